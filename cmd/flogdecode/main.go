@@ -0,0 +1,117 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Command flogdecode converts log records written with the "cbor" logging
+// format back to the console, dev, JSON, or logfmt formats.
+//
+// Usage:
+//
+//	flogdecode [-format console|dev|json|logfmt] [-spec format] [-color] [file ...]
+//
+// Records are read from the named files in order or from standard input when
+// no files are provided.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/mattn/go-isatty"
+	"github.com/pkg/errors"
+	"github.com/redresseur/flogging"
+	"github.com/redresseur/flogging/fabenc"
+	zaplogfmt "github.com/sykesm/zap-logfmt"
+	"go.uber.org/zap/zapcore"
+)
+
+func main() {
+	format := flag.String("format", "console", "output format: console, dev, json, or logfmt")
+	spec := flag.String("spec", flogging.DefaultFormat, "format specifier for the console and dev formats")
+	color := flag.Bool("color", isatty.IsTerminal(os.Stdout.Fd()) && os.Getenv("NO_COLOR") == "", "emit color escapes")
+	flag.Parse()
+
+	encoder, err := newEncoder(*format, *spec, *color)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "flogdecode: %s\n", err)
+		os.Exit(2)
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+
+	if flag.NArg() == 0 {
+		err = decode(out, os.Stdin, encoder)
+	}
+	for _, name := range flag.Args() {
+		if err = decodeFile(out, name, encoder); err != nil {
+			break
+		}
+	}
+	if err != nil {
+		out.Flush()
+		fmt.Fprintf(os.Stderr, "flogdecode: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func newEncoder(format, spec string, color bool) (*fabenc.RecordEncoder, error) {
+	switch format {
+	case "json":
+		return fabenc.NewRecordEncoder(zapcore.NewJSONEncoder(flogging.NewDefaultEncoderConfig())), nil
+	case "logfmt":
+		return fabenc.NewRecordEncoder(zaplogfmt.NewEncoder(flogging.NewDefaultEncoderConfig())), nil
+	case "console", "dev":
+		formatters, err := fabenc.ParseFormat(spec)
+		if err != nil {
+			return nil, err
+		}
+		if !color {
+			formatters = fabenc.DisableColor(formatters)
+		}
+		if format == "dev" {
+			return fabenc.NewRecordEncoder(fabenc.NewDevEncoder(fabenc.NewDevOptions("", color), formatters...)), nil
+		}
+		return fabenc.NewRecordEncoder(fabenc.NewFormatEncoder(formatters...)), nil
+	default:
+		return nil, errors.Errorf("unknown output format: %s", format)
+	}
+}
+
+func decodeFile(w io.Writer, name string, encoder *fabenc.RecordEncoder) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return errors.WithMessage(decode(w, f, encoder), name)
+}
+
+func decode(w io.Writer, r io.Reader, encoder *fabenc.RecordEncoder) error {
+	dec := fabenc.NewCBORDecoder(r)
+	for {
+		rec, err := dec.Decode()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		buf, err := encoder.Encode(rec)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(buf.Bytes())
+		buf.Free()
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,61 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"bytes"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/redresseur/flogging"
+	"github.com/redresseur/flogging/fabenc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// encodeEntries encodes the entries with the encoder after resetting the
+// global sequence number.
+func encodeEntries(t *testing.T, enc zapcore.Encoder, entries []zapcore.Entry) *bytes.Buffer {
+	fabenc.SetSequence(0)
+	out := &bytes.Buffer{}
+	for _, e := range entries {
+		buf, err := enc.EncodeEntry(e, []zapcore.Field{zap.String("channel", "mychannel")})
+		require.NoError(t, err)
+		out.Write(buf.Bytes())
+		buf.Free()
+	}
+	return out
+}
+
+func TestDecodeMatchesConsole(t *testing.T) {
+	pc, file, line, ok := runtime.Caller(0)
+	caller := zapcore.NewEntryCaller(pc, file, line, ok)
+	when := time.Date(2020, time.February, 10, 11, 12, 13, 0, time.Local)
+	entries := []zapcore.Entry{
+		{Level: zapcore.InfoLevel, Time: when, LoggerName: "peer.gossip", Message: "first", Caller: caller},
+		{Level: zapcore.WarnLevel, Time: when.Add(time.Second), LoggerName: "peer.ledger", Message: "second", Caller: caller},
+		{Level: flogging.PayloadLevel, Time: when.Add(2 * time.Second), LoggerName: "peer", Message: "third", Caller: caller},
+	}
+
+	formatters, err := fabenc.ParseFormat(flogging.DefaultFormat)
+	require.NoError(t, err)
+	console := encodeEntries(t, fabenc.NewFormatEncoder(fabenc.DisableColor(formatters)...), entries)
+	records := encodeEntries(t, fabenc.NewCBOREncoder(), entries)
+
+	encoder, err := newEncoder("console", flogging.DefaultFormat, false)
+	require.NoError(t, err)
+	fabenc.SetSequence(100)
+	decoded := &bytes.Buffer{}
+	err = decode(decoded, records, encoder)
+	require.NoError(t, err)
+
+	assert.Equal(t, console.String(), decoded.String())
+	assert.Contains(t, decoded.String(), " 003 third")
+}
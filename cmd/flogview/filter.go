@@ -0,0 +1,236 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/redresseur/flogging"
+	"github.com/redresseur/flogging/fabenc"
+	"go.uber.org/zap/zapcore"
+)
+
+// A filter selects the records that are written by flogview.
+type filter struct {
+	level   zapcore.Level
+	loggers []string
+	since   time.Time
+	until   time.Time
+	fields  []fieldMatcher
+}
+
+// newFilter creates a filter that selects every record.
+func newFilter() *filter {
+	return &filter{level: flogging.PayloadLevel}
+}
+
+// A fieldMatcher matches the value of a structured field. The value is
+// compared with its text representation.
+type fieldMatcher struct {
+	key    string
+	value  string
+	regexp *regexp.Regexp
+}
+
+// newFieldMatcher parses a key=value or key~regexp field expression.
+func newFieldMatcher(expr string) (fieldMatcher, error) {
+	idx := strings.IndexAny(expr, "=~")
+	if idx <= 0 {
+		return fieldMatcher{}, errors.Errorf("invalid field expression: %s", expr)
+	}
+	m := fieldMatcher{key: expr[:idx], value: expr[idx+1:]}
+	if expr[idx] == '~' {
+		re, err := regexp.Compile(m.value)
+		if err != nil {
+			return fieldMatcher{}, errors.WithMessage(err, "invalid field expression")
+		}
+		m.regexp = re
+	}
+	return m, nil
+}
+
+func (m fieldMatcher) match(fields map[string]string) bool {
+	v, ok := fields[m.key]
+	if !ok {
+		return false
+	}
+	if m.regexp != nil {
+		return m.regexp.MatchString(v)
+	}
+	return v == m.value
+}
+
+// parseFilterTime parses a time in RFC 3339 format, a local time in the
+// "2006-01-02 15:04:05" format, or a duration relative to now.
+func parseFilterTime(s string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.Errorf("invalid time: %s", s)
+}
+
+// Match returns true when the record satisfies the filter.
+func (f *filter) Match(rec *fabenc.Record) bool {
+	if rec.Entry.Level < f.level {
+		return false
+	}
+	if len(f.loggers) > 0 && !f.matchLogger(rec.Entry.LoggerName) {
+		return false
+	}
+	if !f.since.IsZero() && rec.Entry.Time.Before(f.since) {
+		return false
+	}
+	if !f.until.IsZero() && rec.Entry.Time.After(f.until) {
+		return false
+	}
+	if len(f.fields) > 0 {
+		fields := fieldValues(rec.Fields)
+		for _, m := range f.fields {
+			if !m.match(fields) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// matchLogger determines whether the logger name is one of the loggers or a
+// descendant of one of them.
+func (f *filter) matchLogger(name string) bool {
+	for _, prefix := range f.loggers {
+		if name == prefix || strings.HasPrefix(name, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldValues returns the text representation of the field values.
+func fieldValues(fields []zapcore.Field) map[string]string {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+
+	values := make(map[string]string, len(enc.Fields))
+	for k, v := range enc.Fields {
+		values[k] = valueText(v)
+	}
+	return values
+}
+
+// fieldText returns the text representation of a field value.
+func fieldText(f zapcore.Field) string {
+	enc := zapcore.NewMapObjectEncoder()
+	f.AddTo(enc)
+	return valueText(enc.Fields[f.Key])
+}
+
+func valueText(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case json.RawMessage:
+		return string(v)
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// levelFlag adapts a zapcore.Level to the flag.Value interface using the
+// level names accepted by flogging.
+type levelFlag struct{ level *zapcore.Level }
+
+func (l levelFlag) String() string {
+	switch {
+	case l.level == nil:
+		return ""
+	case *l.level == flogging.PayloadLevel:
+		return "payload"
+	default:
+		return l.level.String()
+	}
+}
+
+func (l levelFlag) Set(s string) error {
+	if !flogging.IsValidLevel(s) {
+		return errors.Errorf("invalid log level: %s", s)
+	}
+	*l.level = flogging.NameToLevel(s)
+	return nil
+}
+
+// fieldsFlag adds a fieldMatcher for each field expression.
+type fieldsFlag struct{ fields *[]fieldMatcher }
+
+func (f fieldsFlag) String() string {
+	if f.fields == nil {
+		return ""
+	}
+	var exprs []string
+	for _, m := range *f.fields {
+		op := "="
+		if m.regexp != nil {
+			op = "~"
+		}
+		exprs = append(exprs, m.key+op+m.value)
+	}
+	return strings.Join(exprs, ",")
+}
+
+func (f fieldsFlag) Set(expr string) error {
+	m, err := newFieldMatcher(expr)
+	if err != nil {
+		return err
+	}
+	*f.fields = append(*f.fields, m)
+	return nil
+}
+
+// timeFlag parses a time with parseFilterTime.
+type timeFlag struct {
+	time *time.Time
+	now  time.Time
+}
+
+func (t timeFlag) String() string {
+	if t.time == nil || t.time.IsZero() {
+		return ""
+	}
+	return t.time.Format(time.RFC3339Nano)
+}
+
+func (t timeFlag) Set(s string) error {
+	parsed, err := parseFilterTime(s, t.now)
+	if err != nil {
+		return err
+	}
+	*t.time = parsed
+	return nil
+}
+
+// stringsFlag collects the values of a repeated flag.
+type stringsFlag []string
+
+func (s *stringsFlag) String() string { return strings.Join(*s, ",") }
+
+func (s *stringsFlag) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
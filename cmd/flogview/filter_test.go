@@ -0,0 +1,89 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/redresseur/flogging/fabenc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestFilterMatch(t *testing.T) {
+	now := time.Date(2020, time.February, 10, 12, 0, 0, 0, time.UTC)
+	rec := &fabenc.Record{
+		Entry: zapcore.Entry{
+			Level:      zapcore.WarnLevel,
+			LoggerName: "peer.gossip.comm",
+			Time:       now.Add(-time.Minute),
+		},
+		Fields: []zapcore.Field{
+			zap.String("channel", "mychannel"),
+			zap.Int64("block", 42),
+			zap.Reflect("obj", json.RawMessage(`{"a":1}`)),
+		},
+	}
+
+	var tests = []struct {
+		name    string
+		args    []string
+		matched bool
+	}{
+		{name: "no filter", matched: true},
+		{name: "level below", args: []string{"-level", "info"}, matched: true},
+		{name: "level equal", args: []string{"-level", "WARN"}, matched: true},
+		{name: "level above", args: []string{"-level", "error"}, matched: false},
+		{name: "logger exact", args: []string{"-logger", "peer.gossip.comm"}, matched: true},
+		{name: "logger parent", args: []string{"-logger", "peer"}, matched: true},
+		{name: "logger partial", args: []string{"-logger", "peer.goss"}, matched: false},
+		{name: "logger any", args: []string{"-logger", "orderer", "-logger", "peer.gossip"}, matched: true},
+		{name: "since duration", args: []string{"-since", "5m"}, matched: true},
+		{name: "since later", args: []string{"-since", "30s"}, matched: false},
+		{name: "until time", args: []string{"-until", "2020-02-10T11:58:00Z"}, matched: false},
+		{name: "range", args: []string{"-since", "2020-02-10T11:00:00Z", "-until", "2020-02-10T12:00:00Z"}, matched: true},
+		{name: "field equal", args: []string{"-field", "channel=mychannel"}, matched: true},
+		{name: "field number", args: []string{"-field", "block=42"}, matched: true},
+		{name: "field object", args: []string{"-field", `obj={"a":1}`}, matched: true},
+		{name: "field mismatch", args: []string{"-field", "channel=other"}, matched: false},
+		{name: "field missing", args: []string{"-field", "missing="}, matched: false},
+		{name: "field regexp", args: []string{"-field", "channel~^my"}, matched: true},
+		{name: "all fields", args: []string{"-field", "channel~^my", "-field", "block=43"}, matched: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			opts, err := parseFlags(tc.args, ioutil.Discard, now)
+			require.NoError(t, err)
+			assert.Equal(t, tc.matched, opts.filter.Match(rec))
+		})
+	}
+}
+
+func TestParseFlagsErrors(t *testing.T) {
+	var tests = []struct {
+		args []string
+		err  string
+	}{
+		{args: []string{"-level", "loud"}, err: `invalid value "loud" for flag -level: invalid log level: loud`},
+		{args: []string{"-since", "yesterday"}, err: `invalid value "yesterday" for flag -since: invalid time: yesterday`},
+		{args: []string{"-field", "=value"}, err: `invalid value "=value" for flag -field: invalid field expression: =value`},
+		{args: []string{"-field", "key~("}, err: "invalid value \"key~(\" for flag -field: invalid field expression: error parsing regexp: missing closing ): `(`"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.args[0], func(t *testing.T) {
+			_, err := parseFlags(tc.args, ioutil.Discard, time.Now())
+			assert.EqualError(t, err, tc.err)
+		})
+	}
+}
@@ -0,0 +1,260 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rotatedRegexp matches the names of files rotated by appending a generation
+// number, optionally compressed, such as peer.log.1 or peer.log.2.gz.
+var rotatedRegexp = regexp.MustCompile(`^(.*)\.(\d+)(\.gz)?$`)
+
+// A line is a line of input or the end of an input file.
+type line struct {
+	text string
+	eof  bool
+	err  error
+}
+
+// expandPaths replaces directories with the log files they contain and
+// orders each set of rotated files from the oldest to the newest. The order
+// of unrelated files is preserved.
+func expandPaths(args []string) ([]string, error) {
+	var paths []string
+	for _, arg := range args {
+		fi, err := os.Stat(arg)
+		if err != nil {
+			return nil, err
+		}
+		if !fi.IsDir() {
+			paths = append(paths, arg)
+			continue
+		}
+		files, err := ioutil.ReadDir(arg)
+		if err != nil {
+			return nil, err
+		}
+		var names []string
+		for _, f := range files {
+			if f.Mode().IsRegular() && strings.Contains(f.Name(), ".log") {
+				names = append(names, filepath.Join(arg, f.Name()))
+			}
+		}
+		sort.Strings(names)
+		paths = append(paths, names...)
+	}
+	return orderRotated(paths), nil
+}
+
+// orderRotated groups rotated files with the file they were rotated from.
+// Within a group, files with a higher generation number are older and are
+// placed first.
+func orderRotated(paths []string) []string {
+	type rotated struct {
+		path       string
+		generation int
+	}
+
+	var bases []string
+	groups := map[string][]rotated{}
+	for _, p := range paths {
+		base, generation := strings.TrimSuffix(p, ".gz"), 0
+		if m := rotatedRegexp.FindStringSubmatch(p); m != nil {
+			base = m[1]
+			generation, _ = strconv.Atoi(m[2])
+		}
+		if _, ok := groups[base]; !ok {
+			bases = append(bases, base)
+		}
+		groups[base] = append(groups[base], rotated{path: p, generation: generation})
+	}
+
+	ordered := make([]string, 0, len(paths))
+	for _, base := range bases {
+		group := groups[base]
+		sort.SliceStable(group, func(i, j int) bool { return group[i].generation > group[j].generation })
+		for _, r := range group {
+			ordered = append(ordered, r.path)
+		}
+	}
+	return ordered
+}
+
+// openInput opens a file for reading. Files compressed with gzip are
+// decompressed.
+func openInput(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return decompress(f)
+}
+
+func decompress(rc io.ReadCloser) (io.ReadCloser, error) {
+	br := bufio.NewReader(rc)
+	magic, _ := br.Peek(2)
+	if len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			rc.Close()
+			return nil, err
+		}
+		return readCloser{Reader: zr, close: rc.Close}, nil
+	}
+	return readCloser{Reader: br, close: rc.Close}, nil
+}
+
+type readCloser struct {
+	io.Reader
+	close func() error
+}
+
+func (r readCloser) Close() error { return r.close() }
+
+// readLines sends the lines read from r followed by an end of file marker.
+func readLines(r io.Reader, lines chan<- line) error {
+	br := bufio.NewReader(r)
+	for {
+		s, err := br.ReadString('\n')
+		if s != "" {
+			lines <- line{text: s}
+		}
+		if err == io.EOF {
+			lines <- line{eof: true}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// A follower reads lines appended to a file. When the file is truncated, it
+// is read again from the start and when it is replaced, the new file is
+// read. When a directory is being followed, the follower moves on to newer
+// log files as they are created.
+type follower struct {
+	path     string
+	dir      string
+	interval time.Duration
+	stop     <-chan struct{}
+}
+
+// Follow sends lines until the stop channel is closed.
+func (f *follower) Follow(lines chan<- line) error {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return err
+	}
+	defer func() { file.Close() }()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	var offset int64
+	var partial string
+	br := bufio.NewReader(file)
+	readAvailable := func() error {
+		for {
+			s, err := br.ReadString('\n')
+			offset += int64(len(s))
+			partial += s
+			if err != nil {
+				if err == io.EOF {
+					return nil
+				}
+				return err
+			}
+			lines <- line{text: partial}
+			partial = ""
+		}
+	}
+	open := func(path string) bool {
+		newFile, err := os.Open(path)
+		if err != nil {
+			return false
+		}
+		newInfo, err := newFile.Stat()
+		if err != nil {
+			newFile.Close()
+			return false
+		}
+		if partial != "" {
+			lines <- line{text: partial}
+			partial = ""
+		}
+		lines <- line{eof: true}
+		file.Close()
+		file, info, f.path, offset = newFile, newInfo, path, 0
+		br.Reset(file)
+		return true
+	}
+
+	for {
+		if err := readAvailable(); err != nil {
+			return err
+		}
+
+		select {
+		case <-f.stop:
+			return nil
+		case <-time.After(f.interval):
+		}
+
+		fi, err := os.Stat(f.path)
+		switch {
+		case err == nil && os.SameFile(fi, info) && fi.Size() < offset:
+			// truncated
+			if _, err := file.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+			br.Reset(file)
+			offset, partial = 0, ""
+		case err == nil && os.SameFile(fi, info) && fi.Size() > offset:
+			// appended
+		case err == nil && os.SameFile(fi, info):
+			if newer := f.newer(); newer != "" {
+				open(newer)
+			}
+		default:
+			// replaced or removed
+			if err := readAvailable(); err != nil {
+				return err
+			}
+			path := f.path
+			if newer := f.newer(); newer != "" {
+				path = newer
+			}
+			open(path)
+		}
+	}
+}
+
+// newer returns the newest log file in the directory being followed when it
+// is not the file being read.
+func (f *follower) newer() string {
+	if f.dir == "" {
+		return ""
+	}
+	paths, err := expandPaths([]string{f.dir})
+	if err != nil || len(paths) == 0 || paths[len(paths)-1] == f.path {
+		return ""
+	}
+	return paths[len(paths)-1]
+}
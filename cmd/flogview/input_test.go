@@ -0,0 +1,146 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+}
+
+func writeGzip(t *testing.T, path, content string) {
+	buf := &bytes.Buffer{}
+	zw := gzip.NewWriter(buf)
+	_, err := zw.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	writeFile(t, path, buf.String())
+}
+
+func TestExpandPaths(t *testing.T) {
+	dir, err := ioutil.TempDir("", "flogview")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{
+		"peer.log", "peer.log.1", "peer.log.2.gz", "peer.log.10",
+		"peer2020-02-09_0000.log", "peer2020-02-10_0001.log", "peer2020-02-10_0000.log",
+		"notes.txt",
+	} {
+		writeFile(t, filepath.Join(dir, name), "")
+	}
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "sub.log"), 0755))
+
+	paths, err := expandPaths([]string{dir})
+	require.NoError(t, err)
+	for i := range paths {
+		paths[i] = filepath.Base(paths[i])
+	}
+	assert.Equal(t, []string{
+		"peer.log.10", "peer.log.2.gz", "peer.log.1", "peer.log",
+		"peer2020-02-09_0000.log", "peer2020-02-10_0000.log", "peer2020-02-10_0001.log",
+	}, paths)
+
+	paths, err = expandPaths([]string{filepath.Join(dir, "notes.txt"), filepath.Join(dir, "peer.log"), filepath.Join(dir, "peer.log.1")})
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "notes.txt"), filepath.Join(dir, "peer.log.1"), filepath.Join(dir, "peer.log")}, paths)
+
+	_, err = expandPaths([]string{filepath.Join(dir, "missing.log")})
+	assert.Error(t, err)
+}
+
+func TestOpenInput(t *testing.T) {
+	dir, err := ioutil.TempDir("", "flogview")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeFile(t, filepath.Join(dir, "plain.log"), "plain\n")
+	writeGzip(t, filepath.Join(dir, "compressed.log.1"), "compressed\n")
+
+	for name, expected := range map[string]string{"plain.log": "plain\n", "compressed.log.1": "compressed\n"} {
+		r, err := openInput(filepath.Join(dir, name))
+		require.NoError(t, err)
+		b, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+		assert.NoError(t, r.Close())
+		assert.Equal(t, expected, string(b))
+	}
+
+	writeFile(t, filepath.Join(dir, "corrupt.log.gz"), "\x1f\x8bnot gzip")
+	_, err = openInput(filepath.Join(dir, "corrupt.log.gz"))
+	assert.Error(t, err)
+}
+
+func TestFollower(t *testing.T) {
+	dir, err := ioutil.TempDir("", "flogview")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "peer.log")
+	writeFile(t, path, "one\n")
+
+	stop := make(chan struct{})
+	lines := make(chan line, 16)
+	done := make(chan error, 1)
+	f := &follower{path: path, dir: dir, interval: 10 * time.Millisecond, stop: stop}
+	go func() { done <- f.Follow(lines) }()
+
+	next := func() line {
+		select {
+		case l := <-lines:
+			return l
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for line")
+			return line{}
+		}
+	}
+	appendFile := func(path, content string) {
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+		require.NoError(t, err)
+		_, err = f.WriteString(content)
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+	}
+
+	assert.Equal(t, line{text: "one\n"}, next())
+
+	appendFile(path, "tw")
+	time.Sleep(50 * time.Millisecond)
+	appendFile(path, "o\n")
+	assert.Equal(t, line{text: "two\n"}, next())
+
+	// rotated by renaming
+	require.NoError(t, os.Rename(path, path+".1"))
+	writeFile(t, path, "three\n")
+	assert.Equal(t, line{eof: true}, next())
+	assert.Equal(t, line{text: "three\n"}, next())
+
+	// truncated
+	require.NoError(t, os.Truncate(path, 0))
+	time.Sleep(50 * time.Millisecond)
+	appendFile(path, "four\n")
+	assert.Equal(t, line{text: "four\n"}, next())
+
+	// newer file in the directory
+	writeFile(t, filepath.Join(dir, "peer2.log"), "five\n")
+	assert.Equal(t, line{eof: true}, next())
+	assert.Equal(t, line{text: "five\n"}, next())
+
+	close(stop)
+	assert.NoError(t, <-done)
+}
@@ -0,0 +1,240 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Command flogview reads log records written in the console, JSON, or logfmt
+// formats, selects records by level, logger, time, and field values, and
+// renders them in any of the supported logging formats.
+//
+// Usage:
+//
+//	flogview [flags] [file|dir ...]
+//
+// Records are read from the named files in order or from standard input when
+// no files are provided. Directories are replaced by the log files they
+// contain. Rotated files, such as peer.log.2.gz and peer.log.1, are read
+// from the oldest to the newest and files compressed with gzip are
+// decompressed. Console records are parsed with the format spec provided by
+// -input-spec, which defaults to flogging.DefaultFormat.
+//
+// With -f, the last file or directory is followed after the existing
+// records have been written.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-isatty"
+	"github.com/pkg/errors"
+	"github.com/redresseur/flogging"
+	"github.com/redresseur/flogging/fabenc"
+	zaplogfmt "github.com/sykesm/zap-logfmt"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// pollInterval is how often followed files are checked for new data.
+	pollInterval = 250 * time.Millisecond
+
+	// flushInterval is how long a console record is held waiting for
+	// continuation lines when no other input is available.
+	flushInterval = 100 * time.Millisecond
+)
+
+type options struct {
+	format    string
+	spec      string
+	inputSpec string
+	color     bool
+	follow    bool
+	filter    *filter
+	paths     []string
+}
+
+func main() {
+	opts, err := parseFlags(os.Args[1:], os.Stderr, time.Now())
+	if err == flag.ErrHelp {
+		os.Exit(0)
+	}
+	if err != nil {
+		// the flag set has reported the error
+		os.Exit(2)
+	}
+
+	if err := run(opts, os.Stdin, os.Stdout, nil); err != nil {
+		fmt.Fprintf(os.Stderr, "flogview: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func parseFlags(args []string, output io.Writer, now time.Time) (options, error) {
+	opts := options{filter: newFilter()}
+
+	fs := flag.NewFlagSet("flogview", flag.ContinueOnError)
+	fs.SetOutput(output)
+	fs.StringVar(&opts.format, "format", "console", "output format: console, dev, json, logfmt, ecs, otel, or cbor")
+	fs.StringVar(&opts.spec, "spec", flogging.DefaultFormat, "format specifier for the console and dev formats")
+	fs.StringVar(&opts.inputSpec, "input-spec", flogging.DefaultFormat, "format specifier of console input")
+	fs.BoolVar(&opts.color, "color", isatty.IsTerminal(os.Stdout.Fd()) && os.Getenv("NO_COLOR") == "", "emit color escapes")
+	fs.BoolVar(&opts.follow, "f", false, "follow the last file or directory for new records")
+	fs.Var(levelFlag{level: &opts.filter.level}, "level", "minimum level of the records to display")
+	fs.Var((*stringsFlag)(&opts.filter.loggers), "logger", "display records from the named logger and its descendants (repeatable)")
+	fs.Var(fieldsFlag{fields: &opts.filter.fields}, "field", "display records with a field matching key=value or key~regexp (repeatable)")
+	fs.Var(timeFlag{time: &opts.filter.since, now: now}, "since", "display records created at or after a time or a duration ago")
+	fs.Var(timeFlag{time: &opts.filter.until, now: now}, "until", "display records created at or before a time or a duration ago")
+	if err := fs.Parse(args); err != nil {
+		return options{}, err
+	}
+	opts.paths = fs.Args()
+
+	return opts, nil
+}
+
+func newEncoder(format, spec string, color bool) (*fabenc.RecordEncoder, error) {
+	switch format {
+	case "json":
+		return fabenc.NewRecordEncoder(zapcore.NewJSONEncoder(flogging.NewDefaultEncoderConfig())), nil
+	case "logfmt":
+		return fabenc.NewRecordEncoder(zaplogfmt.NewEncoder(flogging.NewDefaultEncoderConfig())), nil
+	case "ecs":
+		return fabenc.NewRecordEncoder(fabenc.NewECSEncoder(nil)), nil
+	case "otel":
+		return fabenc.NewRecordEncoder(fabenc.NewOTelEncoder(nil)), nil
+	case "cbor":
+		return fabenc.NewRecordEncoder(fabenc.NewCBOREncoder()), nil
+	case "console", "dev":
+		formatters, err := fabenc.ParseFormat(spec)
+		if err != nil {
+			return nil, err
+		}
+		if !color {
+			formatters = fabenc.DisableColor(formatters)
+		}
+		if format == "dev" {
+			return fabenc.NewRecordEncoder(fabenc.NewDevEncoder(fabenc.NewDevOptions("", color), formatters...)), nil
+		}
+		return fabenc.NewRecordEncoder(fabenc.NewFormatEncoder(formatters...)), nil
+	default:
+		return nil, errors.Errorf("unknown output format: %s", format)
+	}
+}
+
+// run writes the selected records to w. When following, run returns after
+// the stop channel has been closed.
+func run(opts options, stdin io.Reader, w io.Writer, stop <-chan struct{}) error {
+	encoder, err := newEncoder(opts.format, opts.spec, opts.color)
+	if err != nil {
+		return err
+	}
+	p, err := newParser(opts.inputSpec, time.Local)
+	if err != nil {
+		return err
+	}
+	paths, err := expandPaths(opts.paths)
+	if err != nil {
+		return err
+	}
+
+	var dir string
+	if n := len(opts.paths); n > 0 {
+		if fi, err := os.Stat(opts.paths[n-1]); err == nil && fi.IsDir() {
+			dir = opts.paths[n-1]
+		}
+	}
+
+	lines := make(chan line, 64)
+	go func() {
+		defer close(lines)
+		if err := readInputs(paths, dir, opts.follow, stdin, lines, stop); err != nil {
+			lines <- line{err: err}
+		}
+	}()
+
+	out := bufio.NewWriter(w)
+	defer out.Flush()
+	emit := func(rec *fabenc.Record) error {
+		if rec == nil || !opts.filter.Match(rec) {
+			return nil
+		}
+		buf, err := encoder.Encode(rec)
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(buf.Bytes())
+		buf.Free()
+		return err
+	}
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	idle := false
+	for {
+		select {
+		case l, ok := <-lines:
+			switch {
+			case !ok:
+				return emit(p.Flush())
+			case l.err != nil:
+				return l.err
+			case l.eof:
+				err = emit(p.Flush())
+			default:
+				err = emit(p.Parse(l.text))
+			}
+			if err != nil {
+				return err
+			}
+			idle = false
+			if len(lines) == 0 {
+				if err := out.Flush(); err != nil {
+					return err
+				}
+			}
+
+		case <-ticker.C:
+			if idle && p.Pending() {
+				if err := emit(p.Flush()); err != nil {
+					return err
+				}
+				if err := out.Flush(); err != nil {
+					return err
+				}
+			}
+			idle = true
+		}
+	}
+}
+
+// readInputs sends the lines of the inputs. When following, the last input
+// is followed until the stop channel is closed.
+func readInputs(paths []string, dir string, follow bool, stdin io.Reader, lines chan<- line, stop <-chan struct{}) error {
+	if len(paths) == 0 {
+		return readLines(stdin, lines)
+	}
+
+	for i, path := range paths {
+		if follow && i == len(paths)-1 && !strings.HasSuffix(path, ".gz") {
+			f := &follower{path: path, dir: dir, interval: pollInterval, stop: stop}
+			return errors.WithMessage(f.Follow(lines), path)
+		}
+
+		r, err := openInput(path)
+		if err != nil {
+			return err
+		}
+		err = readLines(r, lines)
+		r.Close()
+		if err != nil {
+			return errors.WithMessage(err, path)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	consoleLog = "2020-02-10 11:12:13.125 UTC [peer.gossip] handleMessage -> INFO 001 hello channel=mychannel\n" +
+		"2020-02-10 11:12:14.125 UTC [peer.ledger] commit -> DEBU 002 multi\nline\n"
+	jsonLog   = `{"level":"warn","ts":1581333135.125,"name":"peer.gossip","msg":"json","channel":"other"}` + "\n"
+	logfmtLog = `ts=1581333136.125 level=error name=orderer msg=logfmt channel=mychannel` + "\n"
+)
+
+func TestRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "flogview")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeGzip(t, filepath.Join(dir, "peer.log.2.gz"), consoleLog)
+	writeFile(t, filepath.Join(dir, "peer.log.1"), jsonLog)
+	writeFile(t, filepath.Join(dir, "peer.log"), logfmtLog)
+
+	var tests = []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			name: "console",
+			args: []string{"-color=false", "-spec", "[%{module}] %{shortfunc} %{level} %{message}", dir},
+			expected: "[peer.gossip] handleMessage INFO hello channel=mychannel\n" +
+				"[peer.ledger] commit DEBUG multi\nline\n" +
+				"[peer.gossip] (unknown) WARN json channel=other\n" +
+				"[orderer] (unknown) ERROR logfmt channel=mychannel\n",
+		},
+		{
+			name: "json",
+			args: []string{"-format", "json", "-level", "info", "-field", "channel=mychannel", dir},
+			expected: `{"level":"info","ts":1581333133.125,"name":"peer.gossip","msg":"hello","channel":"mychannel"}` + "\n" +
+				`{"level":"error","ts":1581333136.125,"name":"orderer","msg":"logfmt","channel":"mychannel"}` + "\n",
+		},
+		{
+			name: "logfmt",
+			args: []string{"-format", "logfmt", "-logger", "peer", "-until", "2020-02-10T11:12:14.5Z", dir},
+			expected: `ts=1581333133.125 level=info name=peer.gossip msg=hello channel=mychannel` + "\n" +
+				`ts=1581333134.125 level=debug name=peer.ledger msg="multi\nline"` + "\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			opts, err := parseFlags(tc.args, ioutil.Discard, time.Now())
+			require.NoError(t, err)
+
+			buf := &bytes.Buffer{}
+			err = run(opts, nil, buf, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, buf.String())
+		})
+	}
+}
+
+func TestRunStdin(t *testing.T) {
+	opts, err := parseFlags([]string{"-format", "json", "-level", "warn"}, ioutil.Discard, time.Now())
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	err = run(opts, strings.NewReader(consoleLog+jsonLog), buf, nil)
+	require.NoError(t, err)
+	assert.Equal(t, `{"level":"warn","ts":1581333135.125,"name":"peer.gossip","msg":"json","channel":"other"}`+"\n", buf.String())
+}
+
+func TestRunFollow(t *testing.T) {
+	dir, err := ioutil.TempDir("", "flogview")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "peer.log")
+	writeFile(t, path, jsonLog)
+
+	opts, err := parseFlags([]string{"-f", "-format", "logfmt", path}, ioutil.Discard, time.Now())
+	require.NoError(t, err)
+
+	stop := make(chan struct{})
+	w := &syncBuffer{}
+	done := make(chan error, 1)
+	go func() { done <- run(opts, nil, w, stop) }()
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	_, err = f.WriteString(consoleLog)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	// the console record is written once the follower is idle
+	assert.Eventually(t, func() bool { return strings.Count(w.String(), "\n") == 3 }, 5*time.Second, 10*time.Millisecond)
+	assert.Contains(t, w.String(), `msg="multi\nline"`)
+
+	close(stop)
+	assert.NoError(t, <-done)
+}
+
+func TestRunErrors(t *testing.T) {
+	opts, err := parseFlags([]string{"-format", "yaml"}, ioutil.Discard, time.Now())
+	require.NoError(t, err)
+	err = run(opts, nil, ioutil.Discard, nil)
+	assert.EqualError(t, err, "unknown output format: yaml")
+
+	opts, err = parseFlags([]string{"-spec", "%{color:bad}"}, ioutil.Discard, time.Now())
+	require.NoError(t, err)
+	err = run(opts, nil, ioutil.Discard, nil)
+	assert.EqualError(t, err, "invalid color option: bad")
+
+	opts, err = parseFlags([]string{"missing.log"}, ioutil.Discard, time.Now())
+	require.NoError(t, err)
+	err = run(opts, nil, ioutil.Discard, nil)
+	assert.Error(t, err)
+}
+
+type syncBuffer struct {
+	mutex sync.Mutex
+	buf   bytes.Buffer
+}
+
+func (s *syncBuffer) Write(b []byte) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.buf.Write(b)
+}
+
+func (s *syncBuffer) String() string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.buf.String()
+}
+
+func TestRunInputSpec(t *testing.T) {
+	opts, err := parseFlags([]string{"-input-spec", "%{level:-5s} [%{module}] %{message}", "-spec", "%{module} %{level} %{message}", "-color=false", "-logger", "peer"}, ioutil.Discard, time.Now())
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	input := "WARN  [peer.gossip] first key=value\nERROR [orderer] second\n"
+	err = run(opts, strings.NewReader(input), buf, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "peer.gossip WARN first key=value\n", buf.String())
+}
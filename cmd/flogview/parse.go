@@ -0,0 +1,315 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redresseur/flogging"
+	"github.com/redresseur/flogging/fabenc"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+var (
+	escapeRegexp = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+	logfmtRegexp = regexp.MustCompile(`^[^\s="]+=`)
+)
+
+// Keys used by the JSON and logfmt encodings for the entry metadata. The
+// default, ECS, and GCP encoder configuration presets are recognized.
+var (
+	timeKeys    = []string{"ts", "time", "timestamp", "@timestamp"}
+	levelKeys   = []string{"level", "severity", "log.level"}
+	nameKeys    = []string{"name", "logger", "log.logger"}
+	callerKeys  = []string{"caller", "log.origin.file.name"}
+	messageKeys = []string{"msg", "message"}
+	stackKeys   = []string{"stacktrace", "stack_trace", "error.stack_trace"}
+)
+
+// A parser reconstructs log records from lines of console, JSON, or logfmt
+// output. The format is detected for each line.
+//
+// Console messages may span multiple lines so a record is held by the parser
+// until the next record starts or Flush is called.
+type parser struct {
+	console *fabenc.LineParser
+	pending *fabenc.Record
+	text    string // the lines of the pending console record
+}
+
+// newParser creates a parser for console lines written with the format spec.
+func newParser(spec string, location *time.Location) (*parser, error) {
+	console, err := fabenc.NewLineParser(spec)
+	if err != nil {
+		return nil, err
+	}
+	console.Location = location
+	return &parser{console: console}, nil
+}
+
+// Parse consumes a line of input. The record completed by the line, if any,
+// is returned.
+func (p *parser) Parse(line string) *fabenc.Record {
+	line = strings.TrimRight(line, "\r\n")
+	clean := escapeRegexp.ReplaceAllString(line, "")
+
+	if rec, ok := parseJSON(clean); ok {
+		return p.replace(rec, "")
+	}
+	if rec, err := p.console.Parse(clean); err == nil {
+		return p.replace(rec, clean)
+	}
+	if logfmtRegexp.MatchString(clean) {
+		if rec, ok := parseLogfmt(clean); ok {
+			return p.replace(rec, "")
+		}
+	}
+
+	// lines that do not start a record continue a console message
+	if p.pending != nil && p.text != "" {
+		p.text += "\n" + clean
+		return nil
+	}
+	if clean == "" {
+		return nil
+	}
+	return p.replace(&fabenc.Record{Entry: zapcore.Entry{Level: zapcore.InfoLevel, Message: clean}}, "")
+}
+
+// Pending returns true when the parser is holding an incomplete record.
+func (p *parser) Pending() bool {
+	return p.pending != nil
+}
+
+// Flush returns the record held by the parser, if any.
+func (p *parser) Flush() *fabenc.Record {
+	return p.replace(nil, "")
+}
+
+// replace holds rec and returns the record that was pending. The text of a
+// console record is parsed again once all of its lines have been read.
+func (p *parser) replace(rec *fabenc.Record, text string) *fabenc.Record {
+	prev := p.pending
+	if prev != nil && strings.Contains(p.text, "\n") {
+		if full, err := p.console.Parse(p.text); err == nil {
+			prev = full
+		}
+	}
+	p.pending, p.text = rec, text
+	return prev
+}
+
+// parseLevel converts a level name to a zapcore.Level.
+func parseLevel(name string) (zapcore.Level, bool) {
+	if lower := strings.ToLower(name); flogging.IsValidLevel(lower) {
+		return flogging.NameToLevel(lower), true
+	}
+	return zapcore.InfoLevel, false
+}
+
+// parseTime converts a numeric time in seconds since the epoch or a string
+// in one of the layouts used by the encoders.
+func parseTime(s string, location *time.Location) (time.Time, bool) {
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		sec, frac := math.Modf(f)
+		return time.Unix(int64(sec), int64(math.Round(frac*1e9))), true
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999Z0700"} {
+		if t, err := time.ParseInLocation(layout, s, location); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func parseCaller(s string) zapcore.EntryCaller {
+	idx := strings.LastIndex(s, ":")
+	if idx < 0 {
+		return zapcore.EntryCaller{}
+	}
+	line, err := strconv.Atoi(s[idx+1:])
+	if err != nil {
+		return zapcore.EntryCaller{}
+	}
+	return zapcore.EntryCaller{Defined: true, File: s[:idx], Line: line}
+}
+
+// A header collects the entry metadata of a JSON or logfmt record.
+type header struct {
+	time, level, name, caller, message, stack string
+	found                                     bool
+}
+
+// set records the value when the key is a metadata key and reports whether
+// it was consumed. Only the first occurrence of each attribute is used.
+func (h *header) set(key, value string) bool {
+	for _, m := range []struct {
+		keys []string
+		dst  *string
+	}{
+		{timeKeys, &h.time},
+		{levelKeys, &h.level},
+		{nameKeys, &h.name},
+		{callerKeys, &h.caller},
+		{messageKeys, &h.message},
+		{stackKeys, &h.stack},
+	} {
+		if contains(m.keys, key) && *m.dst == "" {
+			*m.dst = value
+			h.found = true
+			return true
+		}
+	}
+	return false
+}
+
+func (h *header) record(fields []zapcore.Field) *fabenc.Record {
+	rec := &fabenc.Record{
+		Entry: zapcore.Entry{
+			Level:      zapcore.InfoLevel,
+			LoggerName: h.name,
+			Message:    h.message,
+			Stack:      h.stack,
+			Caller:     parseCaller(h.caller),
+		},
+		Fields: fields,
+	}
+	if t, ok := parseTime(h.time, time.Local); ok {
+		rec.Entry.Time = t
+	}
+	if l, ok := parseLevel(h.level); ok {
+		rec.Entry.Level = l
+	}
+	return rec
+}
+
+// parseJSON decodes a JSON log record. The order of the fields is
+// preserved.
+func parseJSON(line string) (*fabenc.Record, bool) {
+	if !strings.HasPrefix(line, "{") {
+		return nil, false
+	}
+	dec := json.NewDecoder(strings.NewReader(line))
+	dec.UseNumber()
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return nil, false
+	}
+
+	var h header
+	var fields []zapcore.Field
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, false
+		}
+		key, ok := t.(string)
+		if !ok {
+			return nil, false
+		}
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, false
+		}
+		if s, ok := jsonScalar(raw); ok && h.set(key, s) {
+			continue
+		}
+		fields = append(fields, jsonField(key, raw))
+	}
+	if t, err := dec.Token(); err != nil || t != json.Delim('}') {
+		return nil, false
+	}
+	if dec.More() || !h.found {
+		return nil, false
+	}
+	return h.record(fields), true
+}
+
+// jsonScalar returns the text of a JSON string or number.
+func jsonScalar(raw json.RawMessage) (string, bool) {
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return "", false
+	}
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	default:
+		return "", false
+	}
+}
+
+func jsonField(key string, raw json.RawMessage) zapcore.Field {
+	switch raw[0] {
+	case '"':
+		var s string
+		if json.Unmarshal(raw, &s) == nil {
+			return zap.String(key, s)
+		}
+	case 't', 'f':
+		return zap.Bool(key, raw[0] == 't')
+	case '{', '[', 'n':
+	default:
+		if f, ok := numberField(key, string(raw)); ok {
+			return f
+		}
+	}
+	return zap.Reflect(key, raw)
+}
+
+func numberField(key, s string) (zapcore.Field, bool) {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return zap.Int64(key, i), true
+	}
+	if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return zap.Uint64(key, u), true
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return zap.Float64(key, f), true
+	}
+	return zapcore.Field{}, false
+}
+
+// parseLogfmt decodes a logfmt log record.
+func parseLogfmt(line string) (*fabenc.Record, bool) {
+	parsed, err := fabenc.ParseFields(line)
+	if err != nil {
+		return nil, false
+	}
+
+	var h header
+	var fields []zapcore.Field
+	for _, f := range parsed {
+		if h.set(f.Key, fieldText(f)) {
+			continue
+		}
+		fields = append(fields, f)
+	}
+	if !h.found {
+		return nil, false
+	}
+	return h.record(fields), true
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
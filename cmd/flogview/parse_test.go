@@ -0,0 +1,148 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/redresseur/flogging"
+	"github.com/redresseur/flogging/fabenc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func parseAll(t *testing.T, lines ...string) []*fabenc.Record {
+	p, err := newParser(flogging.DefaultFormat, time.UTC)
+	require.NoError(t, err)
+	var records []*fabenc.Record
+	for _, l := range lines {
+		if rec := p.Parse(l); rec != nil {
+			records = append(records, rec)
+		}
+	}
+	if rec := p.Flush(); rec != nil {
+		records = append(records, rec)
+	}
+	return records
+}
+
+func TestParseConsole(t *testing.T) {
+	records := parseAll(t,
+		"\x1b[34m2020-02-10 11:12:13.125 UTC [peer.gossip] handleMessage -> INFO 001\x1b[0m hello world channel=mych n=3 err=\"bad thing\"\n",
+		"2020-02-10 11:12:13.126 UTC [peer.ledger] commit -> DEBU 002 first line",
+		"second line=not a field",
+		"2020-02-10 11:12:13.127 UTC [orderer] (unknown) -> ERRO 0a3 failed: a=b c",
+	)
+	require.Len(t, records, 3)
+
+	assert.Equal(t, time.Date(2020, time.February, 10, 11, 12, 13, 125000000, time.UTC), records[0].Entry.Time)
+	assert.Equal(t, "peer.gossip", records[0].Entry.LoggerName)
+	assert.Equal(t, "handleMessage", records[0].Function)
+	assert.Equal(t, zapcore.InfoLevel, records[0].Entry.Level)
+	assert.Equal(t, "hello world", records[0].Entry.Message)
+	assert.Equal(t, []zapcore.Field{
+		zap.String("channel", "mych"),
+		zap.Int64("n", 3),
+		zap.String("err", "bad thing"),
+	}, records[0].Fields)
+
+	assert.Equal(t, zapcore.DebugLevel, records[1].Entry.Level)
+	assert.Equal(t, "first line\nsecond line=not a field", records[1].Entry.Message)
+	assert.Empty(t, records[1].Fields)
+
+	assert.Equal(t, zapcore.ErrorLevel, records[2].Entry.Level)
+	assert.Equal(t, "failed: a=b c", records[2].Entry.Message)
+	assert.Empty(t, records[2].Fields)
+}
+
+func TestParseJSON(t *testing.T) {
+	records := parseAll(t,
+		`{"level":"warn","ts":1581333133.125,"name":"peer.gossip","caller":"gossip/gossip.go:42","msg":"hello","n":3,"f":1.5,"ok":true,"obj":{"a":[1,2]},"nil":null,"stacktrace":"stack"}`,
+		`{"log.level":"error","@timestamp":"2020-02-10T11:12:13.125Z","log.logger":"orderer","message":"ecs","big":18446744073709551615}`,
+	)
+	require.Len(t, records, 2)
+
+	rec := records[0]
+	assert.Equal(t, time.Date(2020, time.February, 10, 11, 12, 13, 125000000, time.UTC), rec.Entry.Time.UTC())
+	assert.Equal(t, zapcore.WarnLevel, rec.Entry.Level)
+	assert.Equal(t, "peer.gossip", rec.Entry.LoggerName)
+	assert.Equal(t, "hello", rec.Entry.Message)
+	assert.Equal(t, "stack", rec.Entry.Stack)
+	assert.Equal(t, zapcore.EntryCaller{Defined: true, File: "gossip/gossip.go", Line: 42}, rec.Entry.Caller)
+	assert.Equal(t, []zapcore.Field{
+		zap.Int64("n", 3),
+		zap.Float64("f", 1.5),
+		zap.Bool("ok", true),
+		zap.Reflect("obj", json.RawMessage(`{"a":[1,2]}`)),
+		zap.Reflect("nil", json.RawMessage(`null`)),
+	}, rec.Fields)
+
+	rec = records[1]
+	assert.Equal(t, time.Date(2020, time.February, 10, 11, 12, 13, 125000000, time.UTC), rec.Entry.Time.UTC())
+	assert.Equal(t, zapcore.ErrorLevel, rec.Entry.Level)
+	assert.Equal(t, "orderer", rec.Entry.LoggerName)
+	assert.Equal(t, "ecs", rec.Entry.Message)
+	assert.Equal(t, []zapcore.Field{zap.Uint64("big", 18446744073709551615)}, rec.Fields)
+}
+
+func TestParseLogfmt(t *testing.T) {
+	records := parseAll(t, `ts=1581333133.125 level=info name=peer msg="multi\nline \"message\"" n=-3 flag=false quoted="42"`)
+	require.Len(t, records, 1)
+
+	rec := records[0]
+	assert.Equal(t, time.Date(2020, time.February, 10, 11, 12, 13, 125000000, time.UTC), rec.Entry.Time.UTC())
+	assert.Equal(t, zapcore.InfoLevel, rec.Entry.Level)
+	assert.Equal(t, "peer", rec.Entry.LoggerName)
+	assert.Equal(t, "multi\nline \"message\"", rec.Entry.Message)
+	assert.Equal(t, []zapcore.Field{
+		zap.Int64("n", -3),
+		zap.Bool("flag", false),
+		zap.String("quoted", "42"),
+	}, rec.Fields)
+}
+
+func TestParseUnrecognized(t *testing.T) {
+	records := parseAll(t, "", "panic: runtime error", `{"not":"a record"}`, "key=value")
+	require.Len(t, records, 3)
+	for _, rec := range records {
+		assert.Equal(t, zapcore.InfoLevel, rec.Entry.Level)
+		assert.Empty(t, rec.Fields)
+	}
+	assert.Equal(t, "panic: runtime error", records[0].Entry.Message)
+	assert.Equal(t, `{"not":"a record"}`, records[1].Entry.Message)
+	assert.Equal(t, "key=value", records[2].Entry.Message)
+}
+
+func TestParseLevel(t *testing.T) {
+	var tests = []struct {
+		name  string
+		level zapcore.Level
+		ok    bool
+	}{
+		{"payload", zapcore.DebugLevel - 1, true},
+		{"DEBUG", zapcore.DebugLevel, true},
+		{"Info", zapcore.InfoLevel, true},
+		{"WARNING", zapcore.WarnLevel, true},
+		{"error", zapcore.ErrorLevel, true},
+		{"dpanic", zapcore.DPanicLevel, true},
+		{"PANIC", zapcore.PanicLevel, true},
+		{"fatal", zapcore.FatalLevel, true},
+		{"ERRO", zapcore.InfoLevel, false},
+		{"", zapcore.InfoLevel, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			level, ok := parseLevel(tc.name)
+			assert.Equal(t, tc.level, level)
+			assert.Equal(t, tc.ok, ok)
+		})
+	}
+}
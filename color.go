@@ -0,0 +1,48 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package flogging
+
+import (
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+)
+
+// colorVerbRegexp matches the color verbs of a go-logging format spec.
+var colorVerbRegexp = regexp.MustCompile(`%{color(?::[^}]*)?}`)
+
+// colorEnabled determines whether SGR color escapes should be written to w.
+//
+// A non-empty NO_COLOR environment variable disables color and takes
+// precedence over FORCE_COLOR. A FORCE_COLOR environment variable that is set
+// to anything other than an empty string, "0", or "false" enables color.
+// Otherwise, color is only enabled when w is a terminal.
+func colorEnabled(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	switch strings.ToLower(os.Getenv("FORCE_COLOR")) {
+	case "", "0", "false":
+	default:
+		return true
+	}
+
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// stripColor removes the color verbs from a go-logging format spec.
+func stripColor(format string) string {
+	return colorVerbRegexp.ReplaceAllString(format, "")
+}
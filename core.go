@@ -7,6 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package flogging
 
 import (
+	"sync"
+	"sync/atomic"
+
 	"go.uber.org/zap/zapcore"
 )
 
@@ -16,14 +19,27 @@ const (
 	CONSOLE = iota
 	JSON
 	LOGFMT
+	DEV
+	ECS
+	OTEL
+	CBOR
 )
 
-// EncodingSelector is used to determine whether log records are
-// encoded as JSON or in human readable CONSOLE or LOGFMT formats.
+// EncodingSelector is used to determine whether log records are encoded as
+// JSON, as Elastic Common Schema (ECS) or OpenTelemetry (OTEL) documents, in
+// the binary CBOR format, or in human readable CONSOLE, LOGFMT or DEV formats.
 type EncodingSelector interface {
 	Encoding() Encoding
 }
 
+// An EncoderFactory creates the set of encoders used by a Core. The encoder
+// generation changes whenever the encoders created by the factory would
+// differ from the ones it created before.
+type EncoderFactory interface {
+	EncoderGeneration() uint64
+	NewEncoders() map[Encoding]zapcore.Encoder
+}
+
 // Core is a custom implementation of a zapcore.Core. It's a terrible hack that
 // only exists to work around the intersection of state associated with
 // encoders, implementation hiding in zapcore, and implicit, ad-hoc logger
@@ -44,6 +60,10 @@ type EncodingSelector interface {
 // implementations. The core also references the logging configuration to
 // determine the proper encoding to use, the writer to delegate to, and the
 // enabled levels.
+//
+// When a Factory is provided, the fields added to the core are retained and
+// the encoders are rebuilt from the factory the first time the core is
+// written to after the encoder generation has changed.
 type Core struct {
 	zapcore.LevelEnabler
 	Levels   *LoggerLevels
@@ -51,6 +71,12 @@ type Core struct {
 	Selector EncodingSelector
 	Output   zapcore.WriteSyncer
 	Observer Observer
+	Factory  EncoderFactory
+
+	mutex      sync.RWMutex
+	generation uint64
+	fields     []zapcore.Field
+	entries    *uint64 // the entries written for the logger, if counted
 }
 
 //go:generate counterfeiter -o mock/observer.go -fake-name Observer . Observer
@@ -61,21 +87,33 @@ type Observer interface {
 }
 
 func (c *Core) With(fields []zapcore.Field) zapcore.Core {
+	c.mutex.RLock()
+	encoders, generation := c.Encoders, c.generation
+	c.mutex.RUnlock()
+
 	clones := map[Encoding]zapcore.Encoder{}
-	for name, enc := range c.Encoders {
+	for name, enc := range encoders {
 		clone := enc.Clone()
 		addFields(clone, fields)
 		clones[name] = clone
 	}
 
-	return &Core{
+	core := &Core{
 		LevelEnabler: c.LevelEnabler,
 		Levels:       c.Levels,
 		Encoders:     clones,
 		Selector:     c.Selector,
 		Output:       c.Output,
 		Observer:     c.Observer,
+		Factory:      c.Factory,
+		entries:      c.entries,
+	}
+	if c.Factory != nil {
+		core.generation = generation
+		core.fields = append(c.fields[:len(c.fields):len(c.fields)], fields...)
 	}
+
+	return core
 }
 
 func (c *Core) Check(e zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
@@ -90,8 +128,7 @@ func (c *Core) Check(e zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.Checked
 }
 
 func (c *Core) Write(e zapcore.Entry, fields []zapcore.Field) error {
-	encoding := c.Selector.Encoding()
-	enc := c.Encoders[encoding]
+	enc := c.encoder(c.Selector.Encoding())
 
 	buf, err := enc.EncodeEntry(e, fields)
 	if err != nil {
@@ -103,6 +140,9 @@ func (c *Core) Write(e zapcore.Entry, fields []zapcore.Field) error {
 		return err
 	}
 
+	if c.entries != nil {
+		atomic.AddUint64(c.entries, 1)
+	}
 	if e.Level >= zapcore.PanicLevel {
 		c.Sync()
 	}
@@ -114,6 +154,42 @@ func (c *Core) Write(e zapcore.Entry, fields []zapcore.Field) error {
 	return nil
 }
 
+// counting returns a copy of the core that counts the entries it writes
+// with the counter.
+func (c *Core) counting(entries *uint64) *Core {
+	core := c.With(nil).(*Core)
+	core.entries = entries
+	return core
+}
+
+// encoder returns the encoder for the encoding, rebuilding the encoders when
+// the factory generation has changed.
+func (c *Core) encoder(encoding Encoding) zapcore.Encoder {
+	if c.Factory == nil {
+		return c.Encoders[encoding]
+	}
+
+	generation := c.Factory.EncoderGeneration()
+	c.mutex.RLock()
+	if c.generation == generation {
+		enc := c.Encoders[encoding]
+		c.mutex.RUnlock()
+		return enc
+	}
+	c.mutex.RUnlock()
+
+	encoders := c.Factory.NewEncoders()
+	for _, enc := range encoders {
+		addFields(enc, c.fields)
+	}
+
+	c.mutex.Lock()
+	c.Encoders, c.generation = encoders, generation
+	c.mutex.Unlock()
+
+	return encoders[encoding]
+}
+
 func (c *Core) Sync() error {
 	return c.Output.Sync()
 }
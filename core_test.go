@@ -46,6 +46,49 @@ func TestCoreWith(t *testing.T) {
 	assert.Equal(t, core, decorated)
 }
 
+type encoderFactory struct {
+	generation uint64
+	config     zapcore.EncoderConfig
+}
+
+func (e *encoderFactory) EncoderGeneration() uint64 { return e.generation }
+
+func (e *encoderFactory) NewEncoders() map[flogging.Encoding]zapcore.Encoder {
+	return map[flogging.Encoding]zapcore.Encoder{
+		flogging.CONSOLE: zapcore.NewJSONEncoder(e.config),
+	}
+}
+
+func TestCoreFactory(t *testing.T) {
+	factory := &encoderFactory{config: zapcore.EncoderConfig{MessageKey: "msg"}}
+	output := &sw{}
+	core := &flogging.Core{
+		Encoders: factory.NewEncoders(),
+		Selector: output,
+		Output:   output,
+		Factory:  factory,
+	}
+	decorated := core.With([]zapcore.Field{zap.String("key", "value")})
+
+	entry := zapcore.Entry{Level: zapcore.InfoLevel, Message: "message"}
+	err := decorated.Write(entry, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"msg":"message","key":"value"}`+"\n", output.String())
+	output.Reset()
+
+	// a new generation rebuilds the encoders and restores the fields
+	factory.config = zapcore.EncoderConfig{MessageKey: "message"}
+	factory.generation++
+	err = decorated.Write(entry, []zapcore.Field{zap.Int("n", 1)})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"message":"message","key":"value","n":1}`+"\n", output.String())
+	output.Reset()
+
+	err = core.Write(entry, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"message":"message"}`+"\n", output.String())
+}
+
 func TestCoreCheck(t *testing.T) {
 	var enabledArgs []zapcore.Level
 	levels := &flogging.LoggerLevels{}
@@ -0,0 +1,100 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package flogging
+
+import (
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Names of the encoder configuration presets supported by EncoderConfigPreset.
+const (
+	DefaultEncoderPreset = "default"
+	ECSEncoderPreset     = "ecs"
+	GCPEncoderPreset     = "gcp"
+)
+
+// EncoderConfigPreset returns the encoder configuration associated with the
+// named preset. An empty name refers to the default preset.
+func EncoderConfigPreset(name string) (zapcore.EncoderConfig, error) {
+	switch name {
+	case "", DefaultEncoderPreset:
+		return NewDefaultEncoderConfig(), nil
+	case ECSEncoderPreset:
+		return NewECSEncoderConfig(), nil
+	case GCPEncoderPreset:
+		return NewGCPEncoderConfig(), nil
+	default:
+		return zapcore.EncoderConfig{}, errors.Errorf("unknown encoder preset: %s", name)
+	}
+}
+
+// NewDefaultEncoderConfig returns the zap production encoder configuration
+// with the logger name recorded under the "name" key.
+func NewDefaultEncoderConfig() zapcore.EncoderConfig {
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.NameKey = "name"
+	return encoderConfig
+}
+
+// NewECSEncoderConfig returns an encoder configuration that uses the field
+// names of the Elastic Common Schema for the entry metadata.
+func NewECSEncoderConfig() zapcore.EncoderConfig {
+	return zapcore.EncoderConfig{
+		TimeKey:        "@timestamp",
+		LevelKey:       "log.level",
+		NameKey:        "log.logger",
+		CallerKey:      "log.origin.file.name",
+		MessageKey:     "message",
+		StacktraceKey:  "error.stack_trace",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeLevel:    zapcore.LowercaseLevelEncoder,
+		EncodeTime:     zapcore.RFC3339NanoTimeEncoder,
+		EncodeDuration: zapcore.NanosDurationEncoder,
+		EncodeCaller:   zapcore.ShortCallerEncoder,
+	}
+}
+
+// NewGCPEncoderConfig returns an encoder configuration that produces the
+// structured payload expected by Google Cloud Logging.
+func NewGCPEncoderConfig() zapcore.EncoderConfig {
+	return zapcore.EncoderConfig{
+		TimeKey:        "time",
+		LevelKey:       "severity",
+		NameKey:        "logger",
+		CallerKey:      "caller",
+		MessageKey:     "message",
+		StacktraceKey:  "stack_trace",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeLevel:    GCPLevelEncoder,
+		EncodeTime:     zapcore.RFC3339NanoTimeEncoder,
+		EncodeDuration: zapcore.SecondsDurationEncoder,
+		EncodeCaller:   zapcore.ShortCallerEncoder,
+	}
+}
+
+// GCPLevelEncoder serializes a level to the matching Google Cloud Logging
+// severity name.
+func GCPLevelEncoder(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	switch {
+	case l <= zapcore.DebugLevel:
+		enc.AppendString("DEBUG")
+	case l == zapcore.InfoLevel:
+		enc.AppendString("INFO")
+	case l == zapcore.WarnLevel:
+		enc.AppendString("WARNING")
+	case l == zapcore.ErrorLevel:
+		enc.AppendString("ERROR")
+	case l == zapcore.DPanicLevel:
+		enc.AppendString("CRITICAL")
+	case l == zapcore.PanicLevel:
+		enc.AppendString("ALERT")
+	default:
+		enc.AppendString("EMERGENCY")
+	}
+}
@@ -0,0 +1,135 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package flogging_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/redresseur/flogging"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestEncoderConfigPreset(t *testing.T) {
+	var tests = []struct {
+		name       string
+		timeKey    string
+		levelKey   string
+		nameKey    string
+		messageKey string
+	}{
+		{name: "", timeKey: "ts", levelKey: "level", nameKey: "name", messageKey: "msg"},
+		{name: "default", timeKey: "ts", levelKey: "level", nameKey: "name", messageKey: "msg"},
+		{name: "ecs", timeKey: "@timestamp", levelKey: "log.level", nameKey: "log.logger", messageKey: "message"},
+		{name: "gcp", timeKey: "time", levelKey: "severity", nameKey: "logger", messageKey: "message"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			config, err := flogging.EncoderConfigPreset(tc.name)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.timeKey, config.TimeKey)
+			assert.Equal(t, tc.levelKey, config.LevelKey)
+			assert.Equal(t, tc.nameKey, config.NameKey)
+			assert.Equal(t, tc.messageKey, config.MessageKey)
+		})
+	}
+
+	_, err := flogging.EncoderConfigPreset("bogus")
+	assert.EqualError(t, err, "unknown encoder preset: bogus")
+}
+
+func TestGCPLevelEncoder(t *testing.T) {
+	var tests = []struct {
+		level    zapcore.Level
+		severity string
+	}{
+		{level: flogging.PayloadLevel, severity: "DEBUG"},
+		{level: zapcore.DebugLevel, severity: "DEBUG"},
+		{level: zapcore.InfoLevel, severity: "INFO"},
+		{level: zapcore.WarnLevel, severity: "WARNING"},
+		{level: zapcore.ErrorLevel, severity: "ERROR"},
+		{level: zapcore.DPanicLevel, severity: "CRITICAL"},
+		{level: zapcore.PanicLevel, severity: "ALERT"},
+		{level: zapcore.FatalLevel, severity: "EMERGENCY"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.severity, func(t *testing.T) {
+			enc := zapcore.NewMapObjectEncoder()
+			enc.AddArray("severity", zapcore.ArrayMarshalerFunc(func(ae zapcore.ArrayEncoder) error {
+				flogging.GCPLevelEncoder(tc.level, ae)
+				return nil
+			}))
+			assert.Equal(t, []interface{}{tc.severity}, enc.Fields["severity"])
+		})
+	}
+}
+
+func TestLoggingEncoderConfig(t *testing.T) {
+	config := flogging.NewDefaultEncoderConfig()
+	config.TimeKey = "@timestamp"
+	config.EncodeTime = zapcore.RFC3339NanoTimeEncoder
+	config.LevelKey = "severity"
+	config.NameKey = "logger"
+	config.CallerKey = ""
+
+	buf := &bytes.Buffer{}
+	logging, err := flogging.New(flogging.Config{
+		Format:        "json",
+		EncoderConfig: &config,
+		Writer:        buf,
+	})
+	assert.NoError(t, err)
+
+	logging.Logger("test").Info("message")
+	assert.Regexp(t, `^{"severity":"info","@timestamp":"[^"]+","logger":"test","msg":"message"}\n$`, buf.String())
+}
+
+func TestLoggingEncoderPreset(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logging, err := flogging.New(flogging.Config{
+		Format:        "json",
+		EncoderPreset: flogging.GCPEncoderPreset,
+		Writer:        buf,
+	})
+	assert.NoError(t, err)
+
+	logging.Logger("test").Warn("message")
+	assert.Regexp(t, `^{"severity":"WARNING","time":"[^"]+","logger":"test","caller":"[^"]+","message":"message"}\n$`, buf.String())
+
+	_, err = flogging.New(flogging.Config{EncoderPreset: "bogus"})
+	assert.EqualError(t, err, "unknown encoder preset: bogus")
+
+	config := flogging.NewDefaultEncoderConfig()
+	_, err = flogging.New(flogging.Config{EncoderConfig: &config, EncoderPreset: flogging.ECSEncoderPreset})
+	assert.EqualError(t, err, "encoder config and encoder preset are mutually exclusive")
+}
+
+func TestLoggingSetEncoderConfig(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logging, err := flogging.New(flogging.Config{
+		Format: "json",
+		Writer: buf,
+	})
+	assert.NoError(t, err)
+
+	// loggers created before the change must use the updated configuration
+	logger := logging.Logger("test").With("key", "value")
+	logger.Info("before")
+	assert.Contains(t, buf.String(), `"name":"test"`)
+	buf.Reset()
+
+	config := flogging.NewGCPEncoderConfig()
+	config.TimeKey = ""
+	config.CallerKey = ""
+	logging.SetEncoderConfig(config)
+
+	logger.Info("after")
+	assert.Equal(t, `{"severity":"INFO","logger":"test","message":"after","key":"value"}`+"\n", buf.String())
+}
@@ -0,0 +1,105 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package flogging
+
+import (
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+type entrySubscription struct {
+	fn func(zapcore.Entry, []zapcore.Field)
+}
+
+// SubscribeEntries registers a callback that is invoked with every entry
+// written by the loggers of the logging system while the subscription is
+// active. The fields include those added to the logger with With. Only the
+// entries enabled by the active logging spec are delivered.
+//
+// The callback is invoked by the goroutine that writes the entry and must
+// not block. Entries are only copied to the stream while there is at least
+// one subscription.
+//
+// The returned function cancels the subscription.
+func (s *Logging) SubscribeEntries(fn func(zapcore.Entry, []zapcore.Field)) (cancel func()) {
+	sub := &entrySubscription{fn: fn}
+
+	s.streamMutex.Lock()
+	s.streams = append(s.streams, sub)
+	atomic.StoreInt32(&s.streaming, int32(len(s.streams)))
+	s.streamMutex.Unlock()
+
+	return func() { s.unsubscribeEntries(sub) }
+}
+
+func (s *Logging) unsubscribeEntries(sub *entrySubscription) {
+	s.streamMutex.Lock()
+	defer s.streamMutex.Unlock()
+
+	for i := range s.streams {
+		if s.streams[i] == sub {
+			s.streams = append(s.streams[:i:i], s.streams[i+1:]...)
+			break
+		}
+	}
+	if len(s.streams) == 0 {
+		s.streams = nil
+	}
+	atomic.StoreInt32(&s.streaming, int32(len(s.streams)))
+}
+
+// publishEntry invokes the entry subscriptions.
+func (s *Logging) publishEntry(e zapcore.Entry, fields []zapcore.Field) {
+	s.streamMutex.RLock()
+	streams := s.streams
+	s.streamMutex.RUnlock()
+
+	for _, sub := range streams {
+		sub.fn(e, fields)
+	}
+}
+
+// streamCore is a capture core of the loggers created by a Logging instance.
+// It only accepts entries while entries are subscribed.
+type streamCore struct {
+	logging *Logging
+	fields  []zapcore.Field
+}
+
+func (c *streamCore) Enabled(level zapcore.Level) bool {
+	return c.logging.LoggerLevels.Enabled(level)
+}
+
+func (c *streamCore) With(fields []zapcore.Field) zapcore.Core {
+	return &streamCore{
+		logging: c.logging,
+		fields:  append(c.fields[:len(c.fields):len(c.fields)], fields...),
+	}
+}
+
+func (c *streamCore) Check(e zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if atomic.LoadInt32(&c.logging.streaming) == 0 {
+		return ce
+	}
+	if c.logging.LoggerLevels.Level(e.LoggerName).Enabled(e.Level) {
+		return ce.AddCore(e, c)
+	}
+	return ce
+}
+
+func (c *streamCore) Write(e zapcore.Entry, fields []zapcore.Field) error {
+	if len(c.fields) > 0 {
+		fields = append(c.fields[:len(c.fields):len(c.fields)], fields...)
+	}
+	c.logging.publishEntry(e, fields)
+	return nil
+}
+
+func (c *streamCore) Sync() error {
+	return nil
+}
@@ -0,0 +1,79 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package flogging_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/redresseur/flogging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLoggingSubscribeEntries(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logging, err := flogging.New(flogging.Config{
+		LogSpec: "gossip=debug:info",
+		Writer:  buf,
+	})
+	require.NoError(t, err)
+
+	type streamed struct {
+		logger, message string
+		level           zapcore.Level
+		fields          map[string]interface{}
+	}
+	var entries []streamed
+	record := func(e zapcore.Entry, fields []zapcore.Field) {
+		enc := zapcore.NewMapObjectEncoder()
+		for _, f := range fields {
+			f.AddTo(enc)
+		}
+		entries = append(entries, streamed{e.LoggerName, e.Message, e.Level, enc.Fields})
+	}
+
+	gossip := logging.Logger("gossip").With("peer", "p0")
+	ledger := logging.ZapLogger("ledger")
+
+	gossip.Debug("before")
+	cancel := logging.SubscribeEntries(record)
+	gossip.Debugw("debug", "block", 7)
+	ledger.Debug("dropped")
+	ledger.Info("info", zap.String("channel", "mychannel"))
+	cancel()
+	gossip.Info("after")
+
+	assert.Equal(t, []streamed{
+		{"gossip", "debug", zapcore.DebugLevel, map[string]interface{}{"peer": "p0", "block": int64(7)}},
+		{"ledger", "info", zapcore.InfoLevel, map[string]interface{}{"channel": "mychannel"}},
+	}, entries)
+	assert.Contains(t, buf.String(), "before")
+	assert.Contains(t, buf.String(), "after")
+}
+
+func TestLoggingSubscribeEntriesCancel(t *testing.T) {
+	logging, err := flogging.New(flogging.Config{Writer: &bytes.Buffer{}})
+	require.NoError(t, err)
+
+	var first, second int
+	cancelFirst := logging.SubscribeEntries(func(zapcore.Entry, []zapcore.Field) { first++ })
+	cancelSecond := logging.SubscribeEntries(func(zapcore.Entry, []zapcore.Field) { second++ })
+
+	logger := logging.Logger("stream")
+	logger.Info("both")
+	cancelFirst()
+	logger.Info("second")
+	cancelSecond()
+	cancelSecond()
+	logger.Info("none")
+
+	assert.Equal(t, 1, first)
+	assert.Equal(t, 2, second)
+}
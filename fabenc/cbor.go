@@ -0,0 +1,427 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabenc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"runtime"
+	"sort"
+	"time"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// CBOR major types and simple values used by the CBOREncoder.
+const (
+	cborUint   byte = 0 << 5
+	cborNegint byte = 1 << 5
+	cborBytes  byte = 2 << 5
+	cborText   byte = 3 << 5
+	cborArray  byte = 4 << 5
+	cborMap    byte = 5 << 5
+	cborTag    byte = 6 << 5
+	cborSimple byte = 7 << 5
+
+	cborFalse      byte = cborSimple | 20
+	cborTrue       byte = cborSimple | 21
+	cborNull       byte = cborSimple | 22
+	cborFloat64    byte = cborSimple | 27
+	cborIndefinite byte = 31
+	cborBreak      byte = 0xff
+
+	cborTagEpoch = 1
+)
+
+// Keys of the record header written by the CBOREncoder.
+const (
+	CBORTimeKey     = "ts"
+	CBORLevelKey    = "level"
+	CBORSequenceKey = "seq"
+	CBORLoggerKey   = "logger"
+	CBORFileKey     = "file"
+	CBORLineKey     = "line"
+	CBORFunctionKey = "func"
+	CBORMessageKey  = "msg"
+	CBORStackKey    = "stack"
+)
+
+// A CBOREncoder is a zapcore.Encoder that encodes log records in the Concise
+// Binary Object Representation (RFC 8949). The output is a CBOR sequence
+// where each record is a two element array. The first element is a map with
+// the entry metadata and the second is a map with the structured fields.
+//
+// Times are encoded as epoch timestamps (tag 1), durations as nanoseconds,
+// and levels as zap level numbers. Each record carries the global sequence
+// number that the %{id} verb of the console format would have written.
+type CBOREncoder struct {
+	buf            *buffer.Buffer
+	openNamespaces int
+	pool           buffer.Pool
+}
+
+// NewCBOREncoder creates a new CBOREncoder.
+func NewCBOREncoder() *CBOREncoder {
+	pool := buffer.NewPool()
+	return &CBOREncoder{
+		buf:  pool.Get(),
+		pool: pool,
+	}
+}
+
+// Clone creates a new instance of this encoder with the same fields.
+func (c *CBOREncoder) Clone() zapcore.Encoder {
+	return c.clone()
+}
+
+func (c *CBOREncoder) clone() *CBOREncoder {
+	clone := &CBOREncoder{
+		buf:            c.pool.Get(),
+		openNamespaces: c.openNamespaces,
+		pool:           c.pool,
+	}
+	clone.buf.Write(c.buf.Bytes())
+	return clone
+}
+
+// EncodeEntry encodes a zap log record as a CBOR array.
+func (c *CBOREncoder) EncodeEntry(entry zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	final := c.clone()
+	for i := range fields {
+		fields[i].AddTo(final)
+	}
+	final.closeNamespaces()
+
+	function := ""
+	if entry.Caller.Defined {
+		if f := runtime.FuncForPC(entry.Caller.PC); f != nil {
+			function = f.Name()
+		}
+	}
+
+	count := 4
+	if entry.LoggerName != "" {
+		count++
+	}
+	if entry.Caller.Defined {
+		count += 2
+	}
+	if function != "" {
+		count++
+	}
+	if entry.Stack != "" {
+		count++
+	}
+
+	out := c.pool.Get()
+	header := &CBOREncoder{buf: out}
+	header.writeHead(cborArray, 2)
+	header.writeHead(cborMap, uint64(count))
+	header.writeKey(CBORTimeKey)
+	header.AppendTime(entry.Time)
+	header.writeKey(CBORLevelKey)
+	header.AppendInt8(int8(entry.Level))
+	header.AddUint64(CBORSequenceKey, nextSequence())
+	if entry.LoggerName != "" {
+		header.AddString(CBORLoggerKey, entry.LoggerName)
+	}
+	if entry.Caller.Defined {
+		header.AddString(CBORFileKey, entry.Caller.File)
+		header.AddInt(CBORLineKey, entry.Caller.Line)
+	}
+	if function != "" {
+		header.AddString(CBORFunctionKey, function)
+	}
+	header.AddString(CBORMessageKey, entry.Message)
+	if entry.Stack != "" {
+		header.AddString(CBORStackKey, entry.Stack)
+	}
+
+	out.AppendByte(cborMap | cborIndefinite)
+	out.Write(final.buf.Bytes())
+	out.AppendByte(cborBreak)
+	final.buf.Free()
+
+	return out, nil
+}
+
+func (c *CBOREncoder) closeNamespaces() {
+	for i := 0; i < c.openNamespaces; i++ {
+		c.buf.AppendByte(cborBreak)
+	}
+	c.openNamespaces = 0
+}
+
+// writeHead writes the initial byte and argument of a data item.
+func (c *CBOREncoder) writeHead(major byte, n uint64) {
+	switch {
+	case n < 24:
+		c.buf.AppendByte(major | byte(n))
+	case n <= math.MaxUint8:
+		c.buf.AppendByte(major | 24)
+		c.buf.AppendByte(byte(n))
+	case n <= math.MaxUint16:
+		c.buf.AppendByte(major | 25)
+		var b [2]byte
+		binary.BigEndian.PutUint16(b[:], uint16(n))
+		c.buf.Write(b[:])
+	case n <= math.MaxUint32:
+		c.buf.AppendByte(major | 26)
+		var b [4]byte
+		binary.BigEndian.PutUint32(b[:], uint32(n))
+		c.buf.Write(b[:])
+	default:
+		c.buf.AppendByte(major | 27)
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], n)
+		c.buf.Write(b[:])
+	}
+}
+
+func (c *CBOREncoder) writeKey(key string) {
+	c.AppendString(key)
+}
+
+func (c *CBOREncoder) AddArray(key string, marshaler zapcore.ArrayMarshaler) error {
+	c.writeKey(key)
+	return c.AppendArray(marshaler)
+}
+
+func (c *CBOREncoder) AddObject(key string, marshaler zapcore.ObjectMarshaler) error {
+	c.writeKey(key)
+	return c.AppendObject(marshaler)
+}
+
+func (c *CBOREncoder) AddBinary(key string, value []byte) {
+	c.writeKey(key)
+	c.writeHead(cborBytes, uint64(len(value)))
+	c.buf.Write(value)
+}
+
+func (c *CBOREncoder) AddByteString(key string, value []byte) {
+	c.writeKey(key)
+	c.AppendByteString(value)
+}
+
+func (c *CBOREncoder) AddBool(key string, value bool) {
+	c.writeKey(key)
+	c.AppendBool(value)
+}
+
+func (c *CBOREncoder) AddComplex128(key string, value complex128) {
+	c.writeKey(key)
+	c.AppendComplex128(value)
+}
+
+func (c *CBOREncoder) AddComplex64(key string, value complex64) {
+	c.AddComplex128(key, complex128(value))
+}
+
+func (c *CBOREncoder) AddDuration(key string, value time.Duration) {
+	c.writeKey(key)
+	c.AppendDuration(value)
+}
+
+func (c *CBOREncoder) AddFloat64(key string, value float64) {
+	c.writeKey(key)
+	c.AppendFloat64(value)
+}
+
+func (c *CBOREncoder) AddFloat32(key string, value float32) {
+	c.AddFloat64(key, float64(value))
+}
+
+func (c *CBOREncoder) AddInt(key string, value int)     { c.AddInt64(key, int64(value)) }
+func (c *CBOREncoder) AddInt32(key string, value int32) { c.AddInt64(key, int64(value)) }
+func (c *CBOREncoder) AddInt16(key string, value int16) { c.AddInt64(key, int64(value)) }
+func (c *CBOREncoder) AddInt8(key string, value int8)   { c.AddInt64(key, int64(value)) }
+
+func (c *CBOREncoder) AddInt64(key string, value int64) {
+	c.writeKey(key)
+	c.AppendInt64(value)
+}
+
+func (c *CBOREncoder) AddString(key, value string) {
+	c.writeKey(key)
+	c.AppendString(value)
+}
+
+func (c *CBOREncoder) AddTime(key string, value time.Time) {
+	c.writeKey(key)
+	c.AppendTime(value)
+}
+
+func (c *CBOREncoder) AddUint(key string, value uint)       { c.AddUint64(key, uint64(value)) }
+func (c *CBOREncoder) AddUint32(key string, value uint32)   { c.AddUint64(key, uint64(value)) }
+func (c *CBOREncoder) AddUint16(key string, value uint16)   { c.AddUint64(key, uint64(value)) }
+func (c *CBOREncoder) AddUint8(key string, value uint8)     { c.AddUint64(key, uint64(value)) }
+func (c *CBOREncoder) AddUintptr(key string, value uintptr) { c.AddUint64(key, uint64(value)) }
+
+func (c *CBOREncoder) AddUint64(key string, value uint64) {
+	c.writeKey(key)
+	c.AppendUint64(value)
+}
+
+func (c *CBOREncoder) AddReflected(key string, value interface{}) error {
+	c.writeKey(key)
+	return c.AppendReflected(value)
+}
+
+// OpenNamespace opens a nested map. All subsequent fields are added to the
+// namespace until the enclosing object or record is complete.
+func (c *CBOREncoder) OpenNamespace(key string) {
+	c.writeKey(key)
+	c.buf.AppendByte(cborMap | cborIndefinite)
+	c.openNamespaces++
+}
+
+func (c *CBOREncoder) AppendArray(marshaler zapcore.ArrayMarshaler) error {
+	c.buf.AppendByte(cborArray | cborIndefinite)
+	err := marshaler.MarshalLogArray(c)
+	c.buf.AppendByte(cborBreak)
+	return err
+}
+
+func (c *CBOREncoder) AppendObject(marshaler zapcore.ObjectMarshaler) error {
+	openNamespaces := c.openNamespaces
+	c.openNamespaces = 0
+	c.buf.AppendByte(cborMap | cborIndefinite)
+	err := marshaler.MarshalLogObject(c)
+	c.closeNamespaces()
+	c.buf.AppendByte(cborBreak)
+	c.openNamespaces = openNamespaces
+	return err
+}
+
+func (c *CBOREncoder) AppendBool(value bool) {
+	if value {
+		c.buf.AppendByte(cborTrue)
+	} else {
+		c.buf.AppendByte(cborFalse)
+	}
+}
+
+func (c *CBOREncoder) AppendByteString(value []byte) {
+	c.writeHead(cborText, uint64(len(value)))
+	c.buf.Write(value)
+}
+
+func (c *CBOREncoder) AppendComplex128(value complex128) {
+	c.AppendString(fmt.Sprint(value))
+}
+
+func (c *CBOREncoder) AppendComplex64(value complex64) {
+	c.AppendComplex128(complex128(value))
+}
+
+func (c *CBOREncoder) AppendDuration(value time.Duration) {
+	c.AppendInt64(int64(value))
+}
+
+func (c *CBOREncoder) AppendFloat64(value float64) {
+	c.buf.AppendByte(cborFloat64)
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], math.Float64bits(value))
+	c.buf.Write(b[:])
+}
+
+func (c *CBOREncoder) AppendFloat32(value float32) { c.AppendFloat64(float64(value)) }
+func (c *CBOREncoder) AppendInt(value int)         { c.AppendInt64(int64(value)) }
+func (c *CBOREncoder) AppendInt32(value int32)     { c.AppendInt64(int64(value)) }
+func (c *CBOREncoder) AppendInt16(value int16)     { c.AppendInt64(int64(value)) }
+func (c *CBOREncoder) AppendInt8(value int8)       { c.AppendInt64(int64(value)) }
+
+func (c *CBOREncoder) AppendInt64(value int64) {
+	if value < 0 {
+		c.writeHead(cborNegint, uint64(-(value + 1)))
+		return
+	}
+	c.writeHead(cborUint, uint64(value))
+}
+
+func (c *CBOREncoder) AppendString(value string) {
+	c.writeHead(cborText, uint64(len(value)))
+	c.buf.AppendString(value)
+}
+
+// AppendTime encodes the time as an epoch based date/time (tag 1) with a
+// floating point number of seconds.
+func (c *CBOREncoder) AppendTime(value time.Time) {
+	c.writeHead(cborTag, cborTagEpoch)
+	c.AppendFloat64(float64(value.UnixNano()) / float64(time.Second))
+}
+
+func (c *CBOREncoder) AppendUint(value uint)       { c.AppendUint64(uint64(value)) }
+func (c *CBOREncoder) AppendUint32(value uint32)   { c.AppendUint64(uint64(value)) }
+func (c *CBOREncoder) AppendUint16(value uint16)   { c.AppendUint64(uint64(value)) }
+func (c *CBOREncoder) AppendUint8(value uint8)     { c.AppendUint64(uint64(value)) }
+func (c *CBOREncoder) AppendUintptr(value uintptr) { c.AppendUint64(uint64(value)) }
+
+func (c *CBOREncoder) AppendUint64(value uint64) {
+	c.writeHead(cborUint, value)
+}
+
+// AppendReflected encodes the value by converting its JSON representation to
+// CBOR.
+func (c *CBOREncoder) AppendReflected(value interface{}) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return err
+	}
+	c.appendJSONValue(v)
+	return nil
+}
+
+func (c *CBOREncoder) appendJSONValue(v interface{}) {
+	switch v := v.(type) {
+	case nil:
+		c.buf.AppendByte(cborNull)
+	case bool:
+		c.AppendBool(v)
+	case string:
+		c.AppendString(v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			c.AppendInt64(i)
+		} else if f, err := v.Float64(); err == nil {
+			c.AppendFloat64(f)
+		} else {
+			c.AppendString(v.String())
+		}
+	case []interface{}:
+		c.writeHead(cborArray, uint64(len(v)))
+		for _, e := range v {
+			c.appendJSONValue(e)
+		}
+	case map[string]interface{}:
+		c.writeHead(cborMap, uint64(len(v)))
+		for _, k := range sortedInterfaceKeys(v) {
+			c.AppendString(k)
+			c.appendJSONValue(v[k])
+		}
+	}
+}
+
+// sortedInterfaceKeys returns the keys of the map in lexical order.
+func sortedInterfaceKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,423 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabenc
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// maxCBORLength bounds the length of strings, arrays, and maps accepted by
+// the CBORDecoder to protect against corrupt input.
+const maxCBORLength = 64 * 1024 * 1024
+
+// A CBORDecoder reads log records written by a CBOREncoder.
+type CBORDecoder struct {
+	r *bufio.Reader
+}
+
+// NewCBORDecoder creates a CBORDecoder that reads from r.
+func NewCBORDecoder(r io.Reader) *CBORDecoder {
+	return &CBORDecoder{r: bufio.NewReader(r)}
+}
+
+// Decode reads the next log record. io.EOF is returned when no records
+// remain.
+func (d *CBORDecoder) Decode() (*Record, error) {
+	if _, err := d.r.Peek(1); err != nil {
+		return nil, err
+	}
+
+	v, err := d.decode()
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	items, ok := v.([]interface{})
+	if !ok || len(items) != 2 {
+		return nil, errors.New("cbor: record is not a two element array")
+	}
+	header, ok := items[0].(cborObject)
+	if !ok {
+		return nil, errors.New("cbor: record header is not a map")
+	}
+	fields, ok := items[1].(cborObject)
+	if !ok {
+		return nil, errors.New("cbor: record fields are not a map")
+	}
+
+	record := &Record{}
+	for _, m := range header {
+		switch m.key {
+		case CBORTimeKey:
+			record.Entry.Time, _ = m.value.(time.Time)
+		case CBORLevelKey:
+			if l, ok := m.value.(int64); ok {
+				record.Entry.Level = zapcore.Level(l)
+			}
+		case CBORSequenceKey:
+			if seq, ok := m.value.(int64); ok && seq > 0 {
+				record.Sequence = uint64(seq)
+			}
+		case CBORLoggerKey:
+			record.Entry.LoggerName, _ = m.value.(string)
+		case CBORFileKey:
+			record.Entry.Caller.File, _ = m.value.(string)
+			record.Entry.Caller.Defined = true
+		case CBORLineKey:
+			if l, ok := m.value.(int64); ok {
+				record.Entry.Caller.Line = int(l)
+			}
+		case CBORFunctionKey:
+			record.Function, _ = m.value.(string)
+		case CBORMessageKey:
+			record.Entry.Message, _ = m.value.(string)
+		case CBORStackKey:
+			record.Entry.Stack, _ = m.value.(string)
+		}
+	}
+	for _, m := range fields {
+		record.Fields = append(record.Fields, cborField(m.key, m.value))
+	}
+
+	return record, nil
+}
+
+type cborMember struct {
+	key   string
+	value interface{}
+}
+
+// A cborObject is a decoded CBOR map that preserves the order of its members.
+type cborObject []cborMember
+
+// MarshalLogObject adds the members of the map to the encoder.
+func (o cborObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, m := range o {
+		cborField(m.key, m.value).AddTo(enc)
+	}
+	return nil
+}
+
+// cborItems is a decoded CBOR array.
+type cborItems []interface{}
+
+// MarshalLogArray appends the elements of the array to the encoder.
+func (a cborItems) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, v := range a {
+		switch v := v.(type) {
+		case int64:
+			enc.AppendInt64(v)
+		case uint64:
+			enc.AppendUint64(v)
+		case float64:
+			enc.AppendFloat64(v)
+		case string:
+			enc.AppendString(v)
+		case bool:
+			enc.AppendBool(v)
+		case time.Time:
+			enc.AppendTime(v)
+		case cborObject:
+			if err := enc.AppendObject(v); err != nil {
+				return err
+			}
+		case []interface{}:
+			if err := enc.AppendArray(cborItems(v)); err != nil {
+				return err
+			}
+		default:
+			if err := enc.AppendReflected(v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// cborField converts a decoded value to a zap field.
+func cborField(key string, v interface{}) zapcore.Field {
+	switch v := v.(type) {
+	case int64:
+		return zap.Int64(key, v)
+	case uint64:
+		return zap.Uint64(key, v)
+	case float64:
+		return zap.Float64(key, v)
+	case string:
+		return zap.String(key, v)
+	case bool:
+		return zap.Bool(key, v)
+	case []byte:
+		return zap.Binary(key, v)
+	case time.Time:
+		return zap.Time(key, v)
+	case cborObject:
+		return zap.Object(key, v)
+	case []interface{}:
+		return zap.Array(key, cborItems(v))
+	default:
+		return zap.Reflect(key, v)
+	}
+}
+
+// decode reads a single data item.
+func (d *CBORDecoder) decode() (interface{}, error) {
+	ib, err := d.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	major, info := ib&0xe0, ib&0x1f
+
+	if major == cborSimple {
+		return d.decodeSimple(info)
+	}
+
+	if info == cborIndefinite {
+		return d.decodeIndefinite(major)
+	}
+
+	n, err := d.argument(info)
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case cborUint:
+		if n <= math.MaxInt64 {
+			return int64(n), nil
+		}
+		return n, nil
+	case cborNegint:
+		if n <= math.MaxInt64 {
+			return -1 - int64(n), nil
+		}
+		return -1 - float64(n), nil
+	case cborBytes:
+		return d.readBytes(n)
+	case cborText:
+		b, err := d.readBytes(n)
+		return string(b), err
+	case cborArray:
+		if n > maxCBORLength {
+			return nil, errors.Errorf("cbor: array length %d exceeds limit", n)
+		}
+		items := make([]interface{}, 0, n)
+		for i := uint64(0); i < n; i++ {
+			v, err := d.decode()
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, v)
+		}
+		return items, nil
+	case cborMap:
+		if n > maxCBORLength {
+			return nil, errors.Errorf("cbor: map length %d exceeds limit", n)
+		}
+		obj := make(cborObject, 0, n)
+		for i := uint64(0); i < n; i++ {
+			m, err := d.decodeMember()
+			if err != nil {
+				return nil, err
+			}
+			obj = append(obj, m)
+		}
+		return obj, nil
+	default: // cborTag
+		v, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		if n == cborTagEpoch {
+			switch t := v.(type) {
+			case float64:
+				sec, frac := math.Modf(t)
+				return time.Unix(int64(sec), int64(frac*float64(time.Second))), nil
+			case int64:
+				return time.Unix(t, 0), nil
+			}
+		}
+		return v, nil
+	}
+}
+
+func (d *CBORDecoder) decodeIndefinite(major byte) (interface{}, error) {
+	switch major {
+	case cborBytes, cborText:
+		var chunks []byte
+		for {
+			if ok, err := d.readBreak(); err != nil || ok {
+				if major == cborText {
+					return string(chunks), err
+				}
+				return chunks, err
+			}
+			v, err := d.decode()
+			if err != nil {
+				return nil, err
+			}
+			switch c := v.(type) {
+			case []byte:
+				chunks = append(chunks, c...)
+			case string:
+				chunks = append(chunks, c...)
+			default:
+				return nil, errors.New("cbor: invalid chunk in indefinite length string")
+			}
+		}
+	case cborArray:
+		var items []interface{}
+		for {
+			if ok, err := d.readBreak(); err != nil || ok {
+				return items, err
+			}
+			v, err := d.decode()
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, v)
+		}
+	case cborMap:
+		obj := cborObject{}
+		for {
+			if ok, err := d.readBreak(); err != nil || ok {
+				return obj, err
+			}
+			m, err := d.decodeMember()
+			if err != nil {
+				return nil, err
+			}
+			obj = append(obj, m)
+		}
+	default:
+		return nil, errors.Errorf("cbor: invalid indefinite length item of major type %d", major>>5)
+	}
+}
+
+func (d *CBORDecoder) decodeMember() (cborMember, error) {
+	k, err := d.decode()
+	if err != nil {
+		return cborMember{}, err
+	}
+	v, err := d.decode()
+	if err != nil {
+		return cborMember{}, err
+	}
+	key, ok := k.(string)
+	if !ok {
+		key = fmt.Sprint(k)
+	}
+	return cborMember{key: key, value: v}, nil
+}
+
+func (d *CBORDecoder) decodeSimple(info byte) (interface{}, error) {
+	switch info {
+	case 20:
+		return false, nil
+	case 21:
+		return true, nil
+	case 22, 23: // null, undefined
+		return nil, nil
+	case 25:
+		b, err := d.readBytes(2)
+		if err != nil {
+			return nil, err
+		}
+		return halfToFloat64(binary.BigEndian.Uint16(b)), nil
+	case 26:
+		b, err := d.readBytes(4)
+		if err != nil {
+			return nil, err
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), nil
+	case 27:
+		b, err := d.readBytes(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+	default:
+		return nil, errors.Errorf("cbor: unsupported simple value %d", info)
+	}
+}
+
+// readBreak consumes the next byte if it is a break code.
+func (d *CBORDecoder) readBreak() (bool, error) {
+	b, err := d.r.Peek(1)
+	if err != nil {
+		return false, err
+	}
+	if b[0] != cborBreak {
+		return false, nil
+	}
+	_, err = d.r.ReadByte()
+	return true, err
+}
+
+// argument reads the argument of a data item from the additional
+// information and the bytes that follow the initial byte.
+func (d *CBORDecoder) argument(info byte) (uint64, error) {
+	switch {
+	case info < 24:
+		return uint64(info), nil
+	case info <= 27:
+		b, err := d.readBytes(1 << (info - 24))
+		if err != nil {
+			return 0, err
+		}
+		var n uint64
+		for _, c := range b {
+			n = n<<8 | uint64(c)
+		}
+		return n, nil
+	default:
+		return 0, errors.Errorf("cbor: invalid additional information %d", info)
+	}
+}
+
+func (d *CBORDecoder) readBytes(n uint64) ([]byte, error) {
+	if n > maxCBORLength {
+		return nil, errors.Errorf("cbor: length %d exceeds limit", n)
+	}
+	b := make([]byte, n)
+	_, err := io.ReadFull(d.r, b)
+	return b, err
+}
+
+// halfToFloat64 converts an IEEE 754 half precision number to a float64.
+func halfToFloat64(h uint16) float64 {
+	sign := 1.0
+	if h&0x8000 != 0 {
+		sign = -1.0
+	}
+	exp := int(h>>10) & 0x1f
+	mant := float64(h & 0x3ff)
+
+	switch exp {
+	case 0:
+		return sign * math.Ldexp(mant, -24)
+	case 0x1f:
+		if mant == 0 {
+			return math.Inf(int(sign))
+		}
+		return math.NaN()
+	default:
+		return sign * math.Ldexp(mant+1024, exp-25)
+	}
+}
@@ -0,0 +1,218 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabenc_test
+
+import (
+	"bytes"
+	"io"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/redresseur/flogging/fabenc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type user struct {
+	Name  string
+	Roles []string
+}
+
+func (u user) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("name", u.Name)
+	return enc.AddArray("roles", zapcore.ArrayMarshalerFunc(func(ae zapcore.ArrayEncoder) error {
+		for _, r := range u.Roles {
+			ae.AppendString(r)
+		}
+		return nil
+	}))
+}
+
+func TestCBORRoundTrip(t *testing.T) {
+	ts := time.Date(2020, time.February, 10, 11, 12, 13, 125000000, time.UTC)
+	pc, file, line, ok := runtime.Caller(0)
+	entry := zapcore.Entry{
+		Level:      zapcore.WarnLevel,
+		Time:       ts,
+		LoggerName: "peer.gossip",
+		Message:    "message",
+		Caller:     zapcore.NewEntryCaller(pc, file, line, ok),
+		Stack:      "stack",
+	}
+
+	fabenc.SetSequence(41)
+	enc := fabenc.NewCBOREncoder()
+	enc.AddString("channel", "mychannel")
+	buf, err := enc.EncodeEntry(entry, []zapcore.Field{
+		zap.Int("positive", 300),
+		zap.Int64("negative", -70000),
+		zap.Uint64("large", 1<<63),
+		zap.Float64("float", 1.5),
+		zap.Bool("bool", true),
+		zap.Duration("duration", time.Second),
+		zap.Time("time", ts),
+		zap.Binary("binary", []byte{1, 2, 3}),
+		zap.Object("user", user{Name: "alice", Roles: []string{"admin", "peer"}}),
+		zap.Reflect("reflected", map[string]interface{}{"b": []int{1, 2}, "a": nil}),
+		zap.Namespace("ns"),
+		zap.String("inner", "value"),
+	})
+	require.NoError(t, err)
+
+	dec := fabenc.NewCBORDecoder(bytes.NewReader(buf.Bytes()))
+	rec, err := dec.Decode()
+	require.NoError(t, err)
+	assert.Equal(t, ts, rec.Entry.Time.UTC())
+	assert.Equal(t, zapcore.WarnLevel, rec.Entry.Level)
+	assert.Equal(t, "peer.gossip", rec.Entry.LoggerName)
+	assert.Equal(t, "message", rec.Entry.Message)
+	assert.Equal(t, "stack", rec.Entry.Stack)
+	assert.Equal(t, file, rec.Entry.Caller.File)
+	assert.Equal(t, line, rec.Entry.Caller.Line)
+	assert.Equal(t, "github.com/redresseur/flogging/fabenc_test.TestCBORRoundTrip", rec.Function)
+	assert.Equal(t, uint64(42), rec.Sequence)
+
+	_, err = dec.Decode()
+	assert.Equal(t, io.EOF, err)
+
+	jsonEncoder := zapcore.NewJSONEncoder(zapcore.EncoderConfig{
+		EncodeTime:     zapcore.RFC3339NanoTimeEncoder,
+		EncodeDuration: zapcore.NanosDurationEncoder,
+	})
+	out, err := fabenc.NewRecordEncoder(jsonEncoder).Encode(rec)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"channel": "mychannel",
+		"positive": 300,
+		"negative": -70000,
+		"large": 9223372036854775808,
+		"float": 1.5,
+		"bool": true,
+		"duration": 1000000000,
+		"time": "2020-02-10T11:12:13.125Z",
+		"binary": "AQID",
+		"user": {"name": "alice", "roles": ["admin", "peer"]},
+		"reflected": {"a": null, "b": [1, 2]},
+		"ns": {"inner": "value"}
+	}`, out.String())
+}
+
+func TestCBORRecordEncoderConsole(t *testing.T) {
+	pc, file, line, ok := runtime.Caller(0)
+	entry := zapcore.Entry{
+		Level:      zapcore.InfoLevel,
+		LoggerName: "logger",
+		Message:    "message",
+		Caller:     zapcore.NewEntryCaller(pc, file, line, ok),
+	}
+
+	buf := &bytes.Buffer{}
+	enc := fabenc.NewCBOREncoder()
+	for _, msg := range []string{"first", "second"} {
+		entry.Message = msg
+		b, err := enc.EncodeEntry(entry, []zapcore.Field{zap.String("key", "value")})
+		require.NoError(t, err)
+		buf.Write(b.Bytes())
+	}
+
+	formatters, err := fabenc.ParseFormat("[%{module}] %{shortfunc} -> %{level} %{message}")
+	require.NoError(t, err)
+	recordEncoder := fabenc.NewRecordEncoder(fabenc.NewFormatEncoder(fabenc.NewMultiFormatter(formatters...)))
+
+	out := &bytes.Buffer{}
+	dec := fabenc.NewCBORDecoder(buf)
+	for {
+		rec, err := dec.Decode()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		b, err := recordEncoder.Encode(rec)
+		require.NoError(t, err)
+		out.Write(b.Bytes())
+	}
+
+	assert.Equal(t, "[logger] TestCBORRecordEncoderConsole -> INFO first key=value\n"+
+		"[logger] TestCBORRecordEncoderConsole -> INFO second key=value\n", out.String())
+}
+
+func TestCBORDecodeValues(t *testing.T) {
+	var tests = []struct {
+		name     string
+		fields   []byte
+		expected string
+	}{
+		{name: "half float", fields: []byte{0x61, 'f', 0xf9, 0x3e, 0x00}, expected: `{"f":1.5}`},
+		{name: "single float", fields: []byte{0x61, 'f', 0xfa, 0x3f, 0xc0, 0x00, 0x00}, expected: `{"f":1.5}`},
+		{name: "null", fields: []byte{0x61, 'n', 0xf6}, expected: `{"n":null}`},
+		{name: "indefinite text", fields: []byte{0x61, 's', 0x7f, 0x62, 'a', 'b', 0x61, 'c', 0xff}, expected: `{"s":"abc"}`},
+		{name: "definite array", fields: []byte{0x61, 'a', 0x82, 0x01, 0x20}, expected: `{"a":[1,-1]}`},
+		{name: "integer epoch", fields: []byte{0x61, 't', 0xc1, 0x00}, expected: `{"t":"1970-01-01T00:00:00Z"}`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// [ {"msg": "m"}, {_ fields} ]
+			record := append([]byte{0x82, 0xa1, 0x63, 'm', 's', 'g', 0x61, 'm', 0xbf}, tc.fields...)
+			record = append(record, 0xff)
+
+			rec, err := fabenc.NewCBORDecoder(bytes.NewReader(record)).Decode()
+			require.NoError(t, err)
+			assert.Equal(t, "m", rec.Entry.Message)
+
+			enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{EncodeTime: zapcore.RFC3339NanoTimeEncoder})
+			out, err := enc.EncodeEntry(zapcore.Entry{}, rec.Fields)
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.expected, out.String())
+		})
+	}
+}
+
+func TestCBORDecodeErrors(t *testing.T) {
+	var tests = []struct {
+		name   string
+		record []byte
+		err    string
+	}{
+		{name: "not an array", record: []byte{0x01}, err: "cbor: record is not a two element array"},
+		{name: "bad header", record: []byte{0x82, 0x01, 0xa0}, err: "cbor: record header is not a map"},
+		{name: "bad fields", record: []byte{0x82, 0xa0, 0x01}, err: "cbor: record fields are not a map"},
+		{name: "truncated", record: []byte{0x82, 0xa0}, err: io.ErrUnexpectedEOF.Error()},
+		{name: "invalid info", record: []byte{0x1c}, err: "cbor: invalid additional information 28"},
+		{name: "huge length", record: []byte{0x5b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, err: "cbor: length 18446744073709551615 exceeds limit"},
+		{name: "unsupported simple", record: []byte{0xf0}, err: "cbor: unsupported simple value 16"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := fabenc.NewCBORDecoder(bytes.NewReader(tc.record)).Decode()
+			assert.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestCBOREncoderReflectedError(t *testing.T) {
+	enc := fabenc.NewCBOREncoder()
+	err := enc.AddReflected("bad", func() {})
+	assert.Error(t, err)
+}
+
+func TestCBOREncoderClone(t *testing.T) {
+	enc := fabenc.NewCBOREncoder()
+	enc.AddString("key", "value")
+	clone := enc.Clone()
+	clone.AddString("other", "value")
+
+	b1, err := enc.EncodeEntry(zapcore.Entry{}, nil)
+	require.NoError(t, err)
+	b2, err := clone.EncodeEntry(zapcore.Entry{}, nil)
+	require.NoError(t, err)
+	assert.True(t, len(b2.Bytes()) > len(b1.Bytes()))
+}
@@ -0,0 +1,200 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabenc
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// DevOptions controls how a DevEncoder renders stack traces. A single
+// DevOptions can be shared by multiple encoders and modified at runtime.
+type DevOptions struct {
+	mutex  sync.RWMutex
+	color  bool
+	module string
+}
+
+// NewDevOptions creates DevOptions that highlight stack frames from functions
+// in the provided module.
+func NewDevOptions(module string, color bool) *DevOptions {
+	return &DevOptions{
+		module: module,
+		color:  color,
+	}
+}
+
+// SetColor controls whether SGR escapes are used to highlight stack frames.
+func (d *DevOptions) SetColor(color bool) {
+	d.mutex.Lock()
+	d.color = color
+	d.mutex.Unlock()
+}
+
+// SetModule sets the import path prefix of the functions whose stack frames
+// are highlighted. An empty module disables highlighting.
+func (d *DevOptions) SetModule(module string) {
+	d.mutex.Lock()
+	d.module = module
+	d.mutex.Unlock()
+}
+
+func (d *DevOptions) get() (module string, color bool) {
+	if d == nil {
+		return "", false
+	}
+	d.mutex.RLock()
+	module, color = d.module, d.color
+	d.mutex.RUnlock()
+	return module, color
+}
+
+// A DevEncoder is a zapcore.Encoder intended for local development. Log
+// records are rendered as a header line built by the formatters followed by
+// the structured fields, one per line, with aligned keys. When present, the
+// entry stack trace is rendered after the fields.
+type DevEncoder struct {
+	zapcore.Encoder
+	formatters []Formatter
+	options    *DevOptions
+	pool       buffer.Pool
+}
+
+const devIndent = "    "
+
+// NewDevEncoder creates a DevEncoder that uses the formatters to render the
+// header line of a log record.
+func NewDevEncoder(options *DevOptions, formatters ...Formatter) *DevEncoder {
+	return &DevEncoder{
+		Encoder: zapcore.NewJSONEncoder(zapcore.EncoderConfig{
+			MessageKey:     "", // disable
+			LevelKey:       "", // disable
+			TimeKey:        "", // disable
+			NameKey:        "", // disable
+			CallerKey:      "", // disable
+			StacktraceKey:  "", // disable
+			LineEnding:     "\n",
+			EncodeDuration: zapcore.StringDurationEncoder,
+			EncodeTime: func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+				enc.AppendString(t.Format("2006-01-02T15:04:05.999Z07:00"))
+			},
+		}),
+		formatters: formatters,
+		options:    options,
+		pool:       buffer.NewPool(),
+	}
+}
+
+// Clone creates a new instance of this encoder with the same configuration.
+func (d *DevEncoder) Clone() zapcore.Encoder {
+	return &DevEncoder{
+		Encoder:    d.Encoder.Clone(),
+		formatters: d.formatters,
+		options:    d.options,
+		pool:       d.pool,
+	}
+}
+
+// EncodeEntry formats a zap log record across multiple lines.
+func (d *DevEncoder) EncodeEntry(entry zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	line := d.pool.Get()
+	for _, f := range d.formatters {
+		f.Format(line, entry, fields)
+	}
+	line.AppendString("\n")
+
+	encodedFields, err := d.Encoder.EncodeEntry(entry, fields)
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := decodeObject(encodedFields.Bytes())
+	encodedFields.Free()
+	if err != nil {
+		return nil, err
+	}
+
+	width := 0
+	for _, kv := range kvs {
+		if len(kv.key) > width {
+			width = len(kv.key)
+		}
+	}
+	for _, kv := range kvs {
+		value := string(kv.value)
+		var s string
+		if err := json.Unmarshal(kv.value, &s); err == nil {
+			value = s
+		}
+
+		line.AppendString(devIndent)
+		line.AppendString(kv.key)
+		line.AppendString(strings.Repeat(" ", width-len(kv.key)))
+		line.AppendString(" = ")
+		line.AppendString(indentLines(value, devIndent+strings.Repeat(" ", width+3)))
+		line.AppendString("\n")
+	}
+
+	if entry.Stack != "" {
+		module, color := d.options.get()
+		line.AppendString(devIndent)
+		line.AppendString("stacktrace:\n")
+		writeStack(line, entry.Stack, module, color)
+	}
+
+	return line, nil
+}
+
+// writeStack renders a zap stack trace. Frames from functions in module are
+// highlighted in bold when color is enabled or marked with a '>' otherwise.
+func writeStack(buf *buffer.Buffer, stack, module string, color bool) {
+	lines := strings.Split(strings.TrimRight(stack, "\n"), "\n")
+	highlight := false
+	for _, l := range lines {
+		// function lines start in the first column and are followed by an
+		// indented file:line location
+		if !strings.HasPrefix(l, "\t") {
+			highlight = inModule(l, module)
+		} else {
+			l = devIndent + strings.TrimPrefix(l, "\t")
+		}
+
+		switch {
+		case highlight && color:
+			buf.AppendString(devIndent + "  ")
+			buf.AppendString(ColorYellow.Bold())
+			buf.AppendString(l)
+			buf.AppendString(ResetColor())
+		case highlight:
+			buf.AppendString(devIndent + "> ")
+			buf.AppendString(l)
+		default:
+			buf.AppendString(devIndent + "  ")
+			buf.AppendString(l)
+		}
+		buf.AppendString("\n")
+	}
+}
+
+// inModule determines whether the fully qualified function name belongs to a
+// package in module.
+func inModule(function, module string) bool {
+	if module == "" || !strings.HasPrefix(function, module) {
+		return false
+	}
+	rest := function[len(module):]
+	return strings.HasPrefix(rest, ".") || strings.HasPrefix(rest, "/")
+}
+
+// indentLines indents all but the first line of a multi-line value.
+func indentLines(s, indent string) string {
+	return strings.Replace(strings.TrimRight(s, "\n"), "\n", "\n"+indent, -1)
+}
@@ -0,0 +1,129 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabenc_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/redresseur/flogging/fabenc"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestDevEncoderEncodeEntry(t *testing.T) {
+	var tests = []struct {
+		name     string
+		fields   []zapcore.Field
+		expected string
+	}{
+		{name: "nil fields", fields: nil, expected: "[logger] message\n"},
+		{
+			name:     "single field",
+			fields:   []zapcore.Field{zap.String("key", "value")},
+			expected: "[logger] message\n    key = value\n",
+		},
+		{
+			name: "aligned fields",
+			fields: []zapcore.Field{
+				zap.String("a", "value"),
+				zap.Int("longer", 42),
+				zap.Duration("duration", time.Second),
+				zap.Strings("list", []string{"x", "y"}),
+			},
+			expected: "[logger] message\n" +
+				"    a        = value\n" +
+				"    longer   = 42\n" +
+				"    duration = 1s\n" +
+				"    list     = [\"x\",\"y\"]\n",
+		},
+		{
+			name:   "multi-line value",
+			fields: []zapcore.Field{zap.String("k", "line1\nline2")},
+			expected: "[logger] message\n" +
+				"    k = line1\n" +
+				"        line2\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			formatters, err := fabenc.ParseFormat("[%{module}] %{message}")
+			assert.NoError(t, err)
+
+			enc := fabenc.NewDevEncoder(nil, formatters...)
+			buf, err := enc.EncodeEntry(zapcore.Entry{LoggerName: "logger", Message: "message"}, tc.fields)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, buf.String())
+		})
+	}
+}
+
+func TestDevEncoderWith(t *testing.T) {
+	enc := fabenc.NewDevEncoder(nil, fabenc.MessageFormatter{FormatVerb: "%s"})
+	clone := enc.Clone()
+	clone.AddString("context", "value")
+
+	buf, err := clone.EncodeEntry(zapcore.Entry{Message: "message"}, []zapcore.Field{zap.Int("n", 1)})
+	assert.NoError(t, err)
+	assert.Equal(t, "message\n    context = value\n    n       = 1\n", buf.String())
+
+	buf, err = enc.EncodeEntry(zapcore.Entry{Message: "message"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "message\n", buf.String())
+}
+
+func TestDevEncoderStack(t *testing.T) {
+	stack := "example.com/app/pkg.Func\n" +
+		"\t/src/app/pkg/file.go:10\n" +
+		"example.com/other.Func\n" +
+		"\t/src/other/file.go:20\n" +
+		"example.com/application.Func\n" +
+		"\t/src/application/file.go:30"
+	entry := zapcore.Entry{Message: "message", Stack: stack}
+
+	options := fabenc.NewDevOptions("example.com/app", false)
+	enc := fabenc.NewDevEncoder(options, fabenc.MessageFormatter{FormatVerb: "%s"})
+	buf, err := enc.EncodeEntry(entry, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "message\n"+
+		"    stacktrace:\n"+
+		"    > example.com/app/pkg.Func\n"+
+		"    >     /src/app/pkg/file.go:10\n"+
+		"      example.com/other.Func\n"+
+		"          /src/other/file.go:20\n"+
+		"      example.com/application.Func\n"+
+		"          /src/application/file.go:30\n",
+		buf.String(),
+	)
+
+	options.SetColor(true)
+	buf, err = enc.EncodeEntry(entry, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "      "+fabenc.ColorYellow.Bold()+"example.com/app/pkg.Func"+fabenc.ResetColor()+"\n")
+	assert.Contains(t, buf.String(), "      example.com/other.Func\n")
+
+	options.SetModule("")
+	buf, err = enc.EncodeEntry(entry, nil)
+	assert.NoError(t, err)
+	assert.NotContains(t, buf.String(), fabenc.ColorYellow.Bold())
+}
+
+func TestDevEncoderFieldsFailed(t *testing.T) {
+	enc := fabenc.NewDevEncoder(nil)
+	enc.Encoder = &brokenEncoder{}
+
+	_, err := enc.EncodeEntry(zapcore.Entry{}, nil)
+	assert.EqualError(t, err, "broken encoder")
+}
+
+func TestDevEncoderClone(t *testing.T) {
+	enc := fabenc.NewDevEncoder(fabenc.NewDevOptions("module", true))
+	cloned := enc.Clone()
+	assert.Equal(t, enc, cloned)
+}
@@ -0,0 +1,112 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabenc
+
+import (
+	"time"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// ECSVersion is the version of the Elastic Common Schema produced by the
+// ECSEncoder.
+const ECSVersion = "1.6.0"
+
+// ecsFieldNames maps structured field keys to their Elastic Common Schema
+// names.
+var ecsFieldNames = map[string]string{
+	"error":    "error.message",
+	"trace_id": "trace.id",
+	"span_id":  "span.id",
+}
+
+// ecsReservedNames are the names of the members populated from the entry.
+var ecsReservedNames = map[string]bool{
+	"@timestamp":           true,
+	"log.level":            true,
+	"message":              true,
+	"ecs.version":          true,
+	"log.logger":           true,
+	"log.origin.file.name": true,
+	"log.origin.file.line": true,
+	"log.origin.function":  true,
+	"error.stack_trace":    true,
+}
+
+// An ECSEncoder is a zapcore.Encoder that encodes log records as JSON
+// documents that conform to the Elastic Common Schema. Structured fields are
+// added to the top level of the document; fields that would replace a member
+// populated from the entry or the resource, such as message, are added to
+// the fields namespace instead, e.g. as fields.message.
+type ECSEncoder struct {
+	*fieldEncoder
+	resource map[string]string
+	pool     buffer.Pool
+}
+
+// NewECSEncoder creates an ECSEncoder. The resource attributes, such as
+// service.name, are added to every log record.
+func NewECSEncoder(resource map[string]string) *ECSEncoder {
+	return &ECSEncoder{
+		fieldEncoder: newFieldEncoder(1, func(key string) (int, string) {
+			if name, ok := ecsFieldNames[key]; ok {
+				key = name
+			}
+			if _, ok := resource[key]; ok || ecsReservedNames[key] {
+				key = fieldNamespace + key
+			}
+			return 0, key
+		}),
+		resource: resource,
+		pool:     buffer.NewPool(),
+	}
+}
+
+// Clone creates a new instance of this encoder with the same configuration.
+func (e *ECSEncoder) Clone() zapcore.Encoder {
+	return &ECSEncoder{
+		fieldEncoder: e.fieldEncoder.clone(),
+		resource:     e.resource,
+		pool:         e.pool,
+	}
+}
+
+// EncodeEntry encodes a zap log record as an ECS document terminated by a
+// newline.
+func (e *ECSEncoder) EncodeEntry(entry zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	line := e.pool.Get()
+	line.AppendByte('{')
+	obj := &objectWriter{buf: line}
+	obj.String("@timestamp", entry.Time.UTC().Format(time.RFC3339Nano))
+	obj.String("log.level", levelName(entry.Level))
+	obj.String("message", entry.Message)
+	obj.String("ecs.version", ECSVersion)
+	if entry.LoggerName != "" {
+		obj.String("log.logger", entry.LoggerName)
+	}
+	if entry.Caller.Defined {
+		obj.String("log.origin.file.name", entry.Caller.File)
+		obj.Int("log.origin.file.line", int64(entry.Caller.Line))
+		if function := callerFunction(entry.Caller); function != "" {
+			obj.String("log.origin.function", function)
+		}
+	}
+	for _, k := range sortedKeys(e.resource) {
+		obj.String(k, e.resource[k])
+	}
+	if err := e.fieldEncoder.with(fields).writeMembers(obj, 0); err != nil {
+		line.Free()
+		return nil, err
+	}
+	if entry.Stack != "" {
+		obj.String("error.stack_trace", entry.Stack)
+	}
+	line.AppendString("}\n")
+
+	return line, nil
+}
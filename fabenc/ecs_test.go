@@ -0,0 +1,122 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabenc_test
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redresseur/flogging/fabenc"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestECSEncoderEncodeEntry(t *testing.T) {
+	ts := time.Date(2020, time.February, 10, 11, 12, 13, 140000000, time.UTC)
+	enc := fabenc.NewECSEncoder(map[string]string{"service.name": "peer", "host.name": "node1"})
+	clone := enc.Clone()
+	clone.AddString("channel", "mychannel")
+
+	buf, err := clone.EncodeEntry(
+		zapcore.Entry{
+			Level:      zapcore.WarnLevel,
+			Time:       ts,
+			LoggerName: "gossip",
+			Message:    "a \"quoted\" message",
+			Stack:      "stack",
+		},
+		[]zapcore.Field{
+			zap.Int("count", 3),
+			zap.Error(errors.New("boom")),
+			zap.String("trace_id", "0af7651916cd43dd8448eb211c80319c"),
+		},
+	)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"@timestamp": "2020-02-10T11:12:13.14Z",
+		"log.level": "warn",
+		"log.logger": "gossip",
+		"message": "a \"quoted\" message",
+		"ecs.version": "1.6.0",
+		"host.name": "node1",
+		"service.name": "peer",
+		"channel": "mychannel",
+		"count": 3,
+		"error.message": "boom",
+		"trace.id": "0af7651916cd43dd8448eb211c80319c",
+		"error.stack_trace": "stack"
+	}`, buf.String())
+	assert.Equal(t, byte('\n'), buf.Bytes()[buf.Len()-1])
+}
+
+const payloadLevel = zapcore.DebugLevel - 1
+
+func TestECSEncoderCaller(t *testing.T) {
+	pc, file, line, ok := runtime.Caller(0)
+	enc := fabenc.NewECSEncoder(nil)
+	buf, err := enc.EncodeEntry(
+		zapcore.Entry{
+			Level:  payloadLevel,
+			Caller: zapcore.NewEntryCaller(pc, file, line, ok),
+		},
+		nil,
+	)
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), `"log.level":"payload"`)
+	assert.Contains(t, buf.String(), `"log.origin.file.name":"`+file+`"`)
+	assert.Contains(t, buf.String(), `"log.origin.function":"github.com/redresseur/flogging/fabenc_test.TestECSEncoderCaller"`)
+}
+
+func TestECSEncoderReservedFields(t *testing.T) {
+	ts := time.Date(2020, time.February, 10, 11, 12, 13, 0, time.UTC)
+	enc := fabenc.NewECSEncoder(map[string]string{"service.name": "peer"})
+	clone := enc.Clone()
+	clone.AddString("message", "context")
+
+	buf, err := clone.EncodeEntry(
+		zapcore.Entry{Level: zapcore.InfoLevel, Time: ts, Message: "msg"},
+		[]zapcore.Field{
+			zap.String("log.level", "debug"),
+			zap.Int("@timestamp", 1),
+			zap.String("service.name", "orderer"),
+			zap.Namespace("nested"),
+			zap.String("message", "kept"),
+		},
+	)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"@timestamp": "2020-02-10T11:12:13Z",
+		"log.level": "info",
+		"message": "msg",
+		"ecs.version": "1.6.0",
+		"service.name": "peer",
+		"fields.message": "context",
+		"fields.log.level": "debug",
+		"fields.@timestamp": 1,
+		"fields.service.name": "orderer",
+		"nested": {"message": "kept"}
+	}`, buf.String())
+	assert.Equal(t, 1, strings.Count(buf.String(), `"log.level":`))
+}
+
+func TestECSEncoderClone(t *testing.T) {
+	enc := fabenc.NewECSEncoder(map[string]string{"service.name": "peer"})
+	enc.AddString("channel", "mychannel")
+	cloned := enc.Clone()
+	cloned.AddInt("count", 3)
+
+	expected, err := enc.EncodeEntry(zapcore.Entry{}, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, expected.String(), `"channel":"mychannel"}`)
+	actual, err := cloned.EncodeEntry(zapcore.Entry{}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Replace(expected.String(), `}`, `,"count":3}`, 1), actual.String())
+}
@@ -0,0 +1,211 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabenc
+
+import (
+	"bytes"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// fieldNamespace is prepended to the keys of structured fields that would
+// replace a member populated from the entry.
+const fieldNamespace = "fields."
+
+// A fieldRoute selects the JSON object that a top level field is added to
+// and the key it is added with.
+type fieldRoute func(key string) (object int, name string)
+
+// A fieldEncoder is the zapcore.ObjectEncoder that maintains the structured
+// field state of the ECS and OpenTelemetry encoders. Top level fields are
+// added to one of several JSON objects as selected by the route; the fields
+// of nested objects and of namespaces keep their keys.
+type fieldEncoder struct {
+	objects   []zapcore.Encoder
+	route     fieldRoute
+	namespace int // the object with an open namespace or -1
+}
+
+func newFieldEncoder(objects int, route fieldRoute) *fieldEncoder {
+	f := &fieldEncoder{route: route, namespace: -1}
+	for i := 0; i < objects; i++ {
+		f.objects = append(f.objects, zapcore.NewJSONEncoder(zapcore.EncoderConfig{
+			LineEnding:     "\n",
+			EncodeDuration: zapcore.NanosDurationEncoder,
+			EncodeTime:     zapcore.RFC3339NanoTimeEncoder,
+		}))
+	}
+	return f
+}
+
+func (f *fieldEncoder) clone() *fieldEncoder {
+	clone := &fieldEncoder{route: f.route, namespace: f.namespace}
+	for _, o := range f.objects {
+		clone.objects = append(clone.objects, o.Clone())
+	}
+	return clone
+}
+
+// with returns a clone of the encoder with the fields added.
+func (f *fieldEncoder) with(fields []zapcore.Field) *fieldEncoder {
+	clone := f.clone()
+	for _, field := range fields {
+		field.AddTo(clone)
+	}
+	return clone
+}
+
+// writeMembers writes the members of an object to w.
+func (f *fieldEncoder) writeMembers(w *objectWriter, object int) error {
+	buf, err := f.objects[object].EncodeEntry(zapcore.Entry{}, nil)
+	if err != nil {
+		return err
+	}
+	defer buf.Free()
+
+	b := bytes.TrimSpace(buf.Bytes())
+	w.Members(b[1 : len(b)-1])
+	return nil
+}
+
+func (f *fieldEncoder) target(key string) (zapcore.ObjectEncoder, string) {
+	if f.namespace >= 0 {
+		return f.objects[f.namespace], key
+	}
+	object, name := f.route(key)
+	return f.objects[object], name
+}
+
+func (f *fieldEncoder) AddArray(k string, v zapcore.ArrayMarshaler) error {
+	enc, k := f.target(k)
+	return enc.AddArray(k, v)
+}
+
+func (f *fieldEncoder) AddObject(k string, v zapcore.ObjectMarshaler) error {
+	enc, k := f.target(k)
+	return enc.AddObject(k, v)
+}
+
+func (f *fieldEncoder) AddBinary(k string, v []byte) {
+	enc, k := f.target(k)
+	enc.AddBinary(k, v)
+}
+
+func (f *fieldEncoder) AddByteString(k string, v []byte) {
+	enc, k := f.target(k)
+	enc.AddByteString(k, v)
+}
+
+func (f *fieldEncoder) AddBool(k string, v bool) {
+	enc, k := f.target(k)
+	enc.AddBool(k, v)
+}
+
+func (f *fieldEncoder) AddComplex128(k string, v complex128) {
+	enc, k := f.target(k)
+	enc.AddComplex128(k, v)
+}
+
+func (f *fieldEncoder) AddComplex64(k string, v complex64) {
+	enc, k := f.target(k)
+	enc.AddComplex64(k, v)
+}
+
+func (f *fieldEncoder) AddDuration(k string, v time.Duration) {
+	enc, k := f.target(k)
+	enc.AddDuration(k, v)
+}
+
+func (f *fieldEncoder) AddFloat64(k string, v float64) {
+	enc, k := f.target(k)
+	enc.AddFloat64(k, v)
+}
+
+func (f *fieldEncoder) AddFloat32(k string, v float32) {
+	enc, k := f.target(k)
+	enc.AddFloat32(k, v)
+}
+
+func (f *fieldEncoder) AddInt(k string, v int) {
+	enc, k := f.target(k)
+	enc.AddInt(k, v)
+}
+
+func (f *fieldEncoder) AddInt64(k string, v int64) {
+	enc, k := f.target(k)
+	enc.AddInt64(k, v)
+}
+
+func (f *fieldEncoder) AddInt32(k string, v int32) {
+	enc, k := f.target(k)
+	enc.AddInt32(k, v)
+}
+
+func (f *fieldEncoder) AddInt16(k string, v int16) {
+	enc, k := f.target(k)
+	enc.AddInt16(k, v)
+}
+
+func (f *fieldEncoder) AddInt8(k string, v int8) {
+	enc, k := f.target(k)
+	enc.AddInt8(k, v)
+}
+
+func (f *fieldEncoder) AddString(k, v string) {
+	enc, k := f.target(k)
+	enc.AddString(k, v)
+}
+
+func (f *fieldEncoder) AddTime(k string, v time.Time) {
+	enc, k := f.target(k)
+	enc.AddTime(k, v)
+}
+
+func (f *fieldEncoder) AddUint(k string, v uint) {
+	enc, k := f.target(k)
+	enc.AddUint(k, v)
+}
+
+func (f *fieldEncoder) AddUint64(k string, v uint64) {
+	enc, k := f.target(k)
+	enc.AddUint64(k, v)
+}
+
+func (f *fieldEncoder) AddUint32(k string, v uint32) {
+	enc, k := f.target(k)
+	enc.AddUint32(k, v)
+}
+
+func (f *fieldEncoder) AddUint16(k string, v uint16) {
+	enc, k := f.target(k)
+	enc.AddUint16(k, v)
+}
+
+func (f *fieldEncoder) AddUint8(k string, v uint8) {
+	enc, k := f.target(k)
+	enc.AddUint8(k, v)
+}
+
+func (f *fieldEncoder) AddUintptr(k string, v uintptr) {
+	enc, k := f.target(k)
+	enc.AddUintptr(k, v)
+}
+
+func (f *fieldEncoder) AddReflected(k string, v interface{}) error {
+	enc, k := f.target(k)
+	return enc.AddReflected(k, v)
+}
+
+// OpenNamespace opens a namespace in the object selected for the key. The
+// fields added after it are added to the namespace with their keys.
+func (f *fieldEncoder) OpenNamespace(k string) {
+	if f.namespace < 0 {
+		f.namespace, k = f.route(k)
+	}
+	f.objects[f.namespace].OpenNamespace(k)
+}
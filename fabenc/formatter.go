@@ -149,8 +149,9 @@ func NewFormatter(verb, format string) (Formatter, error) {
 
 // A ColorFormatter formats an SGR color code.
 type ColorFormatter struct {
-	Bold  bool // set the bold attribute
-	Reset bool // reset colors and attributes
+	Bold     bool // set the bold attribute
+	Reset    bool // reset colors and attributes
+	Disabled bool // emit nothing
 }
 
 func newColorFormatter(f string) (ColorFormatter, error) {
@@ -186,9 +187,12 @@ func (c ColorFormatter) LevelColor(l zapcore.Level) Color {
 	}
 }
 
-// Format writes the SGR color code to the provided writer.
+// Format writes the SGR color code to the provided writer. Nothing is written
+// when the formatter is disabled.
 func (c ColorFormatter) Format(w io.Writer, entry zapcore.Entry, fields []zapcore.Field) {
 	switch {
+	case c.Disabled:
+		return
 	case c.Reset:
 		fmt.Fprintf(w, ResetColor())
 	case c.Bold:
@@ -198,6 +202,20 @@ func (c ColorFormatter) Format(w io.Writer, entry zapcore.Entry, fields []zapcor
 	}
 }
 
+// DisableColor returns a copy of the formatters where every ColorFormatter has
+// been disabled. Other formatters are returned unchanged.
+func DisableColor(formatters []Formatter) []Formatter {
+	disabled := make([]Formatter, len(formatters))
+	for i, f := range formatters {
+		if cf, ok := f.(ColorFormatter); ok {
+			cf.Disabled = true
+			f = cf
+		}
+		disabled[i] = f
+	}
+	return disabled
+}
+
 // LevelFormatter formats a log level.
 type LevelFormatter struct{ FormatVerb string }
 
@@ -241,6 +259,9 @@ var sequence uint64
 // SetSequence explicitly sets the global sequence number.
 func SetSequence(s uint64) { atomic.StoreUint64(&sequence, s) }
 
+// nextSequence increments the global sequence number and returns it.
+func nextSequence() uint64 { return atomic.AddUint64(&sequence, 1) }
+
 // SequenceFormatter formats a global sequence number.
 type SequenceFormatter struct{ FormatVerb string }
 
@@ -251,7 +272,7 @@ func newSequenceFormatter(f string) SequenceFormatter {
 // SequenceFormatter increments a global sequence number and writes it to the
 // provided writer.
 func (s SequenceFormatter) Format(w io.Writer, entry zapcore.Entry, fields []zapcore.Field) {
-	fmt.Fprintf(w, s.FormatVerb, atomic.AddUint64(&sequence, 1))
+	fmt.Fprintf(w, s.FormatVerb, nextSequence())
 }
 
 // ShortFuncFormatter formats the name of the function creating the log record.
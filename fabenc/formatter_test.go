@@ -149,6 +149,9 @@ func TestColorFormatter(t *testing.T) {
 		{f: fabenc.ColorFormatter{Bold: true}, level: zapcore.FatalLevel, formatted: fabenc.ColorMagenta.Bold()},
 		{f: fabenc.ColorFormatter{}, level: zapcore.Level(99), formatted: fabenc.ColorNone.Normal()},
 		{f: fabenc.ColorFormatter{Bold: true}, level: zapcore.Level(99), formatted: fabenc.ColorNone.Normal()},
+		{f: fabenc.ColorFormatter{Disabled: true}, level: zapcore.InfoLevel, formatted: ""},
+		{f: fabenc.ColorFormatter{Bold: true, Disabled: true}, level: zapcore.ErrorLevel, formatted: ""},
+		{f: fabenc.ColorFormatter{Reset: true, Disabled: true}, level: zapcore.DebugLevel, formatted: ""},
 	}
 
 	for i, tc := range tests {
@@ -161,6 +164,23 @@ func TestColorFormatter(t *testing.T) {
 	}
 }
 
+func TestDisableColor(t *testing.T) {
+	formatters, err := fabenc.ParseFormat("%{color}%{level}%{color:reset} %{message}")
+	assert.NoError(t, err)
+
+	disabled := fabenc.DisableColor(formatters)
+	assert.Equal(t, []fabenc.Formatter{
+		fabenc.ColorFormatter{Disabled: true},
+		fabenc.LevelFormatter{FormatVerb: "%s"},
+		fabenc.ColorFormatter{Reset: true, Disabled: true},
+		fabenc.StringFormatter{Value: " "},
+		fabenc.MessageFormatter{FormatVerb: "%s"},
+	}, disabled)
+
+	// the source formatters are not modified
+	assert.Equal(t, fabenc.ColorFormatter{}, formatters[0])
+}
+
 func TestLevelFormatter(t *testing.T) {
 	var tests = []struct {
 		level     zapcore.Level
@@ -0,0 +1,157 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabenc
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"unicode/utf8"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+type keyValue struct {
+	key   string
+	value json.RawMessage
+}
+
+// decodeObject decodes the top level members of a JSON object in the order
+// they were encoded. The values are returned as compact JSON.
+func decodeObject(b []byte) ([]keyValue, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+
+	var kvs []keyValue
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, err
+		}
+		kvs = append(kvs, keyValue{key: t.(string), value: raw})
+	}
+	return kvs, nil
+}
+
+// An objectWriter writes the members of a JSON object to a buffer. The
+// caller is responsible for the enclosing braces.
+type objectWriter struct {
+	buf   *buffer.Buffer
+	count int
+}
+
+func (o *objectWriter) key(k string) {
+	if o.count > 0 {
+		o.buf.AppendByte(',')
+	}
+	o.count++
+	appendJSONString(o.buf, k)
+	o.buf.AppendByte(':')
+}
+
+func (o *objectWriter) String(k, v string) {
+	o.key(k)
+	appendJSONString(o.buf, v)
+}
+
+func (o *objectWriter) Int(k string, v int64) {
+	o.key(k)
+	o.buf.AppendInt(v)
+}
+
+func (o *objectWriter) Raw(k string, v []byte) {
+	o.key(k)
+	o.buf.Write(v)
+}
+
+// Members writes members that have already been encoded.
+func (o *objectWriter) Members(b []byte) {
+	if len(b) == 0 {
+		return
+	}
+	if o.count > 0 {
+		o.buf.AppendByte(',')
+	}
+	o.count++
+	o.buf.Write(b)
+}
+
+// Object writes a nested object with members populated by fn.
+func (o *objectWriter) Object(k string, fn func(*objectWriter)) {
+	o.key(k)
+	o.buf.AppendByte('{')
+	fn(&objectWriter{buf: o.buf})
+	o.buf.AppendByte('}')
+}
+
+const hex = "0123456789abcdef"
+
+// appendJSONString appends s to the buffer as a quoted JSON string. Invalid
+// UTF-8 sequences are replaced by the Unicode replacement character.
+func appendJSONString(buf *buffer.Buffer, s string) {
+	buf.AppendByte('"')
+	for i := 0; i < len(s); {
+		b := s[i]
+		if b < utf8.RuneSelf {
+			switch {
+			case b == '"' || b == '\\':
+				buf.AppendByte('\\')
+				buf.AppendByte(b)
+			case b == '\n':
+				buf.AppendString(`\n`)
+			case b == '\r':
+				buf.AppendString(`\r`)
+			case b == '\t':
+				buf.AppendString(`\t`)
+			case b < 0x20:
+				buf.AppendString(`\u00`)
+				buf.AppendByte(hex[b>>4])
+				buf.AppendByte(hex[b&0xf])
+			default:
+				buf.AppendByte(b)
+			}
+			i++
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			buf.AppendString(`�`)
+		} else {
+			buf.AppendString(s[i : i+size])
+		}
+		i += size
+	}
+	buf.AppendByte('"')
+}
+
+// levelName returns the lower case name of a level. Levels below debug are
+// reported as payload.
+func levelName(l zapcore.Level) string {
+	if l < zapcore.DebugLevel {
+		return "payload"
+	}
+	return l.String()
+}
+
+// sortedKeys returns the keys of the map in lexical order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
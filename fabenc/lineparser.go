@@ -0,0 +1,422 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabenc
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// A LineParser parses log lines written by a FormatEncoder back into
+// records. The parser is derived from the formatters of a format spec so the
+// lines must have been written with the same spec.
+//
+// The trailing structured fields written by the FormatEncoder are parsed as
+// logfmt. When the message is the last verb of the spec, the fields are the
+// longest suffix of the line that is a sequence of key=value pairs.
+type LineParser struct {
+	// Location is used to interpret times that do not include a zone offset.
+	// NewLineParser sets it to time.Local.
+	Location *time.Location
+
+	regexp     *regexp.Regexp
+	groups     []Formatter // the formatter of each capture group
+	messageEnd bool        // the message is the last verb of the spec
+}
+
+// colorPattern matches the SGR escapes written by a ColorFormatter.
+const colorPattern = `(?:\x1b\[[0-9;]*m)?`
+
+// fmtVerbRegexp matches the flags, width, precision, and verb of a fmt style
+// format directive.
+var fmtVerbRegexp = regexp.MustCompile(`^%([-+# 0]*)(\d*)(?:\.\d*)?([a-zA-Z])$`)
+
+// NewLineParser creates a LineParser for lines written with the format spec.
+// Please see ParseFormat for details on the supported verbs.
+func NewLineParser(spec string) (*LineParser, error) {
+	formatters, err := ParseFormat(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &LineParser{Location: time.Local}
+	pattern := &strings.Builder{}
+	pattern.WriteString(`(?s)^`)
+	for i, f := range formatters {
+		var group string
+		switch f := f.(type) {
+		case StringFormatter:
+			pattern.WriteString(regexp.QuoteMeta(f.Value))
+			continue
+		case ColorFormatter:
+			pattern.WriteString(colorPattern)
+			continue
+		case LevelFormatter:
+			group, err = verbPattern(f.FormatVerb, `[A-Z]+(?:\(-?\d*\)?)?`)
+		case MessageFormatter:
+			group, err = verbPattern(f.FormatVerb, `.*?`)
+			p.messageEnd = lastVerb(formatters[i+1:])
+		case ModuleFormatter:
+			group, err = verbPattern(f.FormatVerb, `[[:alnum:]_#:.-]*?`)
+		case SequenceFormatter:
+			group, err = verbPattern(f.FormatVerb, digitPatterns[verbBase(f.FormatVerb)])
+		case ShortFuncFormatter:
+			group, err = verbPattern(f.FormatVerb, `\S+?`)
+		case TimeFormatter:
+			group = "(" + layoutPattern(f.Layout) + ")"
+		default:
+			err = fmt.Errorf("unsupported formatter: %T", f)
+		}
+		if err != nil {
+			return nil, err
+		}
+		pattern.WriteString(group)
+		p.groups = append(p.groups, f)
+	}
+	// the structured fields are separated from the formatted text by a space
+	if p.messageEnd || len(formatters) == 0 {
+		pattern.WriteString(`(.*?)\n?$`)
+	} else {
+		pattern.WriteString(`(?: (.*?))?\n?$`)
+	}
+
+	p.regexp, err = regexp.Compile(pattern.String())
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ParseLine parses a log line written with the format spec.
+func ParseLine(spec, line string) (*Record, error) {
+	p, err := NewLineParser(spec)
+	if err != nil {
+		return nil, err
+	}
+	return p.Parse(line)
+}
+
+// Parse parses a log line. A line may contain newlines when the message of
+// the record spans multiple lines.
+func (p *LineParser) Parse(line string) (*Record, error) {
+	m := p.regexp.FindStringSubmatch(line)
+	if m == nil {
+		return nil, fmt.Errorf("line does not match format")
+	}
+
+	rec := &Record{}
+	message, hasMessage := "", false
+	for i, f := range p.groups {
+		text := strings.TrimSpace(m[i+1])
+		switch f := f.(type) {
+		case LevelFormatter:
+			level, ok := parseLevel(f, text)
+			if !ok {
+				return nil, fmt.Errorf("invalid level: %s", text)
+			}
+			rec.Entry.Level = level
+		case MessageFormatter:
+			if !hasMessage {
+				message, hasMessage = m[i+1], true
+			}
+		case ModuleFormatter:
+			rec.Entry.LoggerName = text
+		case SequenceFormatter:
+			seq, err := strconv.ParseUint(text, verbBase(f.FormatVerb), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid sequence: %s", text)
+			}
+			rec.Sequence = seq
+		case ShortFuncFormatter:
+			if text != "(unknown)" {
+				rec.Function = text
+			}
+		case TimeFormatter:
+			t, err := time.ParseInLocation(f.Layout, text, p.Location)
+			if err != nil {
+				return nil, err
+			}
+			rec.Entry.Time = t
+		}
+	}
+
+	fields := m[len(m)-1]
+	if hasMessage && p.messageEnd {
+		rec.Entry.Message, rec.Fields = splitFields(message + fields)
+		return rec, nil
+	}
+
+	rec.Entry.Message = message
+	if fields != "" {
+		parsed, err := ParseFields(fields)
+		if err != nil {
+			return nil, err
+		}
+		rec.Fields = parsed
+	}
+	return rec, nil
+}
+
+// lastVerb determines whether the remaining formatters write nothing other
+// than color escapes.
+func lastVerb(formatters []Formatter) bool {
+	for _, f := range formatters {
+		if _, ok := f.(ColorFormatter); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// verbPattern returns a capture group for the text written by a fmt style
+// format directive. Padding introduced by a width is outside of the group.
+func verbPattern(verb, inner string) (string, error) {
+	m := fmtVerbRegexp.FindStringSubmatch(verb)
+	if m == nil {
+		return "", fmt.Errorf("unsupported format directive: %s", verb)
+	}
+	flags, width := m[1], m[2]
+	switch {
+	case width == "" || strings.Contains(flags, "0"):
+		return "(" + inner + ")", nil
+	case strings.Contains(flags, "-"):
+		return "(" + inner + ") *", nil
+	default:
+		return " *(" + inner + ")", nil
+	}
+}
+
+// digitPatterns match the digits of an integer in each supported base.
+var digitPatterns = map[int]string{
+	2:  `[01]+?`,
+	8:  `[0-7]+?`,
+	10: `\d+?`,
+	16: `[0-9a-fA-F]+?`,
+}
+
+// verbBase returns the base of the integer written by a format directive.
+func verbBase(verb string) int {
+	switch verb[len(verb)-1] {
+	case 'x', 'X':
+		return 16
+	case 'o':
+		return 8
+	case 'b':
+		return 2
+	default:
+		return 10
+	}
+}
+
+// parseLevel finds the level that is written as text by the formatter.
+func parseLevel(f LevelFormatter, text string) (zapcore.Level, bool) {
+	for l := zapcore.DebugLevel - 1; l <= zapcore.FatalLevel; l++ {
+		if strings.TrimSpace(fmt.Sprintf(f.FormatVerb, l.CapitalString())) == text {
+			return l, true
+		}
+	}
+	return zapcore.InfoLevel, false
+}
+
+// layoutChunks maps the elements of a time layout to the patterns matching
+// the text they produce. Longer elements are listed before their prefixes.
+var layoutChunks = []struct{ chunk, pattern string }{
+	{"January", `[A-Z][a-z]+`},
+	{"Jan", `[A-Z][a-z]{2}`},
+	{"Monday", `[A-Z][a-z]+`},
+	{"Mon", `[A-Z][a-z]{2}`},
+	{"MST", `(?:[A-Z]{3,5}|[+-]\d{2,4})`},
+	{"2006", `\d{4}`},
+	{"002", `\d{3}`},
+	{"__2", `[ \d]{2}\d`},
+	{"_2", `[ \d]\d`},
+	{"01", `\d{2}`},
+	{"02", `\d{2}`},
+	{"03", `\d{2}`},
+	{"04", `\d{2}`},
+	{"05", `\d{2}`},
+	{"06", `\d{2}`},
+	{"15", `\d{2}`},
+	{"1", `\d{1,2}`},
+	{"2", `\d{1,2}`},
+	{"3", `\d{1,2}`},
+	{"4", `\d{1,2}`},
+	{"5", `\d{1,2}`},
+	{"PM", `[AP]M`},
+	{"pm", `[ap]m`},
+	{"Z07:00:00", `(?:Z|[+-]\d{2}:\d{2}:\d{2})`},
+	{"Z070000", `(?:Z|[+-]\d{6})`},
+	{"Z07:00", `(?:Z|[+-]\d{2}:\d{2})`},
+	{"Z0700", `(?:Z|[+-]\d{4})`},
+	{"Z07", `(?:Z|[+-]\d{2})`},
+	{"-07:00:00", `[+-]\d{2}:\d{2}:\d{2}`},
+	{"-070000", `[+-]\d{6}`},
+	{"-07:00", `[+-]\d{2}:\d{2}`},
+	{"-0700", `[+-]\d{4}`},
+	{"-07", `[+-]\d{2}`},
+}
+
+// fractionRegexp matches fractional seconds in a time layout.
+var fractionRegexp = regexp.MustCompile(`^[.,](0+|9+)`)
+
+// layoutPattern converts a time layout to a regular expression that matches
+// the times it formats.
+func layoutPattern(layout string) string {
+	pattern := &strings.Builder{}
+	for layout != "" {
+		if m := fractionRegexp.FindStringSubmatch(layout); m != nil && !startsWithDigit(layout[len(m[0]):]) {
+			if m[1][0] == '0' {
+				fmt.Fprintf(pattern, `[.,]\d{%d}`, len(m[1]))
+			} else {
+				fmt.Fprintf(pattern, `(?:[.,]\d{1,%d})?`, len(m[1]))
+			}
+			layout = layout[len(m[0]):]
+			continue
+		}
+
+		matched := false
+		for _, c := range layoutChunks {
+			if strings.HasPrefix(layout, c.chunk) {
+				pattern.WriteString(c.pattern)
+				layout = layout[len(c.chunk):]
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			pattern.WriteString(regexp.QuoteMeta(layout[:1]))
+			layout = layout[1:]
+		}
+	}
+	return pattern.String()
+}
+
+func startsWithDigit(s string) bool {
+	return s != "" && s[0] >= '0' && s[0] <= '9'
+}
+
+// ParseFields parses the logfmt encoded fields written by a FormatEncoder.
+// Unquoted numbers and booleans are converted to numeric and boolean fields;
+// all other values are converted to string fields.
+func ParseFields(text string) ([]zapcore.Field, error) {
+	pairs, ok := parseLogfmt(text)
+	if !ok {
+		return nil, fmt.Errorf("invalid fields: %s", text)
+	}
+	return pairs, nil
+}
+
+// splitFields separates a message from the structured fields that follow
+// it.
+func splitFields(text string) (string, []zapcore.Field) {
+	for i := 0; i < len(text); i++ {
+		if i > 0 && text[i-1] != ' ' {
+			continue
+		}
+		if fields, ok := parseLogfmt(text[i:]); ok && len(fields) > 0 {
+			return strings.TrimSuffix(text[:i], " "), fields
+		}
+	}
+	return text, nil
+}
+
+// parseLogfmt splits text into fields. Every space separated token must be a
+// key followed by '=' and an optionally quoted value.
+func parseLogfmt(text string) ([]zapcore.Field, bool) {
+	var fields []zapcore.Field
+	for text != "" {
+		eq := strings.IndexByte(text, '=')
+		if eq <= 0 || strings.IndexFunc(text[:eq], invalidKeyRune) >= 0 {
+			return nil, false
+		}
+		key := text[:eq]
+		text = text[eq+1:]
+
+		if strings.HasPrefix(text, `"`) {
+			end := closingQuote(text)
+			if end < 0 {
+				return nil, false
+			}
+			value, err := strconv.Unquote(text[:end+1])
+			if err != nil {
+				return nil, false
+			}
+			fields = append(fields, zap.String(key, value))
+			text = text[end+1:]
+		} else {
+			end := strings.IndexByte(text, ' ')
+			if end < 0 {
+				end = len(text)
+			}
+			value := text[:end]
+			if strings.ContainsAny(value, "=\"\n") {
+				return nil, false
+			}
+			fields = append(fields, logfmtField(key, value))
+			text = text[end:]
+		}
+
+		if text != "" && !strings.HasPrefix(text, " ") {
+			return nil, false
+		}
+		text = strings.TrimPrefix(text, " ")
+	}
+	return fields, true
+}
+
+func invalidKeyRune(r rune) bool {
+	return unicode.IsSpace(r) || r == '"' || r == '=' || !unicode.IsPrint(r)
+}
+
+// closingQuote returns the index of the quote that terminates the quoted
+// string at the start of s or -1.
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// logfmtField converts an unquoted logfmt value to a field.
+func logfmtField(key, value string) zapcore.Field {
+	switch value {
+	case "true", "false":
+		return zap.Bool(key, value == "true")
+	}
+	if !numeric(value) {
+		return zap.String(key, value)
+	}
+	if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return zap.Int64(key, i)
+	}
+	if u, err := strconv.ParseUint(value, 10, 64); err == nil {
+		return zap.Uint64(key, u)
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return zap.Float64(key, f)
+	}
+	return zap.String(key, value)
+}
+
+// numeric determines whether a value looks like a number. It excludes the
+// special values, such as NaN, accepted by strconv.ParseFloat.
+func numeric(value string) bool {
+	value = strings.TrimLeft(value, "+-")
+	return value != "" && (startsWithDigit(value) || value[0] == '.')
+}
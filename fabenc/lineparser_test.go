@@ -0,0 +1,199 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabenc_test
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/redresseur/flogging/fabenc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLineParserRoundTrip(t *testing.T) {
+	ts := time.Date(2020, time.February, 10, 11, 12, 13, 125000000, time.UTC)
+	pc, file, line, ok := runtime.Caller(0)
+	entry := zapcore.Entry{
+		Level:      zapcore.WarnLevel,
+		Time:       ts,
+		LoggerName: "peer.gossip",
+		Message:    "hello world",
+		Caller:     zapcore.NewEntryCaller(pc, file, line, ok),
+	}
+	fields := []zapcore.Field{
+		zap.String("channel", "my channel"),
+		zap.Int("block", 42),
+		zap.Bool("ok", true),
+		zap.String("empty", ""),
+	}
+	parsedFields := []zapcore.Field{
+		zap.String("channel", "my channel"),
+		zap.Int64("block", 42),
+		zap.Bool("ok", true),
+		zap.String("empty", ""),
+	}
+
+	var tests = []struct {
+		spec     string
+		time     time.Time
+		function string
+	}{
+		{
+			spec:     "%{color}%{time:2006-01-02 15:04:05.000 MST} [%{module}] %{shortfunc} -> %{level:.4s} %{id:03x}%{color:reset} %{message}",
+			time:     ts,
+			function: "TestLineParserRoundTrip",
+		},
+		{
+			spec: "%{time} %{level:-8s}|%{module:12s}|%{id:5d}| %{message}",
+			time: ts,
+		},
+		{
+			spec:     "%{message} (%{shortfunc} %{level} %{time:Jan _2 3:04:05.99PM})",
+			time:     time.Date(0, time.February, 10, 11, 12, 13, 120000000, time.UTC),
+			function: "TestLineParserRoundTrip",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.spec, func(t *testing.T) {
+			formatters, err := fabenc.ParseFormat(tc.spec)
+			require.NoError(t, err)
+			buf, err := fabenc.NewFormatEncoder(formatters...).EncodeEntry(entry, fields)
+			require.NoError(t, err)
+
+			p, err := fabenc.NewLineParser(tc.spec)
+			require.NoError(t, err)
+			p.Location = time.UTC
+
+			rec, err := p.Parse(buf.String())
+			require.NoError(t, err, "line: %q", buf.String())
+			assert.True(t, tc.time.Equal(rec.Entry.Time), "time: %s", rec.Entry.Time)
+			assert.Equal(t, zapcore.WarnLevel, rec.Entry.Level)
+			assert.Equal(t, "hello world", rec.Entry.Message)
+			assert.Equal(t, tc.function, rec.Function)
+			assert.Equal(t, parsedFields, rec.Fields)
+		})
+	}
+}
+
+func TestParseLine(t *testing.T) {
+	var tests = []struct {
+		desc     string
+		spec     string
+		line     string
+		expected *fabenc.Record
+	}{
+		{
+			desc: "default format",
+			spec: "%{color}%{time:2006-01-02 15:04:05.000 MST} [%{module}] %{shortfunc} -> %{level:.4s} %{id:03x}%{color:reset} %{message}",
+			line: "\x1b[34m2020-02-10 11:12:13.125 UTC [peer.gossip] handleMessage -> INFO 0a1\x1b[0m hello a=b world key=value n=-1.5\n",
+			expected: &fabenc.Record{
+				Entry: zapcore.Entry{
+					Time:       time.Date(2020, time.February, 10, 11, 12, 13, 125000000, time.UTC),
+					Level:      zapcore.InfoLevel,
+					LoggerName: "peer.gossip",
+					Message:    "hello a=b world",
+				},
+				Function: "handleMessage",
+				Sequence: 0xa1,
+				Fields:   []zapcore.Field{zap.String("key", "value"), zap.Float64("n", -1.5)},
+			},
+		},
+		{
+			desc: "multi-line message",
+			spec: "%{level} %{message}",
+			line: "ERROR first\nsecond key=value",
+			expected: &fabenc.Record{
+				Entry:  zapcore.Entry{Level: zapcore.ErrorLevel, Message: "first\nsecond"},
+				Fields: []zapcore.Field{zap.String("key", "value")},
+			},
+		},
+		{
+			desc: "message before other verbs",
+			spec: "%{message} [%{module}]",
+			line: "a=b c [logger] key=\"quoted \\\"value\\\"\" big=18446744073709551615 nan=NaN",
+			expected: &fabenc.Record{
+				Entry: zapcore.Entry{LoggerName: "logger", Message: "a=b c"},
+				Fields: []zapcore.Field{
+					zap.String("key", `quoted "value"`),
+					zap.Uint64("big", 18446744073709551615),
+					zap.String("nan", "NaN"),
+				},
+			},
+		},
+		{
+			desc: "unknown function and payload level",
+			spec: "%{shortfunc} %{level}",
+			line: "(unknown) LEVEL(-2)",
+			expected: &fabenc.Record{
+				Entry: zapcore.Entry{Level: zapcore.DebugLevel - 1},
+			},
+		},
+		{
+			desc:     "literal only",
+			spec:     "literal",
+			line:     "literal",
+			expected: &fabenc.Record{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			rec, err := fabenc.ParseLine(tc.spec, tc.line)
+			require.NoError(t, err)
+			rec.Entry.Time = rec.Entry.Time.UTC()
+			tc.expected.Entry.Time = tc.expected.Entry.Time.UTC()
+			assert.Equal(t, tc.expected, rec)
+		})
+	}
+}
+
+func TestParseLineErrors(t *testing.T) {
+	var tests = []struct {
+		desc string
+		spec string
+		line string
+		err  string
+	}{
+		{desc: "bad spec", spec: "%{color:bad}", err: "invalid color option: bad"},
+		{desc: "bad directive", spec: "%{level:*s}", err: "unsupported format directive: %*s"},
+		{desc: "mismatch", spec: "[%{module}]", line: "module", err: "line does not match format"},
+		{desc: "bad level", spec: "%{level}", line: "LOUD", err: "invalid level: LOUD"},
+		{desc: "bad sequence", spec: "%{id}", line: "99999999999999999999", err: "invalid sequence: 99999999999999999999"},
+		{desc: "sequence base", spec: "%{id}", line: "ff", err: "line does not match format"},
+		{desc: "bad time", spec: "%{time:2006-01-02}", line: "2020-13-01", err: `parsing time "2020-13-01": month out of range`},
+		{desc: "bad fields", spec: "%{message} %{level}", line: "message INFO not fields", err: "invalid fields: not fields"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := fabenc.ParseLine(tc.spec, tc.line)
+			assert.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestParseFields(t *testing.T) {
+	fields, err := fabenc.ParseFields(`a=1 b="two words" c=true d=1s e=-0.25`)
+	require.NoError(t, err)
+	assert.Equal(t, []zapcore.Field{
+		zap.Int64("a", 1),
+		zap.String("b", "two words"),
+		zap.Bool("c", true),
+		zap.String("d", "1s"),
+		zap.Float64("e", -0.25),
+	}, fields)
+
+	for _, text := range []string{"a", "=b", `a="unterminated`, `a="x"b=c`, "a=b=c", `a b=c`} {
+		_, err := fabenc.ParseFields(text)
+		assert.Error(t, err, text)
+	}
+}
@@ -0,0 +1,157 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabenc
+
+import (
+	"runtime"
+	"strconv"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// The objects of an OpenTelemetry log record that structured fields are
+// added to.
+const (
+	otelAttributes = iota
+	otelRecord
+)
+
+// otelReservedNames are the names of the attributes populated from the
+// entry.
+var otelReservedNames = map[string]bool{
+	"code.filepath":        true,
+	"code.lineno":          true,
+	"code.function":        true,
+	"exception.stacktrace": true,
+}
+
+// An OTelEncoder is a zapcore.Encoder that encodes log records as JSON
+// objects that follow the OpenTelemetry log data model. Structured fields are
+// recorded as attributes with the exception of trace_id and span_id, which
+// populate the TraceId and SpanId members of the record. Fields that would
+// replace an attribute populated from the entry, such as code.lineno, are
+// added to the fields namespace instead, e.g. as fields.code.lineno.
+type OTelEncoder struct {
+	*fieldEncoder
+	resource map[string]string
+	pool     buffer.Pool
+}
+
+// NewOTelEncoder creates an OTelEncoder. The resource attributes describe
+// the source of the log records and are added to every record.
+func NewOTelEncoder(resource map[string]string) *OTelEncoder {
+	return &OTelEncoder{
+		fieldEncoder: newFieldEncoder(2, func(key string) (int, string) {
+			switch {
+			case key == "trace_id":
+				return otelRecord, "TraceId"
+			case key == "span_id":
+				return otelRecord, "SpanId"
+			case otelReservedNames[key]:
+				return otelAttributes, fieldNamespace + key
+			default:
+				return otelAttributes, key
+			}
+		}),
+		resource: resource,
+		pool:     buffer.NewPool(),
+	}
+}
+
+// Clone creates a new instance of this encoder with the same configuration.
+func (o *OTelEncoder) Clone() zapcore.Encoder {
+	return &OTelEncoder{
+		fieldEncoder: o.fieldEncoder.clone(),
+		resource:     o.resource,
+		pool:         o.pool,
+	}
+}
+
+// EncodeEntry encodes a zap log record as an OpenTelemetry log record
+// terminated by a newline.
+func (o *OTelEncoder) EncodeEntry(entry zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	fe := o.fieldEncoder.with(fields)
+	severityText, severityNumber := OTelSeverity(entry.Level)
+
+	line := o.pool.Get()
+	line.AppendByte('{')
+	rec := &objectWriter{buf: line}
+	rec.String("Timestamp", strconv.FormatInt(entry.Time.UnixNano(), 10))
+	rec.String("SeverityText", severityText)
+	rec.Int("SeverityNumber", int64(severityNumber))
+	rec.String("Body", entry.Message)
+	if len(o.resource) > 0 {
+		rec.Object("Resource", func(res *objectWriter) {
+			for _, k := range sortedKeys(o.resource) {
+				res.String(k, o.resource[k])
+			}
+		})
+	}
+	if entry.LoggerName != "" {
+		rec.Object("InstrumentationScope", func(scope *objectWriter) {
+			scope.String("Name", entry.LoggerName)
+		})
+	}
+	var err error
+	rec.Object("Attributes", func(attrs *objectWriter) {
+		if entry.Caller.Defined {
+			attrs.String("code.filepath", entry.Caller.File)
+			attrs.Int("code.lineno", int64(entry.Caller.Line))
+			if function := callerFunction(entry.Caller); function != "" {
+				attrs.String("code.function", function)
+			}
+		}
+		err = fe.writeMembers(attrs, otelAttributes)
+		if entry.Stack != "" {
+			attrs.String("exception.stacktrace", entry.Stack)
+		}
+	})
+	if err == nil {
+		err = fe.writeMembers(rec, otelRecord)
+	}
+	if err != nil {
+		line.Free()
+		return nil, err
+	}
+	line.AppendString("}\n")
+
+	return line, nil
+}
+
+// OTelSeverity maps a zap level to the OpenTelemetry severity text and
+// number. Levels below debug map to TRACE.
+func OTelSeverity(l zapcore.Level) (string, int) {
+	switch {
+	case l < zapcore.DebugLevel:
+		return "TRACE", 1
+	case l == zapcore.DebugLevel:
+		return "DEBUG", 5
+	case l == zapcore.InfoLevel:
+		return "INFO", 9
+	case l == zapcore.WarnLevel:
+		return "WARN", 13
+	case l == zapcore.ErrorLevel:
+		return "ERROR", 17
+	case l == zapcore.DPanicLevel:
+		return "ERROR", 19
+	case l == zapcore.PanicLevel:
+		return "FATAL", 21
+	default:
+		return "FATAL", 24
+	}
+}
+
+// callerFunction returns the fully qualified name of the function identified
+// by the caller or an empty string when it cannot be determined.
+func callerFunction(caller zapcore.EntryCaller) string {
+	f := runtime.FuncForPC(caller.PC)
+	if f == nil {
+		return ""
+	}
+	return f.Name()
+}
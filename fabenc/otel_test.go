@@ -0,0 +1,116 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabenc_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/redresseur/flogging/fabenc"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestOTelEncoderEncodeEntry(t *testing.T) {
+	ts := time.Unix(1586960586, 123)
+	enc := fabenc.NewOTelEncoder(map[string]string{"service.name": "peer"})
+
+	buf, err := enc.EncodeEntry(
+		zapcore.Entry{
+			Level:      zapcore.ErrorLevel,
+			Time:       ts,
+			LoggerName: "ledger",
+			Message:    "commit failed",
+			Stack:      "stack",
+		},
+		[]zapcore.Field{
+			zap.String("trace_id", "0af7651916cd43dd8448eb211c80319c"),
+			zap.String("span_id", "b7ad6b7169203331"),
+			zap.Int("block", 12),
+			zap.Namespace("tx"),
+			zap.String("id", "abc"),
+		},
+	)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"Timestamp": "1586960586000000123",
+		"SeverityText": "ERROR",
+		"SeverityNumber": 17,
+		"Body": "commit failed",
+		"Resource": {"service.name": "peer"},
+		"InstrumentationScope": {"Name": "ledger"},
+		"Attributes": {
+			"block": 12,
+			"tx": {"id": "abc"},
+			"exception.stacktrace": "stack"
+		},
+		"TraceId": "0af7651916cd43dd8448eb211c80319c",
+		"SpanId": "b7ad6b7169203331"
+	}`, buf.String())
+}
+
+func TestOTelEncoderMinimal(t *testing.T) {
+	enc := fabenc.NewOTelEncoder(nil)
+	buf, err := enc.EncodeEntry(zapcore.Entry{Level: zapcore.InfoLevel, Time: time.Unix(0, 1), Message: "hi"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"Timestamp":"1","SeverityText":"INFO","SeverityNumber":9,"Body":"hi","Attributes":{}}`+"\n", buf.String())
+}
+
+func TestOTelSeverity(t *testing.T) {
+	var tests = []struct {
+		level  zapcore.Level
+		text   string
+		number int
+	}{
+		{level: zapcore.DebugLevel - 1, text: "TRACE", number: 1},
+		{level: zapcore.DebugLevel, text: "DEBUG", number: 5},
+		{level: zapcore.InfoLevel, text: "INFO", number: 9},
+		{level: zapcore.WarnLevel, text: "WARN", number: 13},
+		{level: zapcore.ErrorLevel, text: "ERROR", number: 17},
+		{level: zapcore.DPanicLevel, text: "ERROR", number: 19},
+		{level: zapcore.PanicLevel, text: "FATAL", number: 21},
+		{level: zapcore.FatalLevel, text: "FATAL", number: 24},
+	}
+
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("%d", tc.level), func(t *testing.T) {
+			text, number := fabenc.OTelSeverity(tc.level)
+			assert.Equal(t, tc.text, text)
+			assert.Equal(t, tc.number, number)
+		})
+	}
+}
+
+func TestOTelEncoderClone(t *testing.T) {
+	enc := fabenc.NewOTelEncoder(nil)
+	enc.AddString("trace_id", "0af7651916cd43dd8448eb211c80319c")
+	cloned := enc.Clone()
+	cloned.AddString("code.lineno", "shadowed")
+
+	expected, err := enc.EncodeEntry(zapcore.Entry{}, nil)
+	assert.NoError(t, err)
+	actual, err := cloned.EncodeEntry(zapcore.Entry{}, nil)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"Timestamp": "-6795364578871345152",
+		"SeverityText": "INFO",
+		"SeverityNumber": 9,
+		"Body": "",
+		"Attributes": {},
+		"TraceId": "0af7651916cd43dd8448eb211c80319c"
+	}`, expected.String())
+	assert.JSONEq(t, `{
+		"Timestamp": "-6795364578871345152",
+		"SeverityText": "INFO",
+		"SeverityNumber": 9,
+		"Body": "",
+		"Attributes": {"fields.code.lineno": "shadowed"},
+		"TraceId": "0af7651916cd43dd8448eb211c80319c"
+	}`, actual.String())
+}
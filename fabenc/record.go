@@ -0,0 +1,118 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabenc
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// A Record is a log record that has been reconstructed from encoded output.
+// As the program counter of the caller is not available, the name of the
+// calling function is recorded separately. Sequence holds the log sequence
+// number when it was present in the output.
+type Record struct {
+	Entry    zapcore.Entry
+	Function string
+	Sequence uint64
+	Fields   []zapcore.Field
+}
+
+// A RecordEncoder encodes Records with a zapcore.Encoder. When the encoder is
+// a FormatEncoder or a DevEncoder, the %{shortfunc} verb is rendered from the
+// function name held by the Record and the %{id} verb from its sequence
+// number. Records without a sequence number are numbered from the global
+// sequence.
+//
+// A RecordEncoder is not safe for concurrent use.
+type RecordEncoder struct {
+	encoder  zapcore.Encoder
+	function string
+	sequence uint64
+}
+
+// NewRecordEncoder creates a RecordEncoder that delegates to the encoder.
+func NewRecordEncoder(encoder zapcore.Encoder) *RecordEncoder {
+	r := &RecordEncoder{}
+	switch e := encoder.(type) {
+	case *FormatEncoder:
+		clone := e.Clone().(*FormatEncoder)
+		clone.formatters = r.recordFormatters(clone.formatters)
+		encoder = clone
+	case *DevEncoder:
+		clone := e.Clone().(*DevEncoder)
+		clone.formatters = r.recordFormatters(clone.formatters)
+		encoder = clone
+	}
+	r.encoder = encoder
+	return r
+}
+
+// Encode encodes the record.
+func (r *RecordEncoder) Encode(rec *Record) (*buffer.Buffer, error) {
+	r.function = rec.Function
+	r.sequence = rec.Sequence
+	return r.encoder.EncodeEntry(rec.Entry, rec.Fields)
+}
+
+// recordFormatters replaces the ShortFuncFormatters and SequenceFormatters
+// with formatters that use the function and sequence of the record being
+// encoded.
+func (r *RecordEncoder) recordFormatters(formatters []Formatter) []Formatter {
+	replaced := make([]Formatter, 0, len(formatters))
+	for _, f := range formatters {
+		switch f := f.(type) {
+		case ShortFuncFormatter:
+			replaced = append(replaced, recordFuncFormatter{formatVerb: f.FormatVerb, function: &r.function})
+		case SequenceFormatter:
+			replaced = append(replaced, recordSequenceFormatter{formatVerb: f.FormatVerb, sequence: &r.sequence})
+		case *MultiFormatter:
+			f.mutex.RLock()
+			replaced = append(replaced, r.recordFormatters(f.formatters)...)
+			f.mutex.RUnlock()
+		default:
+			replaced = append(replaced, f)
+		}
+	}
+	return replaced
+}
+
+// recordFuncFormatter formats the short name of the function that created
+// the record being encoded.
+type recordFuncFormatter struct {
+	formatVerb string
+	function   *string
+}
+
+func (r recordFuncFormatter) Format(w io.Writer, entry zapcore.Entry, fields []zapcore.Field) {
+	fname := *r.function
+	if fname == "" {
+		fmt.Fprintf(w, r.formatVerb, "(unknown)")
+		return
+	}
+	funcIdx := strings.LastIndex(fname, ".")
+	fmt.Fprintf(w, r.formatVerb, fname[funcIdx+1:])
+}
+
+// recordSequenceFormatter formats the sequence number of the record being
+// encoded.
+type recordSequenceFormatter struct {
+	formatVerb string
+	sequence   *uint64
+}
+
+func (r recordSequenceFormatter) Format(w io.Writer, entry zapcore.Entry, fields []zapcore.Field) {
+	seq := *r.sequence
+	if seq == 0 {
+		seq = nextSequence()
+	}
+	fmt.Fprintf(w, r.formatVerb, seq)
+}
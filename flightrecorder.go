@@ -0,0 +1,255 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package flogging
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap/zapcore"
+)
+
+// FlightRecorderConfig configures a FlightRecorder.
+type FlightRecorderConfig struct {
+	// Size is the number of entries retained for a logger or correlation
+	// value. It defaults to 100.
+	Size int
+	// Level is the minimum level of the retained entries.
+	Level zapcore.Level
+	// Trigger is the level of the entries that flush the retained entries.
+	// It must be above Level.
+	Trigger zapcore.Level
+	// CorrelationKey is the key of a field that groups entries across
+	// loggers. Entries with the field are retained and flushed by the value
+	// of the field instead of by logger name.
+	CorrelationKey string
+	// MaxBuffers is the number of loggers or correlation values for which
+	// entries are retained. The least recently used buffer is discarded when
+	// the limit is reached. It defaults to 1024.
+	MaxBuffers int
+}
+
+// A FlightRecorder retains the entries that are disabled by the active
+// logging spec and writes them out when something goes wrong. Entries at or
+// above the recorder level that the spec disables are kept in a ring buffer
+// per logger, or per correlation value when a correlation key is configured.
+// When an entry at or above the trigger level is written, the retained
+// entries of its logger or correlation value are written to the writer of
+// the logging system ahead of it.
+//
+// Retained entries are encoded when they are flushed. Fields that refer to
+// mutable values reflect the values at that time.
+type FlightRecorder struct {
+	size           int
+	level          zapcore.Level
+	trigger        zapcore.Level
+	correlationKey string
+	maxBuffers     int
+
+	mutex   sync.Mutex
+	buffers map[string]*list.Element
+	lru     *list.List // of *recorderBuffer, most recently used first
+}
+
+// NewFlightRecorder creates a FlightRecorder from the configuration.
+func NewFlightRecorder(c FlightRecorderConfig) (*FlightRecorder, error) {
+	if c.Trigger <= c.Level {
+		return nil, errors.Errorf("invalid flight recorder trigger: %s is not above %s", LevelName(c.Trigger), LevelName(c.Level))
+	}
+	if c.Size <= 0 {
+		c.Size = 100
+	}
+	if c.MaxBuffers <= 0 {
+		c.MaxBuffers = 1024
+	}
+
+	return &FlightRecorder{
+		size:           c.Size,
+		level:          c.Level,
+		trigger:        c.Trigger,
+		correlationKey: c.CorrelationKey,
+		maxBuffers:     c.MaxBuffers,
+		buffers:        map[string]*list.Element{},
+		lru:            list.New(),
+	}, nil
+}
+
+// recorderBuffer is the ring buffer of retained entries for a key.
+type recorderBuffer struct {
+	key     string
+	entries []recordedEntry
+	next    int
+	count   int
+}
+
+type recordedEntry struct {
+	entry  zapcore.Entry
+	fields []zapcore.Field
+}
+
+// key returns the key of the buffer an entry belongs to.
+func (r *FlightRecorder) key(e zapcore.Entry, fields []zapcore.Field) string {
+	if r.correlationKey != "" {
+		for _, f := range fields {
+			if f.Key == r.correlationKey {
+				enc := zapcore.NewMapObjectEncoder()
+				f.AddTo(enc)
+				return fmt.Sprintf("%s=%v", f.Key, enc.Fields[f.Key])
+			}
+		}
+	}
+	return "logger=" + e.LoggerName
+}
+
+// retain adds an entry to the buffer of its key.
+func (r *FlightRecorder) retain(key string, e zapcore.Entry, fields []zapcore.Field) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var buf *recorderBuffer
+	if elem, ok := r.buffers[key]; ok {
+		r.lru.MoveToFront(elem)
+		buf = elem.Value.(*recorderBuffer)
+	} else {
+		if r.lru.Len() >= r.maxBuffers {
+			oldest := r.lru.Back()
+			r.lru.Remove(oldest)
+			delete(r.buffers, oldest.Value.(*recorderBuffer).key)
+		}
+		buf = &recorderBuffer{key: key, entries: make([]recordedEntry, r.size)}
+		r.buffers[key] = r.lru.PushFront(buf)
+	}
+
+	buf.entries[buf.next] = recordedEntry{entry: e, fields: fields}
+	buf.next = (buf.next + 1) % len(buf.entries)
+	if buf.count < len(buf.entries) {
+		buf.count++
+	}
+}
+
+// take removes the buffer of a key and returns its entries from the oldest
+// to the most recent.
+func (r *FlightRecorder) take(key string) []recordedEntry {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	elem, ok := r.buffers[key]
+	if !ok {
+		return nil
+	}
+	r.lru.Remove(elem)
+	delete(r.buffers, key)
+
+	buf := elem.Value.(*recorderBuffer)
+	entries := make([]recordedEntry, 0, buf.count)
+	start := buf.next - buf.count
+	if start < 0 {
+		start += len(buf.entries)
+	}
+	for i := 0; i < buf.count; i++ {
+		entries = append(entries, buf.entries[(start+i)%len(buf.entries)])
+	}
+	return entries
+}
+
+// Buffered returns the number of entries retained for each logger or
+// correlation value. Loggers are keyed by "logger=<name>" and correlation
+// values by "<key>=<value>".
+func (r *FlightRecorder) Buffered() map[string]int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	buffered := make(map[string]int, len(r.buffers))
+	for key, elem := range r.buffers {
+		buffered[key] = elem.Value.(*recorderBuffer).count
+	}
+	return buffered
+}
+
+// SetFlightRecorder sets the flight recorder of the logging system. A nil
+// recorder discards the retained entries and stops the recording.
+func (s *Logging) SetFlightRecorder(r *FlightRecorder) {
+	s.recorder.Store(recorderHolder{r})
+}
+
+// FlightRecorder returns the flight recorder set with SetFlightRecorder or
+// nil.
+func (s *Logging) FlightRecorder() *FlightRecorder {
+	holder, _ := s.recorder.Load().(recorderHolder)
+	return holder.recorder
+}
+
+// flush writes retained entries through the Core used for flushes. The
+// entries are encoded with the active encoding and reported to the observer
+// like the entries written by the loggers.
+func (s *Logging) flush(entries []recordedEntry) {
+	for _, re := range entries {
+		s.flushCore.Write(re.entry, re.fields)
+	}
+}
+
+// recorderHolder allows a nil flight recorder to be stored in an
+// atomic.Value.
+type recorderHolder struct {
+	recorder *FlightRecorder
+}
+
+// recorderCore is a capture core of the loggers created by a Logging
+// instance. It is checked ahead of the primary Core so that flushed entries
+// are written before the trigger.
+type recorderCore struct {
+	logging *Logging
+	fields  []zapcore.Field
+}
+
+func (c *recorderCore) Enabled(level zapcore.Level) bool {
+	r := c.logging.FlightRecorder()
+	return r != nil && level >= r.level
+}
+
+func (c *recorderCore) With(fields []zapcore.Field) zapcore.Core {
+	return &recorderCore{
+		logging: c.logging,
+		fields:  append(c.fields[:len(c.fields):len(c.fields)], fields...),
+	}
+}
+
+func (c *recorderCore) Check(e zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	r := c.logging.FlightRecorder()
+	switch {
+	case r == nil || e.Level < r.level:
+		return ce
+	case e.Level >= r.trigger:
+		return ce.AddCore(e, c)
+	case !c.logging.LoggerLevels.Level(e.LoggerName).Enabled(e.Level):
+		return ce.AddCore(e, c)
+	default:
+		return ce
+	}
+}
+
+func (c *recorderCore) Write(e zapcore.Entry, fields []zapcore.Field) error {
+	r := c.logging.FlightRecorder()
+	if r == nil {
+		return nil
+	}
+	fields = append(c.fields[:len(c.fields):len(c.fields)], fields...)
+
+	key := r.key(e, fields)
+	if e.Level >= r.trigger {
+		c.logging.flush(r.take(key))
+		return nil
+	}
+	r.retain(key, e, fields)
+	return nil
+}
+
+func (c *recorderCore) Sync() error {
+	return nil
+}
@@ -0,0 +1,169 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package flogging_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/redresseur/flogging"
+	"github.com/redresseur/flogging/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestNewFlightRecorder(t *testing.T) {
+	_, err := flogging.NewFlightRecorder(flogging.FlightRecorderConfig{})
+	assert.EqualError(t, err, "invalid flight recorder trigger: info is not above info")
+
+	_, err = flogging.NewFlightRecorder(flogging.FlightRecorderConfig{Level: zapcore.ErrorLevel, Trigger: zapcore.WarnLevel})
+	assert.EqualError(t, err, "invalid flight recorder trigger: warn is not above error")
+
+	r, err := flogging.NewFlightRecorder(flogging.FlightRecorderConfig{Level: flogging.PayloadLevel, Trigger: zapcore.ErrorLevel})
+	assert.NoError(t, err)
+	assert.Empty(t, r.Buffered())
+}
+
+func newRecordingLogging(t *testing.T, config flogging.FlightRecorderConfig) (*flogging.Logging, *bytes.Buffer) {
+	buf := &bytes.Buffer{}
+	logging, err := flogging.New(flogging.Config{
+		Format:  "%{module} %{level} %{message}",
+		LogSpec: "info",
+		Writer:  buf,
+	})
+	require.NoError(t, err)
+
+	r, err := flogging.NewFlightRecorder(config)
+	require.NoError(t, err)
+	logging.SetFlightRecorder(r)
+	return logging, buf
+}
+
+func lines(buf *bytes.Buffer) []string {
+	return strings.Split(strings.TrimSpace(buf.String()), "\n")
+}
+
+func TestFlightRecorderFlushesLogger(t *testing.T) {
+	logging, buf := newRecordingLogging(t, flogging.FlightRecorderConfig{
+		Size:    2,
+		Level:   zapcore.DebugLevel,
+		Trigger: zapcore.ErrorLevel,
+	})
+
+	gossip := logging.Logger("gossip")
+	ledger := logging.Logger("ledger")
+	gossip.Debug("first")
+	gossip.Debug("second")
+	gossip.Info("written")
+	gossip.Debug("third")
+	ledger.Debug("ledger debug")
+	assert.Equal(t, []string{"gossip INFO written"}, lines(buf))
+	assert.Equal(t, map[string]int{"logger=gossip": 2, "logger=ledger": 1}, logging.FlightRecorder().Buffered())
+
+	gossip.Error("failed")
+	assert.Equal(t, []string{
+		"gossip INFO written",
+		"gossip DEBUG second",
+		"gossip DEBUG third",
+		"gossip ERROR failed",
+	}, lines(buf))
+	assert.Equal(t, map[string]int{"logger=ledger": 1}, logging.FlightRecorder().Buffered())
+
+	buf.Reset()
+	gossip.Error("again")
+	assert.Equal(t, []string{"gossip ERROR again"}, lines(buf))
+}
+
+func TestFlightRecorderFlushesCorrelation(t *testing.T) {
+	logging, buf := newRecordingLogging(t, flogging.FlightRecorderConfig{
+		Level:          zapcore.DebugLevel,
+		Trigger:        zapcore.WarnLevel,
+		CorrelationKey: "txid",
+	})
+
+	endorser := logging.Logger("endorser")
+	ledger := logging.Logger("ledger").With("txid", "tx1")
+	endorser.Debugw("simulating", "txid", "tx1")
+	endorser.Debugw("simulating", "txid", "tx2")
+	ledger.Debug("validating")
+	endorser.Debug("uncorrelated")
+
+	endorser.Warnw("invalid", "txid", "tx1")
+	assert.Equal(t, []string{
+		`endorser DEBUG simulating txid=tx1`,
+		`ledger DEBUG validating txid=tx1`,
+		`endorser WARN invalid txid=tx1`,
+	}, lines(buf))
+	assert.Equal(t, map[string]int{"txid=tx2": 1, "logger=endorser": 1}, logging.FlightRecorder().Buffered())
+}
+
+func TestFlightRecorderMaxBuffers(t *testing.T) {
+	logging, _ := newRecordingLogging(t, flogging.FlightRecorderConfig{
+		Level:      zapcore.DebugLevel,
+		Trigger:    zapcore.ErrorLevel,
+		MaxBuffers: 2,
+	})
+
+	logging.Logger("a").Debug("a")
+	logging.Logger("b").Debug("b")
+	logging.Logger("a").Debug("a")
+	logging.Logger("c").Debug("c")
+	assert.Equal(t, map[string]int{"logger=a": 2, "logger=c": 1}, logging.FlightRecorder().Buffered())
+}
+
+func TestFlightRecorderLevel(t *testing.T) {
+	logging, buf := newRecordingLogging(t, flogging.FlightRecorderConfig{
+		Level:   zapcore.DebugLevel,
+		Trigger: zapcore.ErrorLevel,
+	})
+
+	logger := logging.Logger("peer")
+	logger.Debug("debug")
+	logging.ZapLogger("peer").Check(flogging.PayloadLevel, "payload").Write()
+	logger.Info("info")
+	assert.Equal(t, map[string]int{"logger=peer": 1}, logging.FlightRecorder().Buffered())
+
+	logging.SetFlightRecorder(nil)
+	logger.Error("error")
+	assert.Nil(t, logging.FlightRecorder())
+	assert.Equal(t, []string{"peer INFO info", "peer ERROR error"}, lines(buf))
+}
+
+func TestFlightRecorderObserver(t *testing.T) {
+	logging, buf := newRecordingLogging(t, flogging.FlightRecorderConfig{
+		Level:   zapcore.DebugLevel,
+		Trigger: zapcore.ErrorLevel,
+	})
+	observer := &mock.Observer{}
+	logging.SetObserver(observer)
+
+	gossip := logging.Logger("gossip")
+	assert.False(t, gossip.IsEnabledFor(zapcore.DebugLevel))
+	gossip.Debugw("retained", "block", 1)
+	assert.Equal(t, 0, observer.WriteEntryCallCount())
+
+	gossip.Error("failed")
+	assert.Equal(t, []string{"gossip DEBUG retained block=1", "gossip ERROR failed"}, lines(buf))
+	require.Equal(t, 2, observer.WriteEntryCallCount())
+	e, fields := observer.WriteEntryArgsForCall(0)
+	assert.Equal(t, "retained", e.Message)
+	assert.Equal(t, []zapcore.Field{zap.Int("block", 1)}, fields)
+
+	stats := logging.ObserverStats()
+	assert.Equal(t, uint64(1), stats.Written["debug"])
+	assert.Equal(t, uint64(1), stats.Written["error"])
+
+	// flushed entries follow format changes
+	buf.Reset()
+	require.NoError(t, logging.SetFormat("%{level} %{message}"))
+	gossip.Debug("again")
+	gossip.Error("failed")
+	assert.Equal(t, []string{"DEBUG again", "ERROR failed"}, lines(buf))
+}
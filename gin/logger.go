@@ -0,0 +1,74 @@
+package gin
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redresseur/flogging"
+	"go.uber.org/zap/zapcore"
+)
+
+// RequestIDHeader is the header that carries the request ID logged by
+// FabricLoggerHandler.
+const RequestIDHeader = "X-Request-Id"
+
+// FabricLoggerHandler logs every request through the logger once it has been
+// handled. The entry carries the method, path, status, latency, client IP,
+// request ID and response size as fields and its level is chosen from the
+// status: ERROR for 5xx, WARN for 4xx and INFO otherwise. The request ID is
+// taken from the request header or, when absent, from the response header.
+//
+// While the logger is enabled for DEBUG, the request body is captured and
+// logged as well; see WithBodyCapture.
+func FabricLoggerHandler(logger *flogging.FabricLogger, opts ...Option) gin.HandlerFunc {
+	o := applyOptions(opts)
+	debugEnabled := func() bool { return logger.IsEnabledFor(zapcore.DebugLevel) }
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		path := ctx.Request.URL.Path
+		if raw := ctx.Request.URL.RawQuery; raw != "" {
+			path = path + "?" + raw
+		}
+
+		if o.captureBody(debugEnabled) {
+			dump(ctx, o.maxBodySize)
+		}
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		size := ctx.Writer.Size()
+		if size < 0 {
+			size = 0
+		}
+		requestID := ctx.Request.Header.Get(RequestIDHeader)
+		if requestID == "" {
+			requestID = ctx.Writer.Header().Get(RequestIDHeader)
+		}
+
+		kvPairs := []interface{}{
+			"method", ctx.Request.Method,
+			"path", path,
+			"status", status,
+			"latency", time.Since(start),
+			"client_ip", ctx.ClientIP(),
+			"request_id", requestID,
+			"bytes", size,
+		}
+		if content := ctx.GetString(Content); content != "" {
+			kvPairs = append(kvPairs, "content_type", ctx.ContentType(), "body", content)
+		}
+		if errs := ctx.Errors.ByType(gin.ErrorTypePrivate); len(errs) > 0 {
+			kvPairs = append(kvPairs, "error", errs.String())
+		}
+
+		switch {
+		case status >= http.StatusInternalServerError:
+			logger.Errorw("request completed", kvPairs...)
+		case status >= http.StatusBadRequest:
+			logger.Warnw("request completed", kvPairs...)
+		default:
+			logger.Infow("request completed", kvPairs...)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package gin
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redresseur/flogging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRouter(t *testing.T, buf *bytes.Buffer, opts ...Option) *gin.Engine {
+	return newSpecRouter(t, buf, "debug", opts...)
+}
+
+func newSpecRouter(t *testing.T, buf *bytes.Buffer, spec string, opts ...Option) *gin.Engine {
+	logging, err := flogging.New(flogging.Config{
+		Format:  "json",
+		LogSpec: spec,
+		Writer:  buf,
+	})
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(FabricLoggerHandler(logging.Logger("gin"), opts...), gin.Recovery())
+	router.GET("/ok", func(ctx *gin.Context) {
+		ctx.String(http.StatusOK, "hello")
+	})
+	router.POST("/echo", func(ctx *gin.Context) {
+		var v map[string]interface{}
+		if err := ctx.BindJSON(&v); err != nil {
+			return
+		}
+		ctx.JSON(http.StatusCreated, v)
+	})
+	router.GET("/fail", func(ctx *gin.Context) {
+		ctx.AbortWithError(http.StatusInternalServerError, errors.New("boom"))
+	})
+	return router
+}
+
+func decodeEntry(t *testing.T, buf *bytes.Buffer) map[string]interface{} {
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 1)
+	entry := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+	return entry
+}
+
+func TestFabricLoggerHandler(t *testing.T) {
+	buf := &bytes.Buffer{}
+	router := newTestRouter(t, buf)
+
+	req := httptest.NewRequest(http.MethodGet, "/ok?q=1", nil)
+	req.Header.Set(RequestIDHeader, "req-1")
+	req.RemoteAddr = "10.0.0.1:1234"
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	entry := decodeEntry(t, buf)
+	assert.Equal(t, "info", entry["level"])
+	assert.Equal(t, "gin", entry["name"])
+	assert.Equal(t, "request completed", entry["msg"])
+	assert.Equal(t, "GET", entry["method"])
+	assert.Equal(t, "/ok?q=1", entry["path"])
+	assert.Equal(t, float64(200), entry["status"])
+	assert.Equal(t, "10.0.0.1", entry["client_ip"])
+	assert.Equal(t, "req-1", entry["request_id"])
+	assert.Equal(t, float64(5), entry["bytes"])
+	assert.Contains(t, entry, "latency")
+	assert.NotContains(t, entry, "error")
+}
+
+func TestFabricLoggerHandlerLevels(t *testing.T) {
+	tests := []struct {
+		method, path, body string
+		status             float64
+		level              string
+	}{
+		{http.MethodGet, "/ok", "", 200, "info"},
+		{http.MethodPost, "/echo", `{"a": 1}`, 201, "info"},
+		{http.MethodPost, "/echo", `{"a":`, 400, "warn"},
+		{http.MethodGet, "/missing", "", 404, "warn"},
+		{http.MethodGet, "/fail", "", 500, "error"},
+	}
+
+	for _, tc := range tests {
+		buf := &bytes.Buffer{}
+		router := newTestRouter(t, buf)
+
+		req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+		if tc.body != "" {
+			req.Header.Set("Content-Type", gin.MIMEJSON)
+		}
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		entry := decodeEntry(t, buf)
+		assert.Equal(t, tc.status, entry["status"], tc.path)
+		assert.Equal(t, tc.level, entry["level"], tc.path)
+	}
+}
+
+func TestFabricLoggerHandlerErrors(t *testing.T) {
+	buf := &bytes.Buffer{}
+	router := newTestRouter(t, buf)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fail", nil))
+	entry := decodeEntry(t, buf)
+	assert.Contains(t, entry["error"], "boom")
+}
+
+func TestFabricLoggerHandlerResponseRequestID(t *testing.T) {
+	buf := &bytes.Buffer{}
+	router := newTestRouter(t, buf)
+	router.GET("/id", func(ctx *gin.Context) {
+		ctx.Header(RequestIDHeader, "generated")
+		ctx.Status(http.StatusNoContent)
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/id", nil))
+	entry := decodeEntry(t, buf)
+	assert.Equal(t, "generated", entry["request_id"])
+	assert.Equal(t, float64(0), entry["bytes"])
+}
+
+func TestFabricLoggerHandlerBody(t *testing.T) {
+	defer gin.SetMode(gin.Mode())
+	gin.SetMode(gin.ReleaseMode)
+
+	buf := &bytes.Buffer{}
+	router := newTestRouter(t, buf)
+
+	req := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(`{"a":1}`))
+	req.Header.Set("Content-Type", gin.MIMEJSON)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.JSONEq(t, `{"a": 1}`, recorder.Body.String())
+	entry := decodeEntry(t, buf)
+	assert.Equal(t, gin.MIMEJSON, entry["content_type"])
+	assert.JSONEq(t, `{"a": 1}`, entry["body"].(string))
+}
+
+func TestFabricLoggerHandlerBodyCapture(t *testing.T) {
+	defer gin.SetMode(gin.Mode())
+	gin.SetMode(gin.DebugMode)
+
+	tests := []struct {
+		spec     string
+		opts     []Option
+		captured bool
+	}{
+		{"info", nil, false},
+		{"gin=debug:info", nil, true},
+		{"info", []Option{WithBodyCapture(true)}, true},
+		{"debug", []Option{WithBodyCapture(false)}, false},
+	}
+	for _, tc := range tests {
+		buf := &bytes.Buffer{}
+		router := newSpecRouter(t, buf, tc.spec, tc.opts...)
+
+		req := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(`{"a":1}`))
+		req.Header.Set("Content-Type", gin.MIMEJSON)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		entry := decodeEntry(t, buf)
+		assert.Equal(t, tc.captured, entry["body"] != nil, tc.spec)
+	}
+}
@@ -13,104 +13,124 @@ import (
 	"time"
 )
 
+// Content is the key of the captured request body in the gin context.
 const Content = `content`
 
+// DefaultMaxBodySize is the number of body bytes captured by default.
+const DefaultMaxBodySize = 4096
+
+// truncatedSuffix is appended to captured bodies that exceed the limit.
+const truncatedSuffix = "...(truncated)"
+
+type options struct {
+	maxBodySize int
+	bodyCapture *bool // nil when the handler decides
+}
+
+// An Option configures the logger middleware.
+type Option func(*options)
+
+// WithMaxBodySize sets the maximum number of body bytes captured for a
+// request. Longer bodies are truncated. A size of zero or less disables the
+// capture.
+func WithMaxBodySize(size int) Option {
+	return func(o *options) { o.maxBodySize = size }
+}
+
+// WithBodyCapture enables or disables the capture of request bodies. By
+// default, FabricLoggerHandler captures bodies while its logger is enabled
+// for DEBUG and GinLoggerHandler captures them in gin's debug mode.
+func WithBodyCapture(enabled bool) Option {
+	return func(o *options) { o.bodyCapture = &enabled }
+}
+
+// captureBody returns whether request bodies are captured. The default is
+// used unless WithBodyCapture was provided.
+func (o *options) captureBody(defaultCapture func() bool) bool {
+	if o.bodyCapture != nil {
+		return *o.bodyCapture
+	}
+	return defaultCapture()
+}
+
+// ginDebugMode returns true when gin is in debug mode.
+func ginDebugMode() bool { return gin.Mode() == gin.DebugMode }
+
+func applyOptions(opts []Option) *options {
+	o := &options{maxBodySize: DefaultMaxBodySize}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// textContentTypes are the content types whose body is captured. Multipart
+// and binary bodies are not captured.
+var textContentTypes = map[string]bool{
+	gin.MIMEJSON:     true,
+	gin.MIMEXML:      true,
+	gin.MIMEXML2:     true,
+	gin.MIMEPOSTForm: true,
+	gin.MIMEPlain:    true,
+	gin.MIMEYAML:     true,
+	gin.MIMEHTML:     true,
+}
+
 // dump: load the data from the body in requests.
-// support application/json, application/multipart-format
-func dump(ctx *gin.Context) {
-	var content string
+// At most maxSize bytes are read; the body seen by the handlers is not
+// changed. The captured content is stored in the gin context under Content.
+func dump(ctx *gin.Context, maxSize int) {
+	if maxSize <= 0 || ctx.Request.Body == nil {
+		return
+	}
 
 	contentType, _, err := mime.ParseMediaType(ctx.Request.Header.Get("Content-Type"))
+	if err != nil || !textContentTypes[contentType] {
+		return
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(ctx.Request.Body, int64(maxSize)+1))
 	if err != nil {
-		//ctx.AbortWithError(http.StatusBadRequest, err)
-		ctx.Next()
+		ctx.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+	// 此处很重要，否则后面处理请求的函数无法读取到Body数据
+	ctx.Request.Body = &replayBody{
+		Reader: io.MultiReader(bytes.NewReader(data), ctx.Request.Body),
+		Closer: ctx.Request.Body,
 	}
 
+	if len(data) > maxSize {
+		ctx.Set(Content, string(data[:maxSize])+truncatedSuffix)
+		return
+	}
+
+	content := string(data)
 	switch contentType {
 	case gin.MIMEJSON:
-		if data, err := ioutil.ReadAll(ctx.Request.Body); err != nil {
-			ctx.AbortWithError(http.StatusBadRequest, err)
-		} else {
-			buff := bytes.NewBuffer(nil)
-			err := json.Indent(buff, []byte(data), "", "    ")
-			if err != nil {
-				content = string(data)
-			} else {
-				content = buff.String()
-			}
-
-			// 此处很重要，否则后面处理请求的函数无法读取到Body数据
-			ctx.Request.Body = ioutil.NopCloser(bytes.NewBuffer(data))
-		}
-	case gin.MIMEXML:
-		fallthrough
-	case gin.MIMEXML2:
-		{
-			if data, err := ioutil.ReadAll(ctx.Request.Body); err != nil {
-				ctx.AbortWithError(http.StatusBadRequest, err)
-			} else {
-				content = uxml.FormatXML(string(data), "", "	")
-				// 此处很重要，否则后面处理请求的函数无法读取到Body数据
-				ctx.Request.Body = ioutil.NopCloser(bytes.NewBuffer(data))
-			}
-		}
-	case gin.MIMEPOSTForm:
-		{
-			if data, err := ioutil.ReadAll(ctx.Request.Body); err != nil {
-				ctx.AbortWithError(http.StatusBadRequest, err)
-			} else {
-				// TODO
-				content = string(data)
-				// 此处很重要，否则后面处理请求的函数无法读取到Body数据
-				ctx.Request.Body = ioutil.NopCloser(bytes.NewBuffer(data))
-			}
-
+		buff := bytes.NewBuffer(nil)
+		if err := json.Indent(buff, data, "", "    "); err == nil {
+			content = buff.String()
 		}
-	case gin.MIMEMultipartPOSTForm:
-		{
-			// 多重表單中可能包含二進制文件，暫時先不處理
-			// TODO
-		}
-	case gin.MIMEPlain:
-		{
-			if data, err := ioutil.ReadAll(ctx.Request.Body); err != nil {
-				ctx.AbortWithError(http.StatusBadRequest, err)
-			} else {
-				// TODO
-				content = string(data)
-				// 此处很重要，否则后面处理请求的函数无法读取到Body数据
-				ctx.Request.Body = ioutil.NopCloser(bytes.NewBuffer(data))
-			}
-		}
-	case gin.MIMEYAML:
-		{
-			if data, err := ioutil.ReadAll(ctx.Request.Body); err != nil {
-				ctx.AbortWithError(http.StatusBadRequest, err)
-			} else {
-				// TODO
-				content = string(data)
-				// 此处很重要，否则后面处理请求的函数无法读取到Body数据
-				ctx.Request.Body = ioutil.NopCloser(bytes.NewBuffer(data))
-			}
-		}
-	case gin.MIMEHTML:
-		{
-			if data, err := ioutil.ReadAll(ctx.Request.Body); err != nil {
-				ctx.AbortWithError(http.StatusBadRequest, err)
-			} else {
-				// TODO
-				content = string(data)
-				// 此处很重要，否则后面处理请求的函数无法读取到Body数据
-				ctx.Request.Body = ioutil.NopCloser(bytes.NewBuffer(data))
-			}
-		}
-	default:
+	case gin.MIMEXML, gin.MIMEXML2:
+		content = uxml.FormatXML(content, "", "	")
 	}
+	ctx.Set(Content, content)
+}
 
-	ctx.Request.Header.Set(Content, content)
+// replayBody replays the captured bytes ahead of the rest of the original
+// body and closes the original body.
+type replayBody struct {
+	io.Reader
+	io.Closer
 }
 
-func GinLoggerHandler(w io.Writer) gin.HandlerFunc {
+// GinLoggerHandler writes a line for every request to w in the format of the
+// gin logger. When bodies are captured, the request body is written as well;
+// see WithBodyCapture.
+func GinLoggerHandler(w io.Writer, opts ...Option) gin.HandlerFunc {
+	o := applyOptions(opts)
+
 	formatter := func(params gin.LogFormatterParams) (output string) {
 		var (
 			statusColor, methodColor, resetColor string
@@ -129,7 +149,7 @@ func GinLoggerHandler(w io.Writer) gin.HandlerFunc {
 			params.Latency = params.Latency - params.Latency%time.Second
 		}
 
-		content = params.Request.Header.Get(Content)
+		content, _ = params.Keys[Content].(string)
 		header = fmt.Sprintf("[GIN] %v |%s %3d %s| %13v | %15s |%s %-7s %s %s\n",
 			params.TimeStamp.Format("2006/01/02 - 15:04:05"),
 			statusColor, params.StatusCode, resetColor,
@@ -148,13 +168,7 @@ func GinLoggerHandler(w io.Writer) gin.HandlerFunc {
 			errMessage = fmt.Sprintf("[ERROR] %s\n", params.ErrorMessage)
 		}
 
-		if gin.Mode() == gin.DebugMode {
-			output = header + body + errMessage
-		} else {
-			output = header + errMessage
-		}
-
-		return output
+		return header + body + errMessage
 	}
 
 	loggerFunc := gin.LoggerWithConfig(gin.LoggerConfig{
@@ -163,7 +177,9 @@ func GinLoggerHandler(w io.Writer) gin.HandlerFunc {
 	})
 
 	return func(ctx *gin.Context) {
-		dump(ctx)
+		if o.captureBody(ginDebugMode) {
+			dump(ctx, o.maxBodySize)
+		}
 		loggerFunc(ctx)
 	}
 }
@@ -6,11 +6,13 @@ import (
 	"encoding/xml"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"io/ioutil"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"net/textproto"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -117,3 +119,86 @@ func TestGinLoggerHandler_form(t *testing.T) {
 		router.ServeHTTP(recorder, req)
 	}
 }
+
+func TestDump(t *testing.T) {
+	tests := []struct {
+		name, contentType, body string
+		maxSize                 int
+		expected                string
+	}{
+		{"json", "application/json; charset=utf-8", `{"a":1}`, 64, "{\n    \"a\": 1\n}"},
+		{"plain", gin.MIMEPlain, "hello", 64, "hello"},
+		{"truncated", gin.MIMEPlain, "hello world", 5, "hello" + truncatedSuffix},
+		{"truncated json", gin.MIMEJSON, `{"a":1}`, 3, `{"a` + truncatedSuffix},
+		{"exact size", gin.MIMEPlain, "hello", 5, "hello"},
+		{"disabled", gin.MIMEPlain, "hello", 0, ""},
+		{"binary", "application/octet-stream", "\x00\x01", 64, ""},
+		{"multipart", gin.MIMEMultipartPOSTForm + "; boundary=x", "--x--", 64, ""},
+		{"no content type", "", "hello", 64, ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(tc.body))
+			if tc.contentType != "" {
+				ctx.Request.Header.Set("Content-Type", tc.contentType)
+			}
+
+			dump(ctx, tc.maxSize)
+			assert.Equal(t, tc.expected, ctx.GetString(Content))
+			assert.Empty(t, ctx.Request.Header.Get(Content))
+
+			body, err := ioutil.ReadAll(ctx.Request.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.body, string(body))
+			assert.NoError(t, ctx.Request.Body.Close())
+		})
+	}
+}
+
+func TestGinLoggerHandlerMaxBodySize(t *testing.T) {
+	defer gin.SetMode(gin.Mode())
+	gin.SetMode(gin.DebugMode)
+
+	buf := &bytes.Buffer{}
+	router := gin.New()
+	router.Use(GinLoggerHandler(buf, WithMaxBodySize(4)))
+
+	var received string
+	router.POST("/test", func(ctx *gin.Context) {
+		data, _ := ioutil.ReadAll(ctx.Request.Body)
+		received = string(data)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader("hello world"))
+	req.Header.Set("Content-Type", gin.MIMEPlain)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "hello world", received)
+	assert.Contains(t, buf.String(), "[BODY] Content-Type text/plain - hell"+truncatedSuffix+"\n")
+}
+
+func TestGinLoggerHandlerBodyCapture(t *testing.T) {
+	defer gin.SetMode(gin.Mode())
+	gin.SetMode(gin.ReleaseMode)
+
+	for _, tc := range []struct {
+		opts     []Option
+		captured bool
+	}{
+		{nil, false},
+		{[]Option{WithBodyCapture(true)}, true},
+	} {
+		buf := &bytes.Buffer{}
+		router := gin.New()
+		router.Use(GinLoggerHandler(buf, tc.opts...))
+		router.POST("/test", func(ctx *gin.Context) {})
+
+		req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader("hello"))
+		req.Header.Set("Content-Type", gin.MIMEPlain)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		assert.Equal(t, tc.captured, strings.Contains(buf.String(), "[BODY] Content-Type text/plain - hello\n"))
+	}
+}
@@ -14,10 +14,15 @@ import (
 )
 
 const (
-	defaultFormat = "%{color}%{time:2006-01-02 15:04:05.000 MST} [%{module}] %{shortfunc} -> %{level:.4s} %{id:03x}%{color:reset} %{message}"
+	// DefaultFormat is the console format used when a format is not provided.
+	DefaultFormat = "%{color}%{time:2006-01-02 15:04:05.000 MST} [%{module}] %{shortfunc} -> %{level:.4s} %{id:03x}%{color:reset} %{message}"
 	defaultLevel  = zapcore.InfoLevel
 )
 
+// Global is the Logging instance used by the package level functions. It is
+// also installed as the gRPC logger with the name "grpc". As gRPC INFO entries
+// are logged at DEBUG and V(0) requires DEBUG, the INFO output that gRPC
+// writes by default is hidden unless the "grpc" logger is enabled for DEBUG.
 var Global *Logging
 var logger *FabricLogger
 
@@ -29,8 +34,7 @@ func init() {
 
 	Global = logging
 	logger = Global.Logger("flogging")
-	grpcLogger := Global.ZapLogger("grpc")
-	grpclog.SetLogger(NewGRPCLogger(grpcLogger))
+	grpclog.SetLoggerV2(Global.GRPCLogger("grpc"))
 }
 
 // Init initializes logging with the provided config.
@@ -46,6 +50,7 @@ func Init(config Config) {
 // Used in tests and in the package init
 func Reset() {
 	Global.Apply(Config{})
+	Global.LoggerLevels.resetCache()
 }
 
 // GetLoggerLevel gets the current logging level for the logger with the
@@ -60,9 +65,16 @@ func MustGetLogger(loggerName string) *FabricLogger {
 	return Global.Logger(loggerName)
 }
 
+// MustGetAuditLogger creates a logger for audit records with the specified
+// name. The active logging spec does not apply to it. If an invalid name is
+// provided, the operation will panic.
+func MustGetAuditLogger(loggerName string) *FabricLogger {
+	return Global.AuditLogger(loggerName)
+}
+
 // ActivateSpec is used to activate a logging specification.
 func ActivateSpec(spec string) {
-	err := Global.ActivateSpec(spec)
+	err := Global.ActivateSpecFrom(spec, SpecSourceAPI, callerLocation(1))
 	if err != nil {
 		panic(err)
 	}
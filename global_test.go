@@ -11,7 +11,11 @@ import (
 	"testing"
 
 	"github.com/redresseur/flogging"
+	"github.com/redresseur/flogging/fabenc"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 func TestGlobalReset(t *testing.T) {
@@ -81,6 +85,84 @@ func TestGlobalInitLogfmt(t *testing.T) {
 	assert.Regexp(t, `^ts=\d+.\d+ level=debug name=testlogger caller=flogging/global_test.go:\d+ msg="this is a message"`, buf.String())
 }
 
+func TestGlobalInitDev(t *testing.T) {
+	flogging.Reset()
+	defer flogging.Reset()
+
+	buf := &bytes.Buffer{}
+	flogging.Init(flogging.Config{
+		Format:  "dev",
+		LogSpec: "DEBUG",
+		Writer:  buf,
+	})
+	assert.Equal(t, flogging.Encoding(flogging.DEV), flogging.Global.Encoding())
+
+	logger := flogging.MustGetLogger("testlogger")
+	logger.Debugw("this is a message", "key", "value", "longer-key", 1)
+
+	assert.Regexp(t, `^\d{4}-\d{2}-\d{2} [^\[]+ \[testlogger\] TestGlobalInitDev -> DEBU \w+ this is a message\n    key        = value\n    longer-key = 1\n$`, buf.String())
+}
+
+func TestGlobalInitStructuredFormats(t *testing.T) {
+	flogging.Reset()
+	defer flogging.Reset()
+
+	var tests = []struct {
+		format   string
+		encoding flogging.Encoding
+		expected string
+	}{
+		{
+			format:   "ecs",
+			encoding: flogging.ECS,
+			expected: `^{"@timestamp":"[^"]+","log.level":"info","message":"this is a message","ecs.version":"1.6.0","log.logger":"testlogger",.*"service.name":"peer","key":"value"}\n$`,
+		},
+		{
+			format:   "otel",
+			encoding: flogging.OTEL,
+			expected: `^{"Timestamp":"\d+","SeverityText":"INFO","SeverityNumber":9,"Body":"this is a message","Resource":{"service.name":"peer"},"InstrumentationScope":{"Name":"testlogger"},"Attributes":{.*"key":"value"}}\n$`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.format, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			flogging.Init(flogging.Config{
+				Format:   tc.format,
+				Resource: map[string]string{"service.name": "peer"},
+				Writer:   buf,
+			})
+			assert.Equal(t, tc.encoding, flogging.Global.Encoding())
+
+			logger := flogging.MustGetLogger("testlogger")
+			logger.Infow("this is a message", "key", "value")
+			assert.Regexp(t, tc.expected, buf.String())
+		})
+	}
+}
+
+func TestGlobalInitCBOR(t *testing.T) {
+	flogging.Reset()
+	defer flogging.Reset()
+
+	buf := &bytes.Buffer{}
+	flogging.Init(flogging.Config{
+		Format: "cbor",
+		Writer: buf,
+	})
+	assert.Equal(t, flogging.Encoding(flogging.CBOR), flogging.Global.Encoding())
+
+	logger := flogging.MustGetLogger("testlogger")
+	logger.Infow("this is a message", "key", "value")
+
+	rec, err := fabenc.NewCBORDecoder(buf).Decode()
+	require.NoError(t, err)
+	assert.Equal(t, "testlogger", rec.Entry.LoggerName)
+	assert.Equal(t, "this is a message", rec.Entry.Message)
+	assert.Equal(t, zapcore.InfoLevel, rec.Entry.Level)
+	assert.Equal(t, []zapcore.Field{zap.String("key", "value")}, rec.Fields)
+}
+
 func TestGlobalInitPanic(t *testing.T) {
 	flogging.Reset()
 	defer flogging.Reset()
@@ -4,6 +4,8 @@ go 1.13
 
 require (
 	github.com/gin-gonic/gin v1.7.0
+	github.com/golang/protobuf v1.3.3
+	github.com/mattn/go-isatty v0.0.12
 	github.com/onsi/ginkgo v1.10.2
 	github.com/onsi/gomega v1.7.0
 	github.com/op/go-logging v0.0.0-20160315200505-970db520ece7
@@ -0,0 +1,701 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: admin.proto
+
+package grpcadmin
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type GetSpecRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetSpecRequest) Reset()         { *m = GetSpecRequest{} }
+func (m *GetSpecRequest) String() string { return proto.CompactTextString(m) }
+func (*GetSpecRequest) ProtoMessage()    {}
+func (*GetSpecRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_73a7fc70dcc2027c, []int{0}
+}
+
+func (m *GetSpecRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetSpecRequest.Unmarshal(m, b)
+}
+func (m *GetSpecRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetSpecRequest.Marshal(b, m, deterministic)
+}
+func (m *GetSpecRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetSpecRequest.Merge(m, src)
+}
+func (m *GetSpecRequest) XXX_Size() int {
+	return xxx_messageInfo_GetSpecRequest.Size(m)
+}
+func (m *GetSpecRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetSpecRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetSpecRequest proto.InternalMessageInfo
+
+// LogSpec is a logging spec and its version.
+type LogSpec struct {
+	Spec                 string   `protobuf:"bytes,1,opt,name=spec,proto3" json:"spec,omitempty"`
+	Version              string   `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LogSpec) Reset()         { *m = LogSpec{} }
+func (m *LogSpec) String() string { return proto.CompactTextString(m) }
+func (*LogSpec) ProtoMessage()    {}
+func (*LogSpec) Descriptor() ([]byte, []int) {
+	return fileDescriptor_73a7fc70dcc2027c, []int{1}
+}
+
+func (m *LogSpec) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogSpec.Unmarshal(m, b)
+}
+func (m *LogSpec) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogSpec.Marshal(b, m, deterministic)
+}
+func (m *LogSpec) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogSpec.Merge(m, src)
+}
+func (m *LogSpec) XXX_Size() int {
+	return xxx_messageInfo_LogSpec.Size(m)
+}
+func (m *LogSpec) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogSpec.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogSpec proto.InternalMessageInfo
+
+func (m *LogSpec) GetSpec() string {
+	if m != nil {
+		return m.Spec
+	}
+	return ""
+}
+
+func (m *LogSpec) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+type SetSpecRequest struct {
+	Spec                 string   `protobuf:"bytes,1,opt,name=spec,proto3" json:"spec,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetSpecRequest) Reset()         { *m = SetSpecRequest{} }
+func (m *SetSpecRequest) String() string { return proto.CompactTextString(m) }
+func (*SetSpecRequest) ProtoMessage()    {}
+func (*SetSpecRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_73a7fc70dcc2027c, []int{2}
+}
+
+func (m *SetSpecRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetSpecRequest.Unmarshal(m, b)
+}
+func (m *SetSpecRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetSpecRequest.Marshal(b, m, deterministic)
+}
+func (m *SetSpecRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetSpecRequest.Merge(m, src)
+}
+func (m *SetSpecRequest) XXX_Size() int {
+	return xxx_messageInfo_SetSpecRequest.Size(m)
+}
+func (m *SetSpecRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetSpecRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetSpecRequest proto.InternalMessageInfo
+
+func (m *SetSpecRequest) GetSpec() string {
+	if m != nil {
+		return m.Spec
+	}
+	return ""
+}
+
+type ListLoggersRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListLoggersRequest) Reset()         { *m = ListLoggersRequest{} }
+func (m *ListLoggersRequest) String() string { return proto.CompactTextString(m) }
+func (*ListLoggersRequest) ProtoMessage()    {}
+func (*ListLoggersRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_73a7fc70dcc2027c, []int{3}
+}
+
+func (m *ListLoggersRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListLoggersRequest.Unmarshal(m, b)
+}
+func (m *ListLoggersRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListLoggersRequest.Marshal(b, m, deterministic)
+}
+func (m *ListLoggersRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListLoggersRequest.Merge(m, src)
+}
+func (m *ListLoggersRequest) XXX_Size() int {
+	return xxx_messageInfo_ListLoggersRequest.Size(m)
+}
+func (m *ListLoggersRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListLoggersRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListLoggersRequest proto.InternalMessageInfo
+
+// LoggerInfo describes a logger known to the logging system.
+type LoggerInfo struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Level                string   `protobuf:"bytes,2,opt,name=level,proto3" json:"level,omitempty"`
+	Segment              string   `protobuf:"bytes,3,opt,name=segment,proto3" json:"segment,omitempty"`
+	Default              bool     `protobuf:"varint,4,opt,name=default,proto3" json:"default,omitempty"`
+	Entries              uint64   `protobuf:"varint,5,opt,name=entries,proto3" json:"entries,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LoggerInfo) Reset()         { *m = LoggerInfo{} }
+func (m *LoggerInfo) String() string { return proto.CompactTextString(m) }
+func (*LoggerInfo) ProtoMessage()    {}
+func (*LoggerInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_73a7fc70dcc2027c, []int{4}
+}
+
+func (m *LoggerInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LoggerInfo.Unmarshal(m, b)
+}
+func (m *LoggerInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LoggerInfo.Marshal(b, m, deterministic)
+}
+func (m *LoggerInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LoggerInfo.Merge(m, src)
+}
+func (m *LoggerInfo) XXX_Size() int {
+	return xxx_messageInfo_LoggerInfo.Size(m)
+}
+func (m *LoggerInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_LoggerInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LoggerInfo proto.InternalMessageInfo
+
+func (m *LoggerInfo) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *LoggerInfo) GetLevel() string {
+	if m != nil {
+		return m.Level
+	}
+	return ""
+}
+
+func (m *LoggerInfo) GetSegment() string {
+	if m != nil {
+		return m.Segment
+	}
+	return ""
+}
+
+func (m *LoggerInfo) GetDefault() bool {
+	if m != nil {
+		return m.Default
+	}
+	return false
+}
+
+func (m *LoggerInfo) GetEntries() uint64 {
+	if m != nil {
+		return m.Entries
+	}
+	return 0
+}
+
+type LoggerList struct {
+	Loggers              []*LoggerInfo `protobuf:"bytes,1,rep,name=loggers,proto3" json:"loggers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *LoggerList) Reset()         { *m = LoggerList{} }
+func (m *LoggerList) String() string { return proto.CompactTextString(m) }
+func (*LoggerList) ProtoMessage()    {}
+func (*LoggerList) Descriptor() ([]byte, []int) {
+	return fileDescriptor_73a7fc70dcc2027c, []int{5}
+}
+
+func (m *LoggerList) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LoggerList.Unmarshal(m, b)
+}
+func (m *LoggerList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LoggerList.Marshal(b, m, deterministic)
+}
+func (m *LoggerList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LoggerList.Merge(m, src)
+}
+func (m *LoggerList) XXX_Size() int {
+	return xxx_messageInfo_LoggerList.Size(m)
+}
+func (m *LoggerList) XXX_DiscardUnknown() {
+	xxx_messageInfo_LoggerList.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LoggerList proto.InternalMessageInfo
+
+func (m *LoggerList) GetLoggers() []*LoggerInfo {
+	if m != nil {
+		return m.Loggers
+	}
+	return nil
+}
+
+// StreamLogsRequest selects the streamed entries. Empty fields select all
+// entries.
+type StreamLogsRequest struct {
+	// level is the minimum level of the entries.
+	Level string `protobuf:"bytes,1,opt,name=level,proto3" json:"level,omitempty"`
+	// logger is the logger name prefix of the entries.
+	Logger               string   `protobuf:"bytes,2,opt,name=logger,proto3" json:"logger,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StreamLogsRequest) Reset()         { *m = StreamLogsRequest{} }
+func (m *StreamLogsRequest) String() string { return proto.CompactTextString(m) }
+func (*StreamLogsRequest) ProtoMessage()    {}
+func (*StreamLogsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_73a7fc70dcc2027c, []int{6}
+}
+
+func (m *StreamLogsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StreamLogsRequest.Unmarshal(m, b)
+}
+func (m *StreamLogsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StreamLogsRequest.Marshal(b, m, deterministic)
+}
+func (m *StreamLogsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StreamLogsRequest.Merge(m, src)
+}
+func (m *StreamLogsRequest) XXX_Size() int {
+	return xxx_messageInfo_StreamLogsRequest.Size(m)
+}
+func (m *StreamLogsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StreamLogsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StreamLogsRequest proto.InternalMessageInfo
+
+func (m *StreamLogsRequest) GetLevel() string {
+	if m != nil {
+		return m.Level
+	}
+	return ""
+}
+
+func (m *StreamLogsRequest) GetLogger() string {
+	if m != nil {
+		return m.Logger
+	}
+	return ""
+}
+
+// LogEntry is an entry written by the logging system.
+type LogEntry struct {
+	Time    *timestamp.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	Level   string               `protobuf:"bytes,2,opt,name=level,proto3" json:"level,omitempty"`
+	Logger  string               `protobuf:"bytes,3,opt,name=logger,proto3" json:"logger,omitempty"`
+	Message string               `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	Caller  string               `protobuf:"bytes,5,opt,name=caller,proto3" json:"caller,omitempty"`
+	// fields is the JSON object of the structured fields of the entry.
+	Fields string `protobuf:"bytes,6,opt,name=fields,proto3" json:"fields,omitempty"`
+	// dropped is the number of entries dropped before this entry because the
+	// client did not keep up.
+	Dropped              uint64   `protobuf:"varint,7,opt,name=dropped,proto3" json:"dropped,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LogEntry) Reset()         { *m = LogEntry{} }
+func (m *LogEntry) String() string { return proto.CompactTextString(m) }
+func (*LogEntry) ProtoMessage()    {}
+func (*LogEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_73a7fc70dcc2027c, []int{7}
+}
+
+func (m *LogEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogEntry.Unmarshal(m, b)
+}
+func (m *LogEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogEntry.Marshal(b, m, deterministic)
+}
+func (m *LogEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogEntry.Merge(m, src)
+}
+func (m *LogEntry) XXX_Size() int {
+	return xxx_messageInfo_LogEntry.Size(m)
+}
+func (m *LogEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogEntry proto.InternalMessageInfo
+
+func (m *LogEntry) GetTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.Time
+	}
+	return nil
+}
+
+func (m *LogEntry) GetLevel() string {
+	if m != nil {
+		return m.Level
+	}
+	return ""
+}
+
+func (m *LogEntry) GetLogger() string {
+	if m != nil {
+		return m.Logger
+	}
+	return ""
+}
+
+func (m *LogEntry) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *LogEntry) GetCaller() string {
+	if m != nil {
+		return m.Caller
+	}
+	return ""
+}
+
+func (m *LogEntry) GetFields() string {
+	if m != nil {
+		return m.Fields
+	}
+	return ""
+}
+
+func (m *LogEntry) GetDropped() uint64 {
+	if m != nil {
+		return m.Dropped
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*GetSpecRequest)(nil), "grpcadmin.GetSpecRequest")
+	proto.RegisterType((*LogSpec)(nil), "grpcadmin.LogSpec")
+	proto.RegisterType((*SetSpecRequest)(nil), "grpcadmin.SetSpecRequest")
+	proto.RegisterType((*ListLoggersRequest)(nil), "grpcadmin.ListLoggersRequest")
+	proto.RegisterType((*LoggerInfo)(nil), "grpcadmin.LoggerInfo")
+	proto.RegisterType((*LoggerList)(nil), "grpcadmin.LoggerList")
+	proto.RegisterType((*StreamLogsRequest)(nil), "grpcadmin.StreamLogsRequest")
+	proto.RegisterType((*LogEntry)(nil), "grpcadmin.LogEntry")
+}
+
+func init() { proto.RegisterFile("admin.proto", fileDescriptor_73a7fc70dcc2027c) }
+
+var fileDescriptor_73a7fc70dcc2027c = []byte{
+	// 471 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x52, 0xc1, 0x8e, 0xd3, 0x30,
+	0x10, 0x55, 0x76, 0xdb, 0x66, 0x3b, 0x95, 0x56, 0x60, 0x16, 0x64, 0x22, 0x10, 0x55, 0xc4, 0x21,
+	0xe2, 0x90, 0xa0, 0x72, 0x60, 0x2f, 0x1c, 0x0a, 0x42, 0x08, 0x29, 0xa7, 0x84, 0x13, 0xb7, 0x34,
+	0x99, 0x98, 0x48, 0x49, 0x1c, 0x6c, 0x67, 0x25, 0xce, 0xf0, 0x79, 0x7c, 0x14, 0xb2, 0x1d, 0x6f,
+	0x53, 0x76, 0x11, 0x37, 0xbf, 0xf1, 0x3c, 0xcf, 0xf3, 0x7b, 0x03, 0x9b, 0xa2, 0xea, 0x9a, 0x3e,
+	0x1e, 0x04, 0x57, 0x9c, 0xac, 0x99, 0x18, 0x4a, 0x53, 0x08, 0x5e, 0x30, 0xce, 0x59, 0x8b, 0x89,
+	0xb9, 0x38, 0x8c, 0x75, 0xa2, 0x9a, 0x0e, 0xa5, 0x2a, 0xba, 0xc1, 0xf6, 0x86, 0x0f, 0xe0, 0xf2,
+	0x13, 0xaa, 0x7c, 0xc0, 0x32, 0xc3, 0xef, 0x23, 0x4a, 0x15, 0xbe, 0x05, 0x3f, 0xe5, 0x4c, 0x57,
+	0x08, 0x81, 0x85, 0x1c, 0xb0, 0xa4, 0xde, 0xd6, 0x8b, 0xd6, 0x99, 0x39, 0x13, 0x0a, 0xfe, 0x0d,
+	0x0a, 0xd9, 0xf0, 0x9e, 0x9e, 0x99, 0xb2, 0x83, 0xe1, 0x4b, 0xb8, 0xcc, 0x4f, 0x9e, 0xba, 0x8f,
+	0x1f, 0x5e, 0x01, 0x49, 0x1b, 0xa9, 0x52, 0xce, 0x18, 0x0a, 0xe9, 0x86, 0xfe, 0xf2, 0x00, 0x6c,
+	0xe9, 0x73, 0x5f, 0x73, 0x4d, 0xec, 0x8b, 0x0e, 0x1d, 0x51, 0x9f, 0xc9, 0x15, 0x2c, 0x5b, 0xbc,
+	0xc1, 0x76, 0x1a, 0x6b, 0x81, 0x96, 0x23, 0x91, 0x75, 0xd8, 0x2b, 0x7a, 0x6e, 0xe5, 0x4c, 0x50,
+	0xdf, 0x54, 0x58, 0x17, 0x63, 0xab, 0xe8, 0x62, 0xeb, 0x45, 0x17, 0x99, 0x83, 0xfa, 0x06, 0x7b,
+	0x25, 0x1a, 0x94, 0x74, 0xb9, 0xf5, 0xa2, 0x45, 0xe6, 0x60, 0xf8, 0xce, 0xa9, 0xd0, 0x12, 0x49,
+	0x02, 0x7e, 0x6b, 0x65, 0x52, 0x6f, 0x7b, 0x1e, 0x6d, 0x76, 0x8f, 0xe3, 0x5b, 0x67, 0xe3, 0xa3,
+	0xda, 0xcc, 0x75, 0x85, 0x7b, 0x78, 0x98, 0x2b, 0x81, 0x45, 0x97, 0x72, 0xe6, 0xbe, 0x76, 0xd4,
+	0xed, 0xcd, 0x75, 0x3f, 0x81, 0x95, 0x65, 0x4d, 0xdf, 0x99, 0x50, 0xf8, 0xdb, 0x83, 0x8b, 0x94,
+	0xb3, 0x8f, 0xbd, 0x12, 0x3f, 0x48, 0x0c, 0x0b, 0x9d, 0x97, 0x61, 0x6e, 0x76, 0x41, 0x6c, 0xc3,
+	0x8c, 0x5d, 0x98, 0xf1, 0x17, 0x17, 0x66, 0x66, 0xfa, 0xfe, 0x61, 0xd1, 0x71, 0xd4, 0xf9, 0x7c,
+	0x94, 0xb6, 0xa1, 0x43, 0x29, 0x0b, 0x86, 0xc6, 0xa0, 0x75, 0xe6, 0xa0, 0x66, 0x94, 0x45, 0xdb,
+	0xa2, 0x30, 0xfe, 0xac, 0xb3, 0x09, 0xe9, 0x7a, 0xdd, 0x60, 0x5b, 0x49, 0xba, 0xb2, 0x75, 0x8b,
+	0x8c, 0xd5, 0x82, 0x0f, 0x03, 0x56, 0xd4, 0xb7, 0x86, 0x4e, 0x70, 0xf7, 0xf3, 0x0c, 0x96, 0x7b,
+	0xed, 0x17, 0xb9, 0x06, 0x7f, 0x5a, 0x34, 0xf2, 0x74, 0x66, 0xe3, 0xe9, 0xf2, 0x05, 0xe4, 0xd4,
+	0x61, 0xd3, 0x7e, 0x0d, 0x7e, 0x7e, 0x0f, 0x33, 0xff, 0x3f, 0xf3, 0x03, 0x6c, 0x66, 0xbb, 0x46,
+	0x9e, 0xcf, 0x5b, 0xee, 0xec, 0x60, 0x70, 0x37, 0x5d, 0xb3, 0x05, 0x7b, 0x80, 0x63, 0xa8, 0xe4,
+	0xd9, 0x5c, 0xc1, 0xdf, 0x59, 0x07, 0x8f, 0x4e, 0x9f, 0x30, 0x29, 0xbe, 0xf6, 0xde, 0xbf, 0xfa,
+	0x1a, 0xb1, 0x46, 0x7d, 0x1b, 0x0f, 0x71, 0xc9, 0xbb, 0x44, 0x60, 0x25, 0x50, 0x4a, 0x1c, 0x45,
+	0x52, 0xeb, 0x28, 0x9a, 0x9e, 0x25, 0xb7, 0xb4, 0xc3, 0xca, 0xa4, 0xfb, 0xe6, 0xcf, 0x00, 0x3a,
+	0xd8, 0x38, 0x3d, 0xd2, 0x03, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ *grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// AdminClient is the client API for Admin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type AdminClient interface {
+	// GetSpec returns the active logging spec.
+	GetSpec(ctx context.Context, in *GetSpecRequest, opts ...grpc.CallOption) (*LogSpec, error)
+	// SetSpec activates a logging spec.
+	SetSpec(ctx context.Context, in *SetSpecRequest, opts ...grpc.CallOption) (*LogSpec, error)
+	// ListLoggers returns the known loggers.
+	ListLoggers(ctx context.Context, in *ListLoggersRequest, opts ...grpc.CallOption) (*LoggerList, error)
+	// StreamLogs streams the entries written by the logging system until the
+	// call is cancelled.
+	StreamLogs(ctx context.Context, in *StreamLogsRequest, opts ...grpc.CallOption) (Admin_StreamLogsClient, error)
+}
+
+type adminClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewAdminClient(cc *grpc.ClientConn) AdminClient {
+	return &adminClient{cc}
+}
+
+func (c *adminClient) GetSpec(ctx context.Context, in *GetSpecRequest, opts ...grpc.CallOption) (*LogSpec, error) {
+	out := new(LogSpec)
+	err := c.cc.Invoke(ctx, "/grpcadmin.Admin/GetSpec", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) SetSpec(ctx context.Context, in *SetSpecRequest, opts ...grpc.CallOption) (*LogSpec, error) {
+	out := new(LogSpec)
+	err := c.cc.Invoke(ctx, "/grpcadmin.Admin/SetSpec", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ListLoggers(ctx context.Context, in *ListLoggersRequest, opts ...grpc.CallOption) (*LoggerList, error) {
+	out := new(LoggerList)
+	err := c.cc.Invoke(ctx, "/grpcadmin.Admin/ListLoggers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) StreamLogs(ctx context.Context, in *StreamLogsRequest, opts ...grpc.CallOption) (Admin_StreamLogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Admin_serviceDesc.Streams[0], "/grpcadmin.Admin/StreamLogs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &adminStreamLogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Admin_StreamLogsClient interface {
+	Recv() (*LogEntry, error)
+	grpc.ClientStream
+}
+
+type adminStreamLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *adminStreamLogsClient) Recv() (*LogEntry, error) {
+	m := new(LogEntry)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// AdminServer is the server API for Admin service.
+type AdminServer interface {
+	// GetSpec returns the active logging spec.
+	GetSpec(context.Context, *GetSpecRequest) (*LogSpec, error)
+	// SetSpec activates a logging spec.
+	SetSpec(context.Context, *SetSpecRequest) (*LogSpec, error)
+	// ListLoggers returns the known loggers.
+	ListLoggers(context.Context, *ListLoggersRequest) (*LoggerList, error)
+	// StreamLogs streams the entries written by the logging system until the
+	// call is cancelled.
+	StreamLogs(*StreamLogsRequest, Admin_StreamLogsServer) error
+}
+
+// UnimplementedAdminServer can be embedded to have forward compatible implementations.
+type UnimplementedAdminServer struct {
+}
+
+func (*UnimplementedAdminServer) GetSpec(ctx context.Context, req *GetSpecRequest) (*LogSpec, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSpec not implemented")
+}
+func (*UnimplementedAdminServer) SetSpec(ctx context.Context, req *SetSpecRequest) (*LogSpec, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetSpec not implemented")
+}
+func (*UnimplementedAdminServer) ListLoggers(ctx context.Context, req *ListLoggersRequest) (*LoggerList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLoggers not implemented")
+}
+func (*UnimplementedAdminServer) StreamLogs(req *StreamLogsRequest, srv Admin_StreamLogsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamLogs not implemented")
+}
+
+func RegisterAdminServer(s *grpc.Server, srv AdminServer) {
+	s.RegisterService(&_Admin_serviceDesc, srv)
+}
+
+func _Admin_GetSpec_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSpecRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).GetSpec(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpcadmin.Admin/GetSpec",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).GetSpec(ctx, req.(*GetSpecRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_SetSpec_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetSpecRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).SetSpec(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpcadmin.Admin/SetSpec",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).SetSpec(ctx, req.(*SetSpecRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ListLoggers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLoggersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ListLoggers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpcadmin.Admin/ListLoggers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ListLoggers(ctx, req.(*ListLoggersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_StreamLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamLogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AdminServer).StreamLogs(m, &adminStreamLogsServer{stream})
+}
+
+type Admin_StreamLogsServer interface {
+	Send(*LogEntry) error
+	grpc.ServerStream
+}
+
+type adminStreamLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *adminStreamLogsServer) Send(m *LogEntry) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _Admin_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "grpcadmin.Admin",
+	HandlerType: (*AdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetSpec",
+			Handler:    _Admin_GetSpec_Handler,
+		},
+		{
+			MethodName: "SetSpec",
+			Handler:    _Admin_SetSpec_Handler,
+		},
+		{
+			MethodName: "ListLoggers",
+			Handler:    _Admin_ListLoggers_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamLogs",
+			Handler:       _Admin_StreamLogs_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "admin.proto",
+}
@@ -0,0 +1,316 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package grpcadmin
+
+//go:generate protoc --go_out=plugins=grpc,paths=source_relative:. admin.proto
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/redresseur/flogging"
+	"github.com/redresseur/flogging/httpadmin"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// defaultStreamBuffer is the number of entries buffered for a StreamLogs
+// call before entries are dropped.
+const defaultStreamBuffer = 256
+
+// NewServer creates a Server for the global logging system that requires
+// its clients to be identified by one of the authenticators.
+func NewServer(authenticators ...httpadmin.Authenticator) *Server {
+	return &Server{
+		Logging:        flogging.Global,
+		Registry:       flogging.Global,
+		Streamer:       flogging.Global,
+		Authenticators: authenticators,
+		Logger:         flogging.MustGetLogger("flogging.grpcadmin"),
+		AuditLogger:    flogging.MustGetAuditLogger("flogging.grpcadmin.audit"),
+	}
+}
+
+// Server implements the Admin service with the interfaces used by the
+// httpadmin handlers.
+//
+// Every call must be made by a client that is identified by one of the
+// authenticators; a Server without authenticators rejects all calls unless
+// AllowUnauthenticated is set. The authenticators are the ones used by
+// httpadmin: the request passed to them carries the "authorization" metadata
+// of the call as its Authorization header and the TLS state and address of
+// the peer. GetSpec and ListLoggers require the ReadOnly role; SetSpec and
+// StreamLogs, which changes the spec and exposes log entries, require the
+// ReadWrite role and are recorded in the audit log.
+type Server struct {
+	Logging        httpadmin.Logging
+	Registry       httpadmin.LoggerRegistry
+	Streamer       httpadmin.EntryStreamer
+	Authenticators []httpadmin.Authenticator
+	// AllowUnauthenticated serves calls from every client as a ReadWrite
+	// client. It must only be set when the server is protected by other
+	// means.
+	AllowUnauthenticated bool
+	Logger               *flogging.FabricLogger
+	AuditLogger          *flogging.FabricLogger
+	// Buffer is the number of entries buffered for a StreamLogs call. It
+	// defaults to 256.
+	Buffer int
+}
+
+// GetSpec returns the active logging spec. The version is only set when the
+// logging system is a httpadmin.VersionedLogging.
+func (s *Server) GetSpec(ctx context.Context, req *GetSpecRequest) (*LogSpec, error) {
+	if _, err := s.authorize(ctx, httpadmin.ReadOnly, "GetSpec"); err != nil {
+		return nil, err
+	}
+	if vl, ok := s.Logging.(httpadmin.VersionedLogging); ok {
+		current := vl.VersionedSpec()
+		return &LogSpec{Spec: current.Spec, Version: current.Version}, nil
+	}
+	return &LogSpec{Spec: s.Logging.Spec()}, nil
+}
+
+// SetSpec activates a logging spec. When the logging system is a
+// httpadmin.VersionedLogging, the client of the call is recorded in the spec
+// history.
+func (s *Server) SetSpec(ctx context.Context, req *SetSpecRequest) (*LogSpec, error) {
+	principal, err := s.authorize(ctx, httpadmin.ReadWrite, "SetSpec")
+	if err != nil {
+		return nil, err
+	}
+	spec, err := s.setSpec(ctx, principal, req.Spec)
+	s.AuditLogger.Infow("admin call",
+		"principal", principal.Name, "role", principal.Role.String(),
+		"method", "SetSpec", "remote", peerAddr(ctx), "spec", req.Spec,
+		"code", status.Code(err).String(),
+	)
+	return spec, err
+}
+
+func (s *Server) setSpec(ctx context.Context, principal httpadmin.Principal, spec string) (*LogSpec, error) {
+	vl, ok := s.Logging.(httpadmin.VersionedLogging)
+	if !ok {
+		if err := s.Logging.ActivateSpec(spec); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return &LogSpec{Spec: s.Logging.Spec()}, nil
+	}
+
+	caller := principal.Name
+	if caller == "" {
+		caller = peerCaller(ctx)
+	}
+	result, err := vl.ReplaceSpecFrom(spec, "", flogging.SpecSourceGRPCAdmin, caller)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &LogSpec{Spec: result.Spec, Version: result.Version}, nil
+}
+
+// ListLoggers returns the known loggers with their effective level.
+func (s *Server) ListLoggers(ctx context.Context, req *ListLoggersRequest) (*LoggerList, error) {
+	if _, err := s.authorize(ctx, httpadmin.ReadOnly, "ListLoggers"); err != nil {
+		return nil, err
+	}
+	list := &LoggerList{}
+	for _, info := range s.Registry.Loggers() {
+		list.Loggers = append(list.Loggers, &LoggerInfo{
+			Name:    info.Name,
+			Level:   flogging.LevelName(info.Level),
+			Segment: info.Segment,
+			Default: info.Default,
+			Entries: info.Entries,
+		})
+	}
+	return list, nil
+}
+
+// StreamLogs sends the entries selected by the request until the call is
+// cancelled. Entries are dropped when the client does not keep up and the
+// number of dropped entries is reported with the next entry.
+func (s *Server) StreamLogs(req *StreamLogsRequest, stream Admin_StreamLogsServer) error {
+	ctx := stream.Context()
+	principal, err := s.authorize(ctx, httpadmin.ReadWrite, "StreamLogs")
+	if err != nil {
+		return err
+	}
+
+	kvPairs := []interface{}{
+		"principal", principal.Name, "role", principal.Role.String(),
+		"method", "StreamLogs", "remote", peerAddr(ctx),
+		"level", req.Level, "logger", req.Logger,
+	}
+	s.AuditLogger.Infow("admin call started", kvPairs...)
+	err = s.streamLogs(req, stream)
+	s.AuditLogger.Infow("admin call", append(kvPairs, "code", status.Code(err).String())...)
+	return err
+}
+
+func (s *Server) streamLogs(req *StreamLogsRequest, stream Admin_StreamLogsServer) error {
+	minLevel := flogging.PayloadLevel
+	if req.Level != "" {
+		if !flogging.IsValidLevel(req.Level) {
+			return status.Errorf(codes.InvalidArgument, "invalid level: %s", req.Level)
+		}
+		minLevel = flogging.NameToLevel(req.Level)
+	}
+
+	size := s.Buffer
+	if size <= 0 {
+		size = defaultStreamBuffer
+	}
+	entries := make(chan *LogEntry, size)
+	var dropped uint64
+
+	cancel := s.Streamer.SubscribeEntries(func(e zapcore.Entry, fields []zapcore.Field) {
+		if e.Level < minLevel || !hasLoggerPrefix(e.LoggerName, req.Logger) {
+			return
+		}
+		select {
+		case entries <- newLogEntry(e, fields):
+		default:
+			atomic.AddUint64(&dropped, 1)
+		}
+	})
+	defer cancel()
+
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case entry := <-entries:
+			entry.Dropped = atomic.SwapUint64(&dropped, 0)
+			if err := stream.Send(entry); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func newLogEntry(e zapcore.Entry, fields []zapcore.Field) *LogEntry {
+	entry := &LogEntry{
+		Level:   flogging.LevelName(e.Level),
+		Logger:  e.LoggerName,
+		Message: e.Message,
+	}
+	if ts, err := ptypes.TimestampProto(e.Time); err == nil {
+		entry.Time = ts
+	}
+	if e.Caller.Defined {
+		entry.Caller = e.Caller.TrimmedPath()
+	}
+	if len(fields) > 0 {
+		enc := zapcore.NewMapObjectEncoder()
+		for _, f := range fields {
+			f.AddTo(enc)
+		}
+		if b, err := json.Marshal(enc.Fields); err == nil {
+			entry.Fields = string(b)
+		}
+	}
+	return entry
+}
+
+// hasLoggerPrefix returns true when the prefix is empty or the logger is
+// named prefix or is one of its descendants.
+func hasLoggerPrefix(name, prefix string) bool {
+	return prefix == "" || name == prefix || strings.HasPrefix(name, prefix+".")
+}
+
+// authorize authenticates the client of a call and checks that its role
+// permits the method. Rejected calls are recorded in the audit log.
+func (s *Server) authorize(ctx context.Context, required httpadmin.Role, method string) (httpadmin.Principal, error) {
+	if s.AllowUnauthenticated {
+		return httpadmin.Principal{Role: httpadmin.ReadWrite}, nil
+	}
+
+	req := authRequest(ctx)
+	var principal httpadmin.Principal
+	var found bool
+	for _, a := range s.Authenticators {
+		if principal, found = a.Authenticate(req); found {
+			break
+		}
+	}
+	if !found {
+		s.AuditLogger.Warnw("unauthenticated call rejected", "method", method, "remote", req.RemoteAddr)
+		return httpadmin.Principal{}, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	if !permits(principal.Role, required) {
+		s.AuditLogger.Warnw("unauthorized call rejected",
+			"principal", principal.Name, "role", principal.Role.String(),
+			"method", method, "remote", req.RemoteAddr,
+		)
+		return httpadmin.Principal{}, status.Errorf(codes.PermissionDenied, "permission denied: %s is %s", principal.Name, principal.Role)
+	}
+	return principal, nil
+}
+
+// permits returns true when a role grants the access of the required role.
+// The zero Role grants no access.
+func permits(role, required httpadmin.Role) bool {
+	switch role {
+	case httpadmin.ReadWrite:
+		return true
+	case httpadmin.ReadOnly:
+		return required == httpadmin.ReadOnly
+	default:
+		return false
+	}
+}
+
+// authRequest creates the request passed to the authenticators for a call.
+func authRequest(ctx context.Context) *http.Request {
+	req := &http.Request{Header: http.Header{}, RemoteAddr: peerAddr(ctx)}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for _, authorization := range md.Get("authorization") {
+			req.Header.Add("Authorization", authorization)
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			req.TLS = &tlsInfo.State
+		}
+	}
+	return req
+}
+
+// peerAddr returns the address of the peer of a call.
+func peerAddr(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+	return ""
+}
+
+// peerCaller identifies the client of a call for the spec history. The
+// common name of the client certificate is used when the client has been
+// authenticated with TLS; otherwise the peer address is used.
+func peerCaller(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(tlsInfo.State.PeerCertificates) > 0 {
+		return tlsInfo.State.PeerCertificates[0].Subject.CommonName
+	}
+	if p.Addr != nil {
+		return p.Addr.String()
+	}
+	return ""
+}
@@ -0,0 +1,395 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package grpcadmin_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/redresseur/flogging"
+	"github.com/redresseur/flogging/grpcadmin"
+	"github.com/redresseur/flogging/httpadmin"
+	"github.com/redresseur/flogging/httpadmin/fakes"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// bearerCredentials sends the token it points to as a bearer token.
+type bearerCredentials struct{ token *string }
+
+func (c bearerCredentials) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	if *c.token == "" {
+		return nil, nil
+	}
+	return map[string]string{"authorization": "Bearer " + *c.token}, nil
+}
+
+func (c bearerCredentials) RequireTransportSecurity() bool { return false }
+
+var _ = Describe("Server", func() {
+	var (
+		token        string
+		auditLog     *gbytes.Buffer
+		fakeLogging  *fakes.VersionedLogging
+		fakeRegistry *fakes.LoggerRegistry
+		fakeStreamer *fakes.EntryStreamer
+		subscribed   chan func(zapcore.Entry, []zapcore.Field)
+		cancelled    chan struct{}
+
+		server     *grpcadmin.Server
+		listener   *bufconn.Listener
+		grpcServer *grpc.Server
+		clientConn *grpc.ClientConn
+		client     grpcadmin.AdminClient
+	)
+
+	BeforeEach(func() {
+		fakeLogging = &fakes.VersionedLogging{}
+		fakeLogging.VersionedSpecReturns(flogging.VersionedSpec{Spec: "gossip=debug:info", Version: "0123456789abcdef"})
+		fakeLogging.ReplaceSpecFromReturns(flogging.VersionedSpec{Spec: "gossip=debug:info", Version: "fedcba9876543210"}, nil)
+		fakeRegistry = &fakes.LoggerRegistry{}
+
+		subscribed = make(chan func(zapcore.Entry, []zapcore.Field), 1)
+		cancelled = make(chan struct{})
+		sub, done := subscribed, cancelled
+		fakeStreamer = &fakes.EntryStreamer{}
+		fakeStreamer.SubscribeEntriesStub = func(fn func(zapcore.Entry, []zapcore.Field)) func() {
+			sub <- fn
+			return func() { close(done) }
+		}
+
+		token = "writer-token"
+		auditLog = gbytes.NewBuffer()
+		logging, err := flogging.New(flogging.Config{Format: "json", Writer: auditLog})
+		Expect(err).NotTo(HaveOccurred())
+
+		listener = bufconn.Listen(1024 * 1024)
+		server = &grpcadmin.Server{
+			Logging:  fakeLogging,
+			Registry: fakeRegistry,
+			Streamer: fakeStreamer,
+			Authenticators: []httpadmin.Authenticator{
+				&httpadmin.TokenAuthenticator{
+					Tokens: map[string]httpadmin.Principal{
+						"reader-token": {Name: "reader", Role: httpadmin.ReadOnly},
+						"writer-token": {Name: "writer", Role: httpadmin.ReadWrite},
+					},
+				},
+			},
+			Logger:      logging.Logger("grpcadmin"),
+			AuditLogger: logging.AuditLogger("audit"),
+		}
+		grpcServer = grpc.NewServer()
+		grpcadmin.RegisterAdminServer(grpcServer, server)
+		go grpcServer.Serve(listener)
+
+		clientConn, err = grpc.Dial("bufconn",
+			grpc.WithInsecure(),
+			grpc.WithPerRPCCredentials(bearerCredentials{token: &token}),
+			grpc.WithDialer(func(string, time.Duration) (net.Conn, error) { return listener.Dial() }),
+		)
+		Expect(err).NotTo(HaveOccurred())
+		client = grpcadmin.NewAdminClient(clientConn)
+	})
+
+	AfterEach(func() {
+		clientConn.Close()
+		grpcServer.Stop()
+		if fakeStreamer.SubscribeEntriesCallCount() > 0 {
+			Eventually(cancelled).Should(BeClosed())
+		}
+	})
+
+	Describe("GetSpec", func() {
+		It("returns the active spec and its version", func() {
+			spec, err := client.GetSpec(context.Background(), &grpcadmin.GetSpecRequest{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spec.Spec).To(Equal("gossip=debug:info"))
+			Expect(spec.Version).To(Equal("0123456789abcdef"))
+		})
+	})
+
+	Describe("SetSpec", func() {
+		It("activates the spec", func() {
+			spec, err := client.SetSpec(context.Background(), &grpcadmin.SetSpecRequest{Spec: "gossip=debug:info"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spec.Spec).To(Equal("gossip=debug:info"))
+			Expect(spec.Version).To(Equal("fedcba9876543210"))
+
+			Expect(fakeLogging.ReplaceSpecFromCallCount()).To(Equal(1))
+			activated, version, source, caller := fakeLogging.ReplaceSpecFromArgsForCall(0)
+			Expect(activated).To(Equal("gossip=debug:info"))
+			Expect(version).To(BeEmpty())
+			Expect(source).To(Equal(flogging.SpecSourceGRPCAdmin))
+			Expect(caller).To(Equal("writer"))
+			Expect(auditLog).To(gbytes.Say(`"msg":"admin call","principal":"writer","role":"read-write","method":"SetSpec","remote":"bufconn","spec":"gossip=debug:info","code":"OK"`))
+		})
+
+		Context("when the spec is invalid", func() {
+			BeforeEach(func() {
+				fakeLogging.ReplaceSpecFromReturns(flogging.VersionedSpec{}, errors.New("ewww; that's not right!"))
+			})
+
+			It("returns an invalid argument error", func() {
+				_, err := client.SetSpec(context.Background(), &grpcadmin.SetSpecRequest{Spec: "=="})
+				Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+				Expect(status.Convert(err).Message()).To(Equal("ewww; that's not right!"))
+			})
+		})
+	})
+
+	Context("when the client is not authenticated", func() {
+		BeforeEach(func() {
+			token = ""
+		})
+
+		It("rejects every call", func() {
+			_, err := client.GetSpec(context.Background(), &grpcadmin.GetSpecRequest{})
+			Expect(status.Code(err)).To(Equal(codes.Unauthenticated))
+			_, err = client.SetSpec(context.Background(), &grpcadmin.SetSpecRequest{Spec: "debug"})
+			Expect(status.Code(err)).To(Equal(codes.Unauthenticated))
+			_, err = client.ListLoggers(context.Background(), &grpcadmin.ListLoggersRequest{})
+			Expect(status.Code(err)).To(Equal(codes.Unauthenticated))
+			stream, err := client.StreamLogs(context.Background(), &grpcadmin.StreamLogsRequest{})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = stream.Recv()
+			Expect(status.Code(err)).To(Equal(codes.Unauthenticated))
+
+			Expect(fakeLogging.ReplaceSpecFromCallCount()).To(Equal(0))
+			Expect(fakeStreamer.SubscribeEntriesCallCount()).To(Equal(0))
+			Expect(auditLog).To(gbytes.Say(`"msg":"unauthenticated call rejected","method":"GetSpec","remote":"bufconn"`))
+		})
+
+		It("rejects unknown tokens", func() {
+			token = "writer"
+			_, err := client.GetSpec(context.Background(), &grpcadmin.GetSpecRequest{})
+			Expect(status.Code(err)).To(Equal(codes.Unauthenticated))
+			Expect(status.Convert(err).Message()).To(Equal("authentication required"))
+		})
+	})
+
+	Context("when the client is read-only", func() {
+		BeforeEach(func() {
+			token = "reader-token"
+		})
+
+		It("allows reads", func() {
+			_, err := client.GetSpec(context.Background(), &grpcadmin.GetSpecRequest{})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = client.ListLoggers(context.Background(), &grpcadmin.ListLoggersRequest{})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("rejects changes and streams", func() {
+			_, err := client.SetSpec(context.Background(), &grpcadmin.SetSpecRequest{Spec: "debug"})
+			Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+			Expect(status.Convert(err).Message()).To(Equal("permission denied: reader is read-only"))
+			stream, err := client.StreamLogs(context.Background(), &grpcadmin.StreamLogsRequest{})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = stream.Recv()
+			Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+
+			Expect(fakeLogging.ReplaceSpecFromCallCount()).To(Equal(0))
+			Expect(fakeStreamer.SubscribeEntriesCallCount()).To(Equal(0))
+			Expect(auditLog).To(gbytes.Say(`"msg":"unauthorized call rejected","principal":"reader","role":"read-only","method":"SetSpec"`))
+			Expect(auditLog).To(gbytes.Say(`"msg":"unauthorized call rejected","principal":"reader","role":"read-only","method":"StreamLogs"`))
+		})
+	})
+
+	Context("when the client has no role", func() {
+		BeforeEach(func() {
+			server.Authenticators = []httpadmin.Authenticator{
+				httpadmin.AuthenticatorFunc(func(req *http.Request) (httpadmin.Principal, bool) {
+					return httpadmin.Principal{Name: "nobody"}, true
+				}),
+			}
+		})
+
+		It("rejects reads", func() {
+			_, err := client.GetSpec(context.Background(), &grpcadmin.GetSpecRequest{})
+			Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+			Expect(status.Convert(err).Message()).To(Equal("permission denied: nobody is Role(0)"))
+		})
+	})
+
+	Context("when the server has no authenticators", func() {
+		BeforeEach(func() {
+			server.Authenticators = nil
+		})
+
+		It("rejects every client", func() {
+			_, err := client.GetSpec(context.Background(), &grpcadmin.GetSpecRequest{})
+			Expect(status.Code(err)).To(Equal(codes.Unauthenticated))
+		})
+
+		It("serves every client when unauthenticated calls are allowed", func() {
+			server.AllowUnauthenticated = true
+			token = ""
+			_, err := client.SetSpec(context.Background(), &grpcadmin.SetSpecRequest{Spec: "gossip=debug:info"})
+			Expect(err).NotTo(HaveOccurred())
+			_, _, _, caller := fakeLogging.ReplaceSpecFromArgsForCall(0)
+			Expect(caller).To(Equal("bufconn"))
+		})
+	})
+
+	Context("when the logging system is not versioned", func() {
+		var plainLogging *fakes.Logging
+
+		BeforeEach(func() {
+			plainLogging = &fakes.Logging{}
+			plainLogging.SpecReturns("gossip=debug:info")
+			server.Logging = plainLogging
+		})
+
+		It("activates the spec without a version", func() {
+			spec, err := client.SetSpec(context.Background(), &grpcadmin.SetSpecRequest{Spec: "gossip=debug:info"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spec.Spec).To(Equal("gossip=debug:info"))
+			Expect(spec.Version).To(BeEmpty())
+
+			Expect(plainLogging.ActivateSpecCallCount()).To(Equal(1))
+			Expect(plainLogging.ActivateSpecArgsForCall(0)).To(Equal("gossip=debug:info"))
+		})
+	})
+
+	Describe("ListLoggers", func() {
+		It("returns the known loggers", func() {
+			fakeRegistry.LoggersReturns([]flogging.LoggerInfo{
+				{Name: "gossip", Level: zapcore.DebugLevel, Segment: "gossip=debug", Entries: 12},
+				{Name: "ledger", Level: zapcore.InfoLevel, Segment: "info", Default: true},
+			})
+
+			list, err := client.ListLoggers(context.Background(), &grpcadmin.ListLoggersRequest{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(list.Loggers).To(HaveLen(2))
+			Expect(list.Loggers[0]).To(Equal(&grpcadmin.LoggerInfo{Name: "gossip", Level: "debug", Segment: "gossip=debug", Entries: 12}))
+			Expect(list.Loggers[1]).To(Equal(&grpcadmin.LoggerInfo{Name: "ledger", Level: "info", Segment: "info", Default: true}))
+		})
+
+		It("names the payload level", func() {
+			fakeRegistry.LoggersReturns([]flogging.LoggerInfo{
+				{Name: "gossip", Level: flogging.PayloadLevel, Segment: "gossip=payload"},
+			})
+
+			list, err := client.ListLoggers(context.Background(), &grpcadmin.ListLoggersRequest{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(list.Loggers).To(Equal([]*grpcadmin.LoggerInfo{{Name: "gossip", Level: "payload", Segment: "gossip=payload"}}))
+		})
+	})
+
+	Describe("StreamLogs", func() {
+		It("streams the matching entries until the call is cancelled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			stream, err := client.StreamLogs(ctx, &grpcadmin.StreamLogsRequest{Level: "info", Logger: "gossip"})
+			Expect(err).NotTo(HaveOccurred())
+
+			var publish func(zapcore.Entry, []zapcore.Field)
+			Eventually(subscribed).Should(Receive(&publish))
+			when := time.Date(2019, 10, 7, 12, 0, 0, 0, time.UTC)
+			publish(zapcore.Entry{LoggerName: "gossip", Level: zapcore.DebugLevel, Message: "too low", Time: when}, nil)
+			publish(zapcore.Entry{LoggerName: "ledger", Level: zapcore.InfoLevel, Message: "other logger", Time: when}, nil)
+			publish(zapcore.Entry{LoggerName: "gossip.comm", Level: zapcore.WarnLevel, Message: "warning", Time: when}, []zapcore.Field{zap.Int("peers", 3)})
+
+			entry, err := stream.Recv()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entry.Level).To(Equal("warn"))
+			Expect(entry.Logger).To(Equal("gossip.comm"))
+			Expect(entry.Message).To(Equal("warning"))
+			Expect(entry.Fields).To(MatchJSON(`{"peers": 3}`))
+			Expect(entry.Dropped).To(BeZero())
+			t, err := ptypes.Timestamp(entry.Time)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(t).To(Equal(when))
+
+			cancel()
+			Eventually(cancelled).Should(BeClosed())
+			Expect(auditLog).To(gbytes.Say(`"msg":"admin call started","principal":"writer","role":"read-write","method":"StreamLogs","remote":"bufconn","level":"info","logger":"gossip"`))
+			Eventually(auditLog).Should(gbytes.Say(`"msg":"admin call",.*"method":"StreamLogs",.*"code":"OK"`))
+		})
+
+		It("reports dropped entries", func() {
+			server.Buffer = 1
+			done := cancelled
+			fakeStreamer.SubscribeEntriesStub = func(fn func(zapcore.Entry, []zapcore.Field)) func() {
+				for _, message := range []string{"kept", "dropped", "dropped"} {
+					fn(zapcore.Entry{LoggerName: "ledger", Level: zapcore.InfoLevel, Message: message}, nil)
+				}
+				return func() { close(done) }
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			stream, err := client.StreamLogs(ctx, &grpcadmin.StreamLogsRequest{})
+			Expect(err).NotTo(HaveOccurred())
+			entry, err := stream.Recv()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entry.Message).To(Equal("kept"))
+			Expect(entry.Dropped).To(Equal(uint64(2)))
+
+			cancel()
+			Eventually(cancelled).Should(BeClosed())
+		})
+
+		It("names the payload level", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			stream, err := client.StreamLogs(ctx, &grpcadmin.StreamLogsRequest{Level: "payload"})
+			Expect(err).NotTo(HaveOccurred())
+
+			var publish func(zapcore.Entry, []zapcore.Field)
+			Eventually(subscribed).Should(Receive(&publish))
+			publish(zapcore.Entry{LoggerName: "gossip", Level: flogging.PayloadLevel, Message: "payload"}, nil)
+
+			entry, err := stream.Recv()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entry.Level).To(Equal("payload"))
+
+			cancel()
+			Eventually(cancelled).Should(BeClosed())
+		})
+
+		It("rejects invalid levels", func() {
+			stream, err := client.StreamLogs(context.Background(), &grpcadmin.StreamLogsRequest{Level: "loud"})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = stream.Recv()
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+			Expect(status.Convert(err).Message()).To(Equal("invalid level: loud"))
+			Expect(fakeStreamer.SubscribeEntriesCallCount()).To(Equal(0))
+		})
+	})
+
+	Describe("NewServer", func() {
+		It("constructs a server for the global logging system", func() {
+			server := grpcadmin.NewServer()
+			Expect(server.Logging).To(Equal(flogging.Global))
+			Expect(server.Registry).To(Equal(flogging.Global))
+			Expect(server.Streamer).To(Equal(flogging.Global))
+			Expect(server.Authenticators).To(BeEmpty())
+			Expect(server.Logger).NotTo(BeNil())
+			Expect(server.AuditLogger).NotTo(BeNil())
+
+			authenticator := &httpadmin.TokenAuthenticator{}
+			server = grpcadmin.NewServer(authenticator)
+			Expect(server.Authenticators).To(Equal([]httpadmin.Authenticator{authenticator}))
+		})
+	})
+})
@@ -0,0 +1,103 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package flogging
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/grpclog"
+)
+
+var _ grpclog.LoggerV2 = &GRPCLoggerV2{}
+
+// A GRPCLoggerV2 is a grpclog.LoggerV2 that delegates to a FabricLogger. The
+// gRPC severities are mapped onto flogging levels as follows:
+//
+//	INFO     DEBUG
+//	WARNING  WARN
+//	ERROR    ERROR
+//	FATAL    FATAL
+//
+// gRPC logs connection and resolver state changes at INFO. They are logged
+// at DEBUG so that they are not written with the default INFO spec.
+//
+// Verbose logging is enabled with V(0) when DEBUG is enabled and with V(l)
+// for l > 0 when PAYLOAD is enabled.
+//
+// The caller reported for entries logged with the LoggerV2 methods is the
+// caller of the grpclog package. When gRPC logs through a helper of its own,
+// the helper is reported instead. The InfoDepth, WarningDepth, ErrorDepth and
+// FatalDepth methods also skip the number of frames they are given above the
+// caller of the grpclog package; gRPC only uses them in releases that define
+// grpclog.DepthLoggerV2.
+type GRPCLoggerV2 struct {
+	s       *zap.SugaredLogger
+	enabler zapcore.LevelEnabler
+}
+
+// NewGRPCLoggerV2 creates a GRPCLoggerV2 that delegates to the FabricLogger.
+// The caller reported for entries is the caller of the grpclog package. The
+// enabler determines the verbosity reported by V; when it is nil, the levels
+// enabled by the logger are used.
+func NewGRPCLoggerV2(l *FabricLogger, enabler zapcore.LevelEnabler) *GRPCLoggerV2 {
+	if enabler == nil {
+		enabler = zap.LevelEnablerFunc(l.IsEnabledFor)
+	}
+	return &GRPCLoggerV2{
+		s:       l.s.Desugar().WithOptions(zap.AddCallerSkip(1)).Sugar(),
+		enabler: enabler,
+	}
+}
+
+// GRPCLogger creates a GRPCLoggerV2 for the named logger. The verbosity
+// reported by V follows the level of the logger in the active spec.
+func (s *Logging) GRPCLogger(name string) *GRPCLoggerV2 {
+	enabler := zap.LevelEnablerFunc(func(l zapcore.Level) bool {
+		return s.LoggerLevels.Level(name).Enabled(l)
+	})
+	return NewGRPCLoggerV2(s.Logger(name), enabler)
+}
+
+func (g *GRPCLoggerV2) Info(args ...interface{})                    { g.s.Debug(args...) }
+func (g *GRPCLoggerV2) Infoln(args ...interface{})                  { g.s.Debug(formatArgs(args)) }
+func (g *GRPCLoggerV2) Infof(format string, args ...interface{})    { g.s.Debugf(format, args...) }
+func (g *GRPCLoggerV2) Warning(args ...interface{})                 { g.s.Warn(args...) }
+func (g *GRPCLoggerV2) Warningln(args ...interface{})               { g.s.Warn(formatArgs(args)) }
+func (g *GRPCLoggerV2) Warningf(format string, args ...interface{}) { g.s.Warnf(format, args...) }
+func (g *GRPCLoggerV2) Error(args ...interface{})                   { g.s.Error(args...) }
+func (g *GRPCLoggerV2) Errorln(args ...interface{})                 { g.s.Error(formatArgs(args)) }
+func (g *GRPCLoggerV2) Errorf(format string, args ...interface{})   { g.s.Errorf(format, args...) }
+func (g *GRPCLoggerV2) Fatal(args ...interface{})                   { g.s.Fatal(args...) }
+func (g *GRPCLoggerV2) Fatalln(args ...interface{})                 { g.s.Fatal(formatArgs(args)) }
+func (g *GRPCLoggerV2) Fatalf(format string, args ...interface{})   { g.s.Fatalf(format, args...) }
+
+func (g *GRPCLoggerV2) InfoDepth(depth int, args ...interface{})    { g.depth(depth).Debug(args...) }
+func (g *GRPCLoggerV2) WarningDepth(depth int, args ...interface{}) { g.depth(depth).Warn(args...) }
+func (g *GRPCLoggerV2) ErrorDepth(depth int, args ...interface{})   { g.depth(depth).Error(args...) }
+func (g *GRPCLoggerV2) FatalDepth(depth int, args ...interface{})   { g.depth(depth).Fatal(args...) }
+
+// depth returns a logger that reports the caller depth frames above the
+// caller of the grpclog function that called a Depth method.
+func (g *GRPCLoggerV2) depth(depth int) *zap.SugaredLogger {
+	if depth <= 0 {
+		return g.s
+	}
+	return g.s.Desugar().WithOptions(zap.AddCallerSkip(depth)).Sugar()
+}
+
+// V reports whether verbosity level l is enabled.
+func (g *GRPCLoggerV2) V(l int) bool {
+	return g.enabler.Enabled(grpcVerbosityLevel(l))
+}
+
+// grpcVerbosityLevel returns the flogging level of a gRPC verbosity level.
+func grpcVerbosityLevel(l int) zapcore.Level {
+	if l <= 0 {
+		return zapcore.DebugLevel
+	}
+	return PayloadLevel
+}
@@ -0,0 +1,163 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package flogging_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/redresseur/flogging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/grpclog"
+)
+
+// grpcCaller stands in for the gRPC code that calls a grpclog function. The
+// functions passed to it stand in for the grpclog functions.
+func grpcCaller(l grpclog.LoggerV2, fn func(grpclog.LoggerV2)) { fn(l) }
+
+func TestGRPCLoggerV2Severities(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logging, err := flogging.New(flogging.Config{
+		Format:  "%{module} %{level} %{shortfunc} %{message}",
+		LogSpec: "debug",
+		Writer:  buf,
+	})
+	require.NoError(t, err)
+	gl := logging.GRPCLogger("grpc")
+
+	tests := []struct {
+		log      func(grpclog.LoggerV2)
+		expected string
+	}{
+		{func(l grpclog.LoggerV2) { l.Info("info", 1) }, "grpc DEBUG grpcCaller info1\n"},
+		{func(l grpclog.LoggerV2) { l.Infoln("info", 1) }, "grpc DEBUG grpcCaller info 1\n"},
+		{func(l grpclog.LoggerV2) { l.Infof("info %d", 1) }, "grpc DEBUG grpcCaller info 1\n"},
+		{func(l grpclog.LoggerV2) { l.Warning("warning", 1) }, "grpc WARN grpcCaller warning1\n"},
+		{func(l grpclog.LoggerV2) { l.Warningln("warning", 1) }, "grpc WARN grpcCaller warning 1\n"},
+		{func(l grpclog.LoggerV2) { l.Warningf("warning %d", 1) }, "grpc WARN grpcCaller warning 1\n"},
+		{func(l grpclog.LoggerV2) { l.Error("error", 1) }, "grpc ERROR grpcCaller error1\n"},
+		{func(l grpclog.LoggerV2) { l.Errorln("error", 1) }, "grpc ERROR grpcCaller error 1\n"},
+		{func(l grpclog.LoggerV2) { l.Errorf("error %d", 1) }, "grpc ERROR grpcCaller error 1\n"},
+	}
+	for _, tc := range tests {
+		buf.Reset()
+		grpcCaller(gl, tc.log)
+		assert.Equal(t, tc.expected, buf.String())
+	}
+}
+
+// depthCaller stands in for the gRPC code that calls a grpclog function with
+// a depth.
+func depthCaller(l *flogging.GRPCLoggerV2, depth int, severity string) {
+	logDepth(l, depth, severity)
+}
+
+// logDepth stands in for the grpclog functions that call the Depth methods.
+func logDepth(l *flogging.GRPCLoggerV2, depth int, severity string) {
+	switch severity {
+	case "info":
+		l.InfoDepth(depth, severity)
+	case "warning":
+		l.WarningDepth(depth, severity)
+	case "error":
+		l.ErrorDepth(depth, severity)
+	}
+}
+
+func TestGRPCLoggerV2Depth(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logging, err := flogging.New(flogging.Config{
+		Format:  "%{level} %{shortfunc} %{message}",
+		LogSpec: "debug",
+		Writer:  buf,
+	})
+	require.NoError(t, err)
+	gl := logging.GRPCLogger("grpc")
+
+	tests := []struct {
+		severity string
+		level    string
+	}{
+		{"info", "DEBUG"},
+		{"warning", "WARN"},
+		{"error", "ERROR"},
+	}
+	for _, tc := range tests {
+		buf.Reset()
+		depthCaller(gl, 0, tc.severity)
+		assert.Equal(t, fmt.Sprintf("%s depthCaller %s\n", tc.level, tc.severity), buf.String())
+
+		buf.Reset()
+		depthCaller(gl, 1, tc.severity)
+		assert.Equal(t, fmt.Sprintf("%s TestGRPCLoggerV2Depth %s\n", tc.level, tc.severity), buf.String())
+	}
+}
+
+func TestGRPCLoggerV2Spec(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logging, err := flogging.New(flogging.Config{
+		Format:  "%{level} %{message}",
+		LogSpec: "info",
+		Writer:  buf,
+	})
+	require.NoError(t, err)
+	gl := logging.GRPCLogger("grpc")
+
+	gl.Info("hidden")
+	gl.Warning("shown")
+	assert.Equal(t, "WARN shown\n", buf.String())
+	assert.False(t, gl.V(0))
+
+	err = logging.ActivateSpec("grpc=debug:info")
+	require.NoError(t, err)
+	gl.Info("shown")
+	assert.Equal(t, "WARN shown\nDEBUG shown\n", buf.String())
+	assert.True(t, gl.V(0))
+	assert.False(t, gl.V(1))
+
+	err = logging.ActivateSpec("grpc=payload")
+	require.NoError(t, err)
+	assert.True(t, gl.V(1))
+	assert.True(t, gl.V(2))
+
+	err = logging.ActivateSpec("grpc=error")
+	require.NoError(t, err)
+	gl.Warning("hidden")
+	assert.Equal(t, "WARN shown\nDEBUG shown\n", buf.String())
+}
+
+func TestNewGRPCLoggerV2(t *testing.T) {
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(zapcore.EncoderConfig{}), zapcore.AddSync(&bytes.Buffer{}), zapcore.DebugLevel)
+	gl := flogging.NewGRPCLoggerV2(flogging.NewFabricLogger(zap.New(core)), nil)
+	assert.True(t, gl.V(0))
+	assert.False(t, gl.V(1))
+
+	enabler := zap.LevelEnablerFunc(func(l zapcore.Level) bool { return l >= flogging.PayloadLevel })
+	gl = flogging.NewGRPCLoggerV2(flogging.NewFabricLogger(zap.New(core)), enabler)
+	assert.True(t, gl.V(3))
+}
+
+func TestGlobalGRPCLogger(t *testing.T) {
+	flogging.Reset()
+	defer flogging.Reset()
+
+	buf := &bytes.Buffer{}
+	flogging.Init(flogging.Config{
+		Format:  "%{module} %{level} %{message}",
+		LogSpec: "grpc=debug:info",
+		Writer:  buf,
+	})
+
+	grpclog.Info("connecting")
+	assert.Equal(t, "grpc DEBUG connecting\n", buf.String())
+	assert.True(t, grpclog.V(0))
+	assert.False(t, grpclog.V(1))
+}
@@ -0,0 +1,132 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package grpclogging
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+)
+
+// UnaryClientInterceptor logs unary calls with their method, target,
+// duration, and status code.
+func UnaryClientInterceptor(logger *zap.Logger, opts ...Option) grpc.UnaryClientInterceptor {
+	o := applyOptions(opts...)
+
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		start := time.Now()
+		callLogger := logger.With(clientFields(ctx, method, cc, start)...)
+
+		payloadLevel := o.payloadLeveler.Level(ctx, method)
+		if ce := callLogger.Check(payloadLevel, "sending unary request"); ce != nil {
+			ce.Write(ProtoMessage("grpc.request_content", req))
+		}
+
+		err := invoker(ctx, method, req, reply, cc, callOpts...)
+
+		if err == nil {
+			if ce := callLogger.Check(payloadLevel, "received unary response"); ce != nil {
+				ce.Write(ProtoMessage("grpc.response_content", reply))
+			}
+		}
+		if ce := callLogger.Check(o.leveler.Level(ctx, method), "unary call completed"); ce != nil {
+			ce.Write(completionFields(start, err)...)
+		}
+
+		return err
+	}
+}
+
+// StreamClientInterceptor logs streaming calls with their method, target,
+// duration, and status code. A call is complete when the stream returns an
+// error, including io.EOF, from RecvMsg.
+func StreamClientInterceptor(logger *zap.Logger, opts ...Option) grpc.StreamClientInterceptor {
+	o := applyOptions(opts...)
+
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+		callLogger := logger.With(clientFields(ctx, method, cc, start)...)
+		level := o.leveler.Level(ctx, method)
+
+		stream, err := streamer(ctx, desc, cc, method, callOpts...)
+		if err != nil {
+			if ce := callLogger.Check(level, "streaming call completed"); ce != nil {
+				ce.Write(completionFields(start, err)...)
+			}
+			return nil, err
+		}
+
+		return &clientStream{
+			ClientStream: stream,
+			logger:       callLogger,
+			level:        level,
+			payloadLevel: o.payloadLeveler.Level(ctx, method),
+			start:        start,
+		}, nil
+	}
+}
+
+type clientStream struct {
+	grpc.ClientStream
+	logger       *zap.Logger
+	level        zapcore.Level
+	payloadLevel zapcore.Level
+	start        time.Time
+	once         sync.Once
+}
+
+func (cs *clientStream) SendMsg(msg interface{}) error {
+	if ce := cs.logger.Check(cs.payloadLevel, "sending stream message"); ce != nil {
+		ce.Write(ProtoMessage("grpc.request_content", msg))
+	}
+	return cs.ClientStream.SendMsg(msg)
+}
+
+func (cs *clientStream) RecvMsg(msg interface{}) error {
+	err := cs.ClientStream.RecvMsg(msg)
+	switch err {
+	case nil:
+		if ce := cs.logger.Check(cs.payloadLevel, "received stream message"); ce != nil {
+			ce.Write(ProtoMessage("grpc.response_content", msg))
+		}
+	case io.EOF:
+		cs.complete(nil)
+	default:
+		cs.complete(err)
+	}
+	return err
+}
+
+func (cs *clientStream) complete(err error) {
+	cs.once.Do(func() {
+		if ce := cs.logger.Check(cs.level, "streaming call completed"); ce != nil {
+			ce.Write(completionFields(cs.start, err)...)
+		}
+	})
+}
+
+// clientFields returns the fields that describe an outgoing call.
+func clientFields(ctx context.Context, fullMethod string, cc *grpc.ClientConn, start time.Time) []zapcore.Field {
+	service, method := splitMethod(fullMethod)
+	fields := []zapcore.Field{
+		zap.Time("grpc.start_time", start),
+		zap.String("grpc.service", service),
+		zap.String("grpc.method", method),
+	}
+	if cc != nil {
+		fields = append(fields, zap.String("grpc.target", cc.Target()))
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		fields = append(fields, zap.Time("grpc.request_deadline", deadline))
+	}
+	return fields
+}
@@ -0,0 +1,64 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package grpclogging
+
+import (
+	"context"
+
+	"github.com/redresseur/flogging"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type callContextKey struct{}
+
+type callContext struct {
+	logger *zap.Logger
+	fields []zapcore.Field
+}
+
+// WithFields returns a context whose call logger includes the fields. It is
+// used by the interceptors and may be used by handlers to add request-scoped
+// fields for the code they call.
+func WithFields(ctx context.Context, fields []zapcore.Field) context.Context {
+	cc := callContext{logger: zap.NewNop()}
+	if parent, ok := ctx.Value(callContextKey{}).(callContext); ok {
+		cc = parent
+	}
+	cc.logger = cc.logger.With(fields...)
+	cc.fields = append(cc.fields[:len(cc.fields):len(cc.fields)], fields...)
+	return context.WithValue(ctx, callContextKey{}, cc)
+}
+
+// Fields returns the request-scoped fields of the call.
+func Fields(ctx context.Context) []zapcore.Field {
+	cc, _ := ctx.Value(callContextKey{}).(callContext)
+	return cc.fields
+}
+
+// ZapLogger returns the logger of the call with the request-scoped fields.
+// A no-op logger is returned when the context is not a call context.
+func ZapLogger(ctx context.Context) *zap.Logger {
+	if cc, ok := ctx.Value(callContextKey{}).(callContext); ok {
+		return cc.logger
+	}
+	return zap.NewNop()
+}
+
+// Logger returns the logger of the call as a FabricLogger.
+func Logger(ctx context.Context) *flogging.FabricLogger {
+	return flogging.NewFabricLogger(ZapLogger(ctx))
+}
+
+// withLogger returns a context whose call logger is the logger with the
+// fields.
+func withLogger(ctx context.Context, logger *zap.Logger, fields []zapcore.Field) context.Context {
+	return context.WithValue(ctx, callContextKey{}, callContext{
+		logger: logger.With(fields...),
+		fields: fields,
+	})
+}
@@ -0,0 +1,75 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package grpclogging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// ProtoMessage returns a field that encodes a protobuf message as JSON.
+// Other values are encoded with fmt.
+func ProtoMessage(key string, val interface{}) zapcore.Field {
+	return zap.Any(key, &payload{value: val})
+}
+
+type payload struct {
+	value interface{}
+}
+
+func (p *payload) MarshalJSON() ([]byte, error) {
+	if m, ok := p.value.(proto.Message); ok {
+		marshaler := jsonpb.Marshaler{}
+		s, err := marshaler.MarshalToString(m)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(s), nil
+	}
+	return json.Marshal(fmt.Sprintf("%v", p.value))
+}
+
+// callFields returns the fields that describe a call.
+func callFields(ctx context.Context, fullMethod string, start time.Time) []zapcore.Field {
+	service, method := splitMethod(fullMethod)
+	fields := []zapcore.Field{
+		zap.Time("grpc.start_time", start),
+		zap.String("grpc.service", service),
+		zap.String("grpc.method", method),
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		fields = append(fields, zap.Time("grpc.request_deadline", deadline))
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		if p.Addr != nil {
+			fields = append(fields, zap.String("grpc.peer_address", p.Addr.String()))
+		}
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(tlsInfo.State.PeerCertificates) > 0 {
+			fields = append(fields, zap.Stringer("grpc.peer_subject", tlsInfo.State.PeerCertificates[0].Subject))
+		}
+	}
+	return fields
+}
+
+// splitMethod splits a full method name of the form /service/method.
+func splitMethod(fullMethod string) (string, string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndex(fullMethod, "/"); i >= 0 {
+		return fullMethod[:i], fullMethod[i+1:]
+	}
+	return "unknown", fullMethod
+}
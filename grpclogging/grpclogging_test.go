@@ -0,0 +1,256 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package grpclogging_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/redresseur/flogging"
+	"github.com/redresseur/flogging/grpcadmin"
+	"github.com/redresseur/flogging/grpclogging"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// adminServer is a grpcadmin.AdminServer that logs with the call logger.
+type adminServer struct {
+	callFields []zapcore.Field
+}
+
+func (s *adminServer) GetSpec(ctx context.Context, req *grpcadmin.GetSpecRequest) (*grpcadmin.LogSpec, error) {
+	s.callFields = grpclogging.Fields(ctx)
+	grpclogging.Logger(ctx).Infow("handling", "spec", "info")
+	return &grpcadmin.LogSpec{Spec: "info"}, nil
+}
+
+func (s *adminServer) SetSpec(ctx context.Context, req *grpcadmin.SetSpecRequest) (*grpcadmin.LogSpec, error) {
+	return nil, status.Error(codes.InvalidArgument, "bad spec")
+}
+
+func (s *adminServer) ListLoggers(ctx context.Context, req *grpcadmin.ListLoggersRequest) (*grpcadmin.LoggerList, error) {
+	return &grpcadmin.LoggerList{}, nil
+}
+
+func (s *adminServer) StreamLogs(req *grpcadmin.StreamLogsRequest, stream grpcadmin.Admin_StreamLogsServer) error {
+	grpclogging.ZapLogger(stream.Context()).Info("streaming")
+	for _, message := range []string{"one", "two"} {
+		if err := stream.Send(&grpcadmin.LogEntry{Message: message}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var _ = Describe("Interceptors", func() {
+	var (
+		serverObserved *observer.ObservedLogs
+		clientObserved *observer.ObservedLogs
+		server         *adminServer
+
+		listener   *bufconn.Listener
+		grpcServer *grpc.Server
+		clientConn *grpc.ClientConn
+		client     grpcadmin.AdminClient
+	)
+
+	BeforeEach(func() {
+		var serverCore, clientCore zapcore.Core
+		serverCore, serverObserved = observer.New(flogging.PayloadLevel)
+		clientCore, clientObserved = observer.New(flogging.PayloadLevel)
+		serverLogger := zap.New(serverCore).Named("server")
+		clientLogger := zap.New(clientCore).Named("client")
+
+		server = &adminServer{}
+		listener = bufconn.Listen(1024 * 1024)
+		grpcServer = grpc.NewServer(
+			grpc.UnaryInterceptor(grpclogging.UnaryServerInterceptor(serverLogger)),
+			grpc.StreamInterceptor(grpclogging.StreamServerInterceptor(serverLogger)),
+		)
+		grpcadmin.RegisterAdminServer(grpcServer, server)
+		go grpcServer.Serve(listener)
+
+		var err error
+		clientConn, err = grpc.Dial("bufconn",
+			grpc.WithInsecure(),
+			grpc.WithDialer(func(string, time.Duration) (net.Conn, error) { return listener.Dial() }),
+			grpc.WithUnaryInterceptor(grpclogging.UnaryClientInterceptor(clientLogger)),
+			grpc.WithStreamInterceptor(grpclogging.StreamClientInterceptor(clientLogger)),
+		)
+		Expect(err).NotTo(HaveOccurred())
+		client = grpcadmin.NewAdminClient(clientConn)
+	})
+
+	AfterEach(func() {
+		clientConn.Close()
+		grpcServer.Stop()
+	})
+
+	messages := func(logs *observer.ObservedLogs) []string {
+		var messages []string
+		for _, e := range logs.AllUntimed() {
+			messages = append(messages, e.Message)
+		}
+		return messages
+	}
+
+	Describe("unary calls", func() {
+		It("logs the call and its payloads", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			defer cancel()
+			_, err := client.GetSpec(ctx, &grpcadmin.GetSpecRequest{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(messages(serverObserved)).To(Equal([]string{
+				"received unary request",
+				"handling",
+				"sending unary response",
+				"unary call completed",
+			}))
+			Expect(messages(clientObserved)).To(Equal([]string{
+				"sending unary request",
+				"received unary response",
+				"unary call completed",
+			}))
+
+			entries := serverObserved.AllUntimed()
+			Expect(entries[0].Entry.Level).To(Equal(flogging.PayloadLevel))
+			Expect(entries[0].ContextMap()).To(HaveKeyWithValue("grpc.service", "grpcadmin.Admin"))
+			Expect(entries[0].ContextMap()).To(HaveKeyWithValue("grpc.method", "GetSpec"))
+			Expect(entries[0].ContextMap()).To(HaveKeyWithValue("grpc.peer_address", "bufconn"))
+			Expect(entries[0].ContextMap()).To(HaveKey("grpc.request_deadline"))
+			Expect(entries[0].ContextMap()).To(HaveKey("grpc.request_content"))
+
+			Expect(entries[1].Entry.LoggerName).To(Equal("server"))
+			Expect(entries[1].ContextMap()).To(HaveKeyWithValue("grpc.method", "GetSpec"))
+			Expect(entries[1].ContextMap()).To(HaveKeyWithValue("spec", "info"))
+			Expect(server.callFields).NotTo(BeEmpty())
+
+			completed := entries[3]
+			Expect(completed.Entry.Level).To(Equal(zapcore.InfoLevel))
+			Expect(completed.ContextMap()).To(HaveKeyWithValue("grpc.code", "OK"))
+			Expect(completed.ContextMap()).To(HaveKey("grpc.call_duration"))
+
+			clientCompleted := clientObserved.AllUntimed()[2]
+			Expect(clientCompleted.ContextMap()).To(HaveKeyWithValue("grpc.target", "bufconn"))
+			Expect(clientCompleted.ContextMap()).To(HaveKeyWithValue("grpc.code", "OK"))
+		})
+
+		It("logs the status code of failed calls", func() {
+			_, err := client.SetSpec(context.Background(), &grpcadmin.SetSpecRequest{Spec: "=="})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+
+			Expect(messages(serverObserved)).To(Equal([]string{"received unary request", "unary call completed"}))
+			completed := serverObserved.AllUntimed()[1]
+			Expect(completed.ContextMap()).To(HaveKeyWithValue("grpc.code", "InvalidArgument"))
+			Expect(completed.ContextMap()).To(HaveKeyWithValue("error", "rpc error: code = InvalidArgument desc = bad spec"))
+
+			Expect(messages(clientObserved)).To(Equal([]string{"sending unary request", "unary call completed"}))
+			Expect(clientObserved.AllUntimed()[1].ContextMap()).To(HaveKeyWithValue("grpc.code", "InvalidArgument"))
+		})
+
+		It("encodes protobuf payloads as JSON", func() {
+			_, err := client.SetSpec(context.Background(), &grpcadmin.SetSpecRequest{Spec: "debug"})
+			Expect(err).To(HaveOccurred())
+
+			enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{MessageKey: "msg"})
+			buf, err := enc.EncodeEntry(serverObserved.AllUntimed()[0].Entry, []zapcore.Field{
+				grpclogging.ProtoMessage("grpc.request_content", &grpcadmin.SetSpecRequest{Spec: "debug"}),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(buf.String()).To(MatchJSON(`{"msg": "received unary request", "grpc.request_content": {"spec": "debug"}}`))
+		})
+	})
+
+	Describe("streaming calls", func() {
+		It("logs the call and its messages", func() {
+			stream, err := client.StreamLogs(context.Background(), &grpcadmin.StreamLogsRequest{})
+			Expect(err).NotTo(HaveOccurred())
+			for {
+				_, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			Eventually(func() []string { return messages(serverObserved) }).Should(Equal([]string{
+				"received stream message",
+				"streaming",
+				"sending stream message",
+				"sending stream message",
+				"streaming call completed",
+			}))
+			Expect(messages(clientObserved)).To(Equal([]string{
+				"sending stream message",
+				"received stream message",
+				"received stream message",
+				"streaming call completed",
+			}))
+
+			entries := serverObserved.AllUntimed()
+			Expect(entries[1].ContextMap()).To(HaveKeyWithValue("grpc.method", "StreamLogs"))
+			Expect(entries[4].ContextMap()).To(HaveKeyWithValue("grpc.code", "OK"))
+		})
+	})
+
+	Describe("options", func() {
+		It("uses the levelers", func() {
+			core, observed := observer.New(zapcore.DebugLevel)
+			interceptor := grpclogging.UnaryServerInterceptor(zap.New(core),
+				grpclogging.WithLeveler(grpclogging.LevelerFunc(func(context.Context, string) zapcore.Level { return zapcore.WarnLevel })),
+				grpclogging.WithPayloadLeveler(grpclogging.LevelerFunc(func(context.Context, string) zapcore.Level { return zapcore.DebugLevel })),
+			)
+
+			_, err := interceptor(context.Background(), "request", &grpc.UnaryServerInfo{FullMethod: "/svc/Method"},
+				func(ctx context.Context, req interface{}) (interface{}, error) { return "response", nil },
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			entries := observed.AllUntimed()
+			Expect(entries).To(HaveLen(3))
+			Expect(entries[0].Entry.Level).To(Equal(zapcore.DebugLevel))
+			Expect(entries[2].Entry.Level).To(Equal(zapcore.WarnLevel))
+			Expect(entries[2].ContextMap()).To(HaveKeyWithValue("grpc.service", "svc"))
+		})
+	})
+
+	Describe("call context", func() {
+		It("returns a no-op logger outside of calls", func() {
+			Expect(grpclogging.ZapLogger(context.Background())).NotTo(BeNil())
+			Expect(grpclogging.Fields(context.Background())).To(BeEmpty())
+		})
+
+		It("adds fields to the call logger", func() {
+			core, observed := observer.New(zapcore.InfoLevel)
+			interceptor := grpclogging.UnaryServerInterceptor(zap.New(core))
+			_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/svc/Method"},
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					ctx = grpclogging.WithFields(ctx, []zapcore.Field{zap.String("txid", "tx1")})
+					grpclogging.ZapLogger(ctx).Info("scoped")
+					Expect(grpclogging.Fields(ctx)).To(ContainElement(zap.String("txid", "tx1")))
+					return nil, nil
+				},
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			scoped := observed.FilterMessage("scoped").AllUntimed()
+			Expect(scoped).To(HaveLen(1))
+			Expect(scoped[0].ContextMap()).To(HaveKeyWithValue("txid", "tx1"))
+			Expect(scoped[0].ContextMap()).To(HaveKeyWithValue("grpc.method", "Method"))
+		})
+	})
+})
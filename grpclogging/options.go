@@ -0,0 +1,69 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package grpclogging
+
+import (
+	"context"
+
+	"github.com/redresseur/flogging"
+	"go.uber.org/zap/zapcore"
+)
+
+// A Leveler determines the level of the log entries of a call.
+type Leveler interface {
+	Level(ctx context.Context, fullMethod string) zapcore.Level
+}
+
+// LevelerFunc is an adapter that allows a function to be used as a Leveler.
+type LevelerFunc func(ctx context.Context, fullMethod string) zapcore.Level
+
+// Level calls f(ctx, fullMethod).
+func (f LevelerFunc) Level(ctx context.Context, fullMethod string) zapcore.Level {
+	return f(ctx, fullMethod)
+}
+
+// DefaultLeveler logs completed calls at INFO.
+var DefaultLeveler = LevelerFunc(func(context.Context, string) zapcore.Level {
+	return zapcore.InfoLevel
+})
+
+// DefaultPayloadLeveler logs payloads at PayloadLevel.
+var DefaultPayloadLeveler = LevelerFunc(func(context.Context, string) zapcore.Level {
+	return flogging.PayloadLevel
+})
+
+// An Option configures the interceptors.
+type Option func(o *options)
+
+type options struct {
+	leveler        Leveler
+	payloadLeveler Leveler
+}
+
+// WithLeveler sets the Leveler that determines the level of the entries
+// logged when a call completes.
+func WithLeveler(l Leveler) Option {
+	return func(o *options) { o.leveler = l }
+}
+
+// WithPayloadLeveler sets the Leveler that determines the level of the
+// entries that log request and response payloads. Payloads are only encoded
+// when the level is enabled.
+func WithPayloadLeveler(l Leveler) Option {
+	return func(o *options) { o.payloadLeveler = l }
+}
+
+func applyOptions(opts ...Option) *options {
+	o := &options{
+		leveler:        DefaultLeveler,
+		payloadLeveler: DefaultPayloadLeveler,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
@@ -0,0 +1,116 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package grpclogging
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor logs unary calls with their method, peer, duration,
+// and status code. The handler receives a context with a call logger that
+// can be retrieved with ZapLogger or Logger.
+func UnaryServerInterceptor(logger *zap.Logger, opts ...Option) grpc.UnaryServerInterceptor {
+	o := applyOptions(opts...)
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		ctx = withLogger(ctx, logger, callFields(ctx, info.FullMethod, start))
+		callLogger := ZapLogger(ctx)
+
+		payloadLevel := o.payloadLeveler.Level(ctx, info.FullMethod)
+		if ce := callLogger.Check(payloadLevel, "received unary request"); ce != nil {
+			ce.Write(ProtoMessage("grpc.request_content", req))
+		}
+
+		resp, err := handler(ctx, req)
+
+		if err == nil {
+			if ce := callLogger.Check(payloadLevel, "sending unary response"); ce != nil {
+				ce.Write(ProtoMessage("grpc.response_content", resp))
+			}
+		}
+		if ce := callLogger.Check(o.leveler.Level(ctx, info.FullMethod), "unary call completed"); ce != nil {
+			ce.Write(completionFields(start, err)...)
+		}
+
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor logs streaming calls with their method, peer,
+// duration, and status code. The handler receives a stream whose context has
+// a call logger that can be retrieved with ZapLogger or Logger.
+func StreamServerInterceptor(logger *zap.Logger, opts ...Option) grpc.StreamServerInterceptor {
+	o := applyOptions(opts...)
+
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx := withLogger(stream.Context(), logger, callFields(stream.Context(), info.FullMethod, start))
+		callLogger := ZapLogger(ctx)
+
+		wrapped := &serverStream{
+			ServerStream: stream,
+			ctx:          ctx,
+			logger:       callLogger,
+			payloadLevel: o.payloadLeveler.Level(ctx, info.FullMethod),
+		}
+		err := handler(srv, wrapped)
+
+		if ce := callLogger.Check(o.leveler.Level(ctx, info.FullMethod), "streaming call completed"); ce != nil {
+			ce.Write(completionFields(start, err)...)
+		}
+
+		return err
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx          context.Context
+	logger       *zap.Logger
+	payloadLevel zapcore.Level
+}
+
+func (ss *serverStream) Context() context.Context {
+	return ss.ctx
+}
+
+func (ss *serverStream) SendMsg(msg interface{}) error {
+	if ce := ss.logger.Check(ss.payloadLevel, "sending stream message"); ce != nil {
+		ce.Write(ProtoMessage("grpc.response_content", msg))
+	}
+	return ss.ServerStream.SendMsg(msg)
+}
+
+func (ss *serverStream) RecvMsg(msg interface{}) error {
+	err := ss.ServerStream.RecvMsg(msg)
+	if err != nil {
+		return err
+	}
+	if ce := ss.logger.Check(ss.payloadLevel, "received stream message"); ce != nil {
+		ce.Write(ProtoMessage("grpc.request_content", msg))
+	}
+	return nil
+}
+
+// completionFields returns the fields that describe the outcome of a call.
+func completionFields(start time.Time, err error) []zapcore.Field {
+	fields := []zapcore.Field{
+		zap.Stringer("grpc.code", status.Code(err)),
+		zap.Duration("grpc.call_duration", time.Since(start)),
+	}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	return fields
+}
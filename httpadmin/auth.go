@@ -0,0 +1,290 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpadmin
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/redresseur/flogging"
+)
+
+// A Role determines which requests an authenticated client may make. The
+// zero Role grants no access.
+type Role int
+
+const (
+	// ReadOnly clients may only make GET, HEAD, and OPTIONS requests to
+	// handlers that are not privileged.
+	ReadOnly Role = iota + 1
+	// ReadWrite clients may make any request.
+	ReadWrite
+)
+
+func (r Role) String() string {
+	switch r {
+	case ReadOnly:
+		return "read-only"
+	case ReadWrite:
+		return "read-write"
+	default:
+		return fmt.Sprintf("Role(%d)", int(r))
+	}
+}
+
+// A Principal is an authenticated client of the admin API.
+type Principal struct {
+	Name string
+	Role Role
+}
+
+// An Authenticator identifies the client of a request. It returns false when
+// the request does not carry credentials the Authenticator accepts.
+type Authenticator interface {
+	Authenticate(req *http.Request) (Principal, bool)
+}
+
+// AuthenticatorFunc is an adapter that allows a function to be used as an
+// Authenticator.
+type AuthenticatorFunc func(req *http.Request) (Principal, bool)
+
+// Authenticate calls f(req).
+func (f AuthenticatorFunc) Authenticate(req *http.Request) (Principal, bool) {
+	return f(req)
+}
+
+// TokenAuthenticator authenticates requests that carry a bearer token in the
+// Authorization header.
+type TokenAuthenticator struct {
+	// Tokens maps the accepted bearer tokens to the principal they identify.
+	Tokens map[string]Principal
+}
+
+// Authenticate returns the principal of the bearer token of the request.
+func (a *TokenAuthenticator) Authenticate(req *http.Request) (Principal, bool) {
+	token := bearerToken(req)
+	if token == "" {
+		return Principal{}, false
+	}
+
+	// compare every token to avoid leaking which prefix matched
+	var principal Principal
+	var found bool
+	for t, p := range a.Tokens {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			principal, found = p, true
+		}
+	}
+	return principal, found
+}
+
+// bearerToken returns the token of an Authorization header that uses the
+// Bearer scheme.
+func bearerToken(req *http.Request) string {
+	authorization := req.Header.Get("Authorization")
+	if len(authorization) < len("Bearer ") || !strings.EqualFold(authorization[:len("Bearer ")], "Bearer ") {
+		return ""
+	}
+	return strings.TrimSpace(authorization[len("Bearer "):])
+}
+
+// SubjectAuthenticator authenticates requests from clients that presented a
+// TLS certificate that was verified by the server. The server must be
+// configured to verify client certificates, for example with
+// tls.VerifyClientCertIfGiven.
+type SubjectAuthenticator struct {
+	// Subjects maps certificate subjects to the role of the client. A key
+	// matches either the distinguished name of the subject, such as
+	// "CN=admin,O=Org1", or its common name.
+	Subjects map[string]Role
+}
+
+// Authenticate returns a principal named by the subject of the verified
+// client certificate.
+func (a *SubjectAuthenticator) Authenticate(req *http.Request) (Principal, bool) {
+	if req.TLS == nil || len(req.TLS.VerifiedChains) == 0 || len(req.TLS.VerifiedChains[0]) == 0 {
+		return Principal{}, false
+	}
+
+	subject := req.TLS.VerifiedChains[0][0].Subject
+	name := subject.String()
+	if role, ok := a.Subjects[name]; ok {
+		return Principal{Name: name, Role: role}, true
+	}
+	if role, ok := a.Subjects[subject.CommonName]; ok && subject.CommonName != "" {
+		return Principal{Name: name, Role: role}, true
+	}
+	return Principal{}, false
+}
+
+type principalKey struct{}
+
+// PrincipalFromContext returns the principal that was authenticated by an
+// AuthHandler.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
+
+// NewAuthHandler creates an AuthHandler that requires the clients of handler
+// to be identified by one of the authenticators.
+func NewAuthHandler(handler http.Handler, authenticators ...Authenticator) *AuthHandler {
+	return &AuthHandler{
+		Handler:        handler,
+		Authenticators: authenticators,
+		Logger:         flogging.MustGetLogger("flogging.httpadmin"),
+		AuditLogger:    flogging.MustGetAuditLogger("flogging.httpadmin.audit"),
+	}
+}
+
+// auditBodyLimit is the number of bytes of a request body that are recorded
+// in the audit log.
+const auditBodyLimit = 4096
+
+// AuthHandler authenticates and authorizes requests before passing them to
+// the wrapped handler. The authenticators are consulted in order and the
+// first one that identifies the client determines its role. Requests that
+// change the logging system and requests to privileged handlers are recorded
+// in the audit log with their query and up to 4KiB of their body. Requests to
+// privileged handlers, which may stream entries for as long as the client
+// stays connected, are also recorded before they are served.
+//
+// NewAuthHandler records the audit log with a logger that the logging spec
+// does not apply to.
+type AuthHandler struct {
+	Handler        http.Handler
+	Authenticators []Authenticator
+	// Privileged requires the ReadWrite role for every request, including
+	// reads. It is used for handlers that expose log entries.
+	Privileged  bool
+	Logger      *flogging.FabricLogger
+	AuditLogger *flogging.FabricLogger
+}
+
+func (h *AuthHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	principal, ok := h.authenticate(req)
+	if !ok {
+		h.AuditLogger.Warnw("unauthenticated request rejected", "method", req.Method, "path", req.URL.Path, "remote", req.RemoteAddr)
+		resp.Header().Set("WWW-Authenticate", "Bearer")
+		sendResponse(h.Logger, resp, http.StatusUnauthorized, errors.New("authentication required"))
+		return
+	}
+
+	if !h.authorized(principal, req) {
+		h.AuditLogger.Warnw("unauthorized request rejected",
+			"principal", principal.Name, "role", principal.Role.String(),
+			"method", req.Method, "path", req.URL.Path, "remote", req.RemoteAddr,
+		)
+		err := fmt.Errorf("permission denied: %s is %s", principal.Name, principal.Role)
+		sendResponse(h.Logger, resp, http.StatusForbidden, err)
+		return
+	}
+
+	if isReadRequest(req) && !h.Privileged {
+		h.Handler.ServeHTTP(resp, withPrincipal(req, principal))
+		return
+	}
+
+	kvPairs := []interface{}{
+		"principal", principal.Name, "role", principal.Role.String(),
+		"method", req.Method, "path", req.URL.Path, "remote", req.RemoteAddr,
+	}
+	if req.URL.RawQuery != "" {
+		kvPairs = append(kvPairs, "query", req.URL.RawQuery)
+	}
+	if body := auditBody(req); body != "" {
+		kvPairs = append(kvPairs, "body", body)
+	}
+	if h.Privileged {
+		h.AuditLogger.Infow("admin request started", kvPairs...)
+	}
+
+	recorder := &statusRecorder{ResponseWriter: resp, status: http.StatusOK}
+	h.Handler.ServeHTTP(recorder, withPrincipal(req, principal))
+	h.AuditLogger.Infow("admin request", append(kvPairs, "status", recorder.status)...)
+}
+
+// auditBody returns up to auditBodyLimit bytes of the request body for the
+// audit log. The body remains available to the handler.
+func auditBody(req *http.Request) string {
+	if req.Body == nil || req.Body == http.NoBody {
+		return ""
+	}
+	prefix, _ := ioutil.ReadAll(io.LimitReader(req.Body, auditBodyLimit+1))
+	req.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(prefix), req.Body), req.Body}
+
+	if len(prefix) > auditBodyLimit {
+		return string(prefix[:auditBodyLimit]) + "..."
+	}
+	return string(prefix)
+}
+
+func (h *AuthHandler) authenticate(req *http.Request) (Principal, bool) {
+	for _, a := range h.Authenticators {
+		if principal, ok := a.Authenticate(req); ok {
+			return principal, true
+		}
+	}
+	return Principal{}, false
+}
+
+// authorized returns true when the role of the principal permits the
+// request.
+func (h *AuthHandler) authorized(principal Principal, req *http.Request) bool {
+	switch principal.Role {
+	case ReadWrite:
+		return true
+	case ReadOnly:
+		return !h.Privileged && isReadRequest(req)
+	default:
+		return false
+	}
+}
+
+// isReadRequest returns true for requests that do not change the logging
+// system.
+func isReadRequest(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+func withPrincipal(req *http.Request, principal Principal) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), principalKey{}, principal))
+}
+
+// statusRecorder records the status code written by a handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+// Flush flushes the wrapped ResponseWriter so that streamed responses are
+// delivered while they are audited.
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,307 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpadmin_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/redresseur/flogging"
+	"github.com/redresseur/flogging/httpadmin"
+	"github.com/redresseur/flogging/httpadmin/fakes"
+)
+
+var _ = Describe("AuthHandler", func() {
+	var (
+		auditLog  *gbytes.Buffer
+		principal httpadmin.Principal
+		served    bool
+		handler   *httpadmin.AuthHandler
+	)
+
+	BeforeEach(func() {
+		auditLog = gbytes.NewBuffer()
+		logging, err := flogging.New(flogging.Config{Format: "json", Writer: auditLog})
+		Expect(err).NotTo(HaveOccurred())
+
+		served = false
+		handler = &httpadmin.AuthHandler{
+			Handler: http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+				served = true
+				principal, _ = httpadmin.PrincipalFromContext(req.Context())
+				resp.WriteHeader(http.StatusNoContent)
+			}),
+			Authenticators: []httpadmin.Authenticator{
+				&httpadmin.TokenAuthenticator{
+					Tokens: map[string]httpadmin.Principal{
+						"reader-token": {Name: "reader", Role: httpadmin.ReadOnly},
+						"writer-token": {Name: "writer", Role: httpadmin.ReadWrite},
+					},
+				},
+			},
+			Logger:      logging.Logger("httpadmin"),
+			AuditLogger: logging.Logger("audit"),
+		}
+	})
+
+	It("passes read requests from read-only clients to the handler", func() {
+		req := httptest.NewRequest("GET", "/logspec", nil)
+		req.Header.Set("Authorization", "Bearer reader-token")
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		Expect(served).To(BeTrue())
+		Expect(principal).To(Equal(httpadmin.Principal{Name: "reader", Role: httpadmin.ReadOnly}))
+		Expect(resp.Code).To(Equal(http.StatusNoContent))
+		Expect(auditLog.Contents()).To(BeEmpty())
+	})
+
+	It("passes changes from read-write clients to the handler and audits them", func() {
+		req := httptest.NewRequest("PUT", "/logspec", strings.NewReader(`{"spec": "debug"}`))
+		req.Header.Set("Authorization", "bearer writer-token")
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		Expect(served).To(BeTrue())
+		Expect(principal).To(Equal(httpadmin.Principal{Name: "writer", Role: httpadmin.ReadWrite}))
+		Expect(resp.Code).To(Equal(http.StatusNoContent))
+		Expect(auditLog).To(gbytes.Say(`"msg":"admin request","principal":"writer","role":"read-write","method":"PUT","path":"/logspec","remote":"192.0.2.1:1234","body":"{\\"spec\\": \\"debug\\"}","status":204`))
+	})
+
+	It("passes the audited body to the handler", func() {
+		var body []byte
+		handler.Handler = http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			body, _ = ioutil.ReadAll(req.Body)
+		})
+
+		spec := strings.Repeat("gossip=debug:", 400) + "info"
+		req := httptest.NewRequest("PUT", "/logspec?force=true", strings.NewReader(spec))
+		req.Header.Set("Authorization", "Bearer writer-token")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		Expect(string(body)).To(Equal(spec))
+		Expect(auditLog).To(gbytes.Say(`"query":"force=true","body":"` + spec[:4096] + `\.\.\.","status":200`))
+	})
+
+	It("rejects changes from read-only clients", func() {
+		req := httptest.NewRequest("PUT", "/logspec", strings.NewReader(`{"spec": "debug"}`))
+		req.Header.Set("Authorization", "Bearer reader-token")
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		Expect(served).To(BeFalse())
+		Expect(resp.Code).To(Equal(http.StatusForbidden))
+		Expect(resp.Body).To(MatchJSON(`{"error": "permission denied: reader is read-only"}`))
+		Expect(auditLog).To(gbytes.Say(`"msg":"unauthorized request rejected","principal":"reader","role":"read-only","method":"PUT"`))
+	})
+
+	It("rejects clients without a role", func() {
+		handler.Authenticators = []httpadmin.Authenticator{
+			httpadmin.AuthenticatorFunc(func(req *http.Request) (httpadmin.Principal, bool) {
+				return httpadmin.Principal{Name: "nobody"}, true
+			}),
+		}
+
+		req := httptest.NewRequest("GET", "/logspec", nil)
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		Expect(served).To(BeFalse())
+		Expect(resp.Code).To(Equal(http.StatusForbidden))
+		Expect(resp.Body).To(MatchJSON(`{"error": "permission denied: nobody is Role(0)"}`))
+		Expect(auditLog).To(gbytes.Say(`"msg":"unauthorized request rejected","principal":"nobody","role":"Role\(0\)","method":"GET"`))
+	})
+
+	Context("when the handler is privileged", func() {
+		BeforeEach(func() {
+			handler.Privileged = true
+		})
+
+		It("rejects reads from read-only clients", func() {
+			req := httptest.NewRequest("GET", "/logspec/ring", nil)
+			req.Header.Set("Authorization", "Bearer reader-token")
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(served).To(BeFalse())
+			Expect(resp.Code).To(Equal(http.StatusForbidden))
+			Expect(resp.Body).To(MatchJSON(`{"error": "permission denied: reader is read-only"}`))
+		})
+
+		It("passes reads from read-write clients to the handler and audits them", func() {
+			req := httptest.NewRequest("GET", "/logspec/ring", nil)
+			req.Header.Set("Authorization", "Bearer writer-token")
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(served).To(BeTrue())
+			Expect(resp.Code).To(Equal(http.StatusNoContent))
+			Expect(auditLog).To(gbytes.Say(`"msg":"admin request","principal":"writer","role":"read-write","method":"GET","path":"/logspec/ring"`))
+		})
+
+		It("audits requests before they are served", func() {
+			handler.Handler = http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+				Expect(auditLog).To(gbytes.Say(`"msg":"admin request started","principal":"writer","role":"read-write","method":"GET","path":"/logspec/stream","remote":"192.0.2.1:1234","query":"level=debug"}`))
+			})
+
+			req := httptest.NewRequest("GET", "/logspec/stream?level=debug", nil)
+			req.Header.Set("Authorization", "Bearer writer-token")
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			Expect(auditLog).To(gbytes.Say(`"msg":"admin request",.*"query":"level=debug","status":200`))
+		})
+
+		It("lets the handler flush streamed responses", func() {
+			var flushed bool
+			handler.Handler = http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+				flusher, ok := resp.(http.Flusher)
+				Expect(ok).To(BeTrue())
+				flusher.Flush()
+				flushed = true
+			})
+
+			req := httptest.NewRequest("GET", "/logspec/stream", nil)
+			req.Header.Set("Authorization", "Bearer writer-token")
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(flushed).To(BeTrue())
+			Expect(resp.Flushed).To(BeTrue())
+		})
+	})
+
+	It("rejects unauthenticated clients", func() {
+		for _, authorization := range []string{"", "Bearer writer", "Basic d3JpdGVyLXRva2Vu", "Bearer "} {
+			req := httptest.NewRequest("GET", "/logspec", nil)
+			req.Header.Set("Authorization", authorization)
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(served).To(BeFalse(), authorization)
+			Expect(resp.Code).To(Equal(http.StatusUnauthorized), authorization)
+			Expect(resp.Header().Get("WWW-Authenticate")).To(Equal("Bearer"))
+			Expect(resp.Body).To(MatchJSON(`{"error": "authentication required"}`))
+			Expect(auditLog).To(gbytes.Say(`"msg":"unauthenticated request rejected","method":"GET","path":"/logspec"`))
+		}
+	})
+
+	Context("when the authenticators are consulted in order", func() {
+		BeforeEach(func() {
+			handler.Authenticators = append([]httpadmin.Authenticator{
+				httpadmin.AuthenticatorFunc(func(req *http.Request) (httpadmin.Principal, bool) {
+					return httpadmin.Principal{Name: "first", Role: httpadmin.ReadOnly}, req.Header.Get("X-First") != ""
+				}),
+			}, handler.Authenticators...)
+		})
+
+		It("uses the first principal", func() {
+			req := httptest.NewRequest("GET", "/logspec", nil)
+			req.Header.Set("X-First", "true")
+			req.Header.Set("Authorization", "Bearer writer-token")
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+			Expect(principal.Name).To(Equal("first"))
+
+			req = httptest.NewRequest("GET", "/logspec", nil)
+			req.Header.Set("Authorization", "Bearer writer-token")
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+			Expect(principal.Name).To(Equal("writer"))
+		})
+	})
+
+	It("identifies the principal as the caller of spec changes", func() {
+		fakeLogging := &fakes.VersionedLogging{}
+		handler.Handler = &httpadmin.SpecHandler{Logging: fakeLogging}
+
+		req := httptest.NewRequest("PUT", "/logspec", strings.NewReader(`{"spec": "debug"}`))
+		req.Header.Set("Authorization", "Bearer writer-token")
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		Expect(resp.Code).To(Equal(http.StatusNoContent))
+		Expect(fakeLogging.ReplaceSpecFromCallCount()).To(Equal(1))
+		spec, _, source, caller := fakeLogging.ReplaceSpecFromArgsForCall(0)
+		Expect(spec).To(Equal("debug"))
+		Expect(source).To(Equal(flogging.SpecSourceHTTPAdmin))
+		Expect(caller).To(Equal("writer"))
+	})
+
+	Describe("NewAuthHandler", func() {
+		It("constructs a handler with loggers", func() {
+			authenticator := &httpadmin.TokenAuthenticator{}
+			authHandler := httpadmin.NewAuthHandler(http.NotFoundHandler(), authenticator)
+			Expect(authHandler.Handler).NotTo(BeNil())
+			Expect(authHandler.Authenticators).To(ConsistOf(authenticator))
+			Expect(authHandler.Logger).NotTo(BeNil())
+			Expect(authHandler.AuditLogger).NotTo(BeNil())
+		})
+	})
+})
+
+var _ = Describe("SubjectAuthenticator", func() {
+	var (
+		authenticator *httpadmin.SubjectAuthenticator
+		req           *http.Request
+	)
+
+	BeforeEach(func() {
+		authenticator = &httpadmin.SubjectAuthenticator{
+			Subjects: map[string]httpadmin.Role{
+				"CN=operator,O=Org1": httpadmin.ReadWrite,
+				"auditor":            httpadmin.ReadOnly,
+			},
+		}
+		req = httptest.NewRequest("GET", "/logspec", nil)
+	})
+
+	verified := func(subject pkix.Name) *tls.ConnectionState {
+		cert := &x509.Certificate{Subject: subject}
+		return &tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{cert},
+			VerifiedChains:   [][]*x509.Certificate{{cert}},
+		}
+	}
+
+	It("matches the distinguished name of the subject", func() {
+		req.TLS = verified(pkix.Name{CommonName: "operator", Organization: []string{"Org1"}})
+		principal, ok := authenticator.Authenticate(req)
+		Expect(ok).To(BeTrue())
+		Expect(principal).To(Equal(httpadmin.Principal{Name: "CN=operator,O=Org1", Role: httpadmin.ReadWrite}))
+	})
+
+	It("matches the common name of the subject", func() {
+		req.TLS = verified(pkix.Name{CommonName: "auditor", Organization: []string{"Org2"}})
+		principal, ok := authenticator.Authenticate(req)
+		Expect(ok).To(BeTrue())
+		Expect(principal).To(Equal(httpadmin.Principal{Name: "CN=auditor,O=Org2", Role: httpadmin.ReadOnly}))
+	})
+
+	It("rejects unknown subjects", func() {
+		req.TLS = verified(pkix.Name{CommonName: "operator", Organization: []string{"Org2"}})
+		_, ok := authenticator.Authenticate(req)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("rejects certificates that were not verified", func() {
+		req.TLS = verified(pkix.Name{CommonName: "auditor"})
+		req.TLS.VerifiedChains = nil
+		_, ok := authenticator.Authenticate(req)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("rejects requests without TLS", func() {
+		_, ok := authenticator.Authenticate(req)
+		Expect(ok).To(BeFalse())
+	})
+})
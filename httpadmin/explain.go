@@ -0,0 +1,53 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpadmin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/redresseur/flogging"
+)
+
+//go:generate counterfeiter -o fakes/explainer.go -fake-name Explainer . Explainer
+
+type Explainer interface {
+	Explain(loggerName string) flogging.LevelExplanation
+}
+
+// NewExplainHandler creates an ExplainHandler for the global logging system.
+// It is intended to be served at /logspec/explain.
+func NewExplainHandler() *ExplainHandler {
+	return &ExplainHandler{
+		Explainer: flogging.Global,
+		Logger:    flogging.MustGetLogger("flogging.httpadmin"),
+	}
+}
+
+// ExplainHandler reports which segment of the active logging spec determines
+// the level of the logger named by the logger query parameter.
+type ExplainHandler struct {
+	Explainer Explainer
+	Logger    *flogging.FabricLogger
+}
+
+func (h *ExplainHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		err := fmt.Errorf("invalid request method: %s", req.Method)
+		sendResponse(h.Logger, resp, http.StatusBadRequest, err)
+		return
+	}
+
+	loggerName := req.URL.Query().Get("logger")
+	if loggerName == "" {
+		sendResponse(h.Logger, resp, http.StatusBadRequest, errors.New("missing logger parameter"))
+		return
+	}
+
+	sendResponse(h.Logger, resp, http.StatusOK, h.Explainer.Explain(loggerName))
+}
@@ -0,0 +1,121 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpadmin_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/redresseur/flogging"
+	"github.com/redresseur/flogging/httpadmin"
+	"github.com/redresseur/flogging/httpadmin/fakes"
+	"go.uber.org/zap/zapcore"
+)
+
+var _ = Describe("ExplainHandler", func() {
+	var (
+		fakeExplainer *fakes.Explainer
+		handler       *httpadmin.ExplainHandler
+	)
+
+	BeforeEach(func() {
+		debug := zapcore.DebugLevel
+		fakeExplainer = &fakes.Explainer{}
+		fakeExplainer.ExplainReturns(flogging.LevelExplanation{
+			Logger:  "a.b",
+			Level:   zapcore.DebugLevel,
+			Segment: "a=debug",
+			Candidates: []flogging.LevelCandidate{
+				{Name: "a.b.", Exact: true},
+				{Name: "a.b"},
+				{Name: "a", Level: &debug, Matched: true},
+			},
+		})
+		handler = &httpadmin.ExplainHandler{
+			Explainer: fakeExplainer,
+		}
+	})
+
+	It("responds with the explanation for the logger", func() {
+		req := httptest.NewRequest("GET", "/logspec/explain?logger=a.b", nil)
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		Expect(fakeExplainer.ExplainCallCount()).To(Equal(1))
+		Expect(fakeExplainer.ExplainArgsForCall(0)).To(Equal("a.b"))
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Header().Get("Content-Type")).To(Equal("application/json"))
+		Expect(resp.Body).To(MatchJSON(`{
+			"logger": "a.b",
+			"level": "debug",
+			"segment": "a=debug",
+			"default": false,
+			"candidates": [
+				{"name": "a.b.", "exact": true, "matched": false},
+				{"name": "a.b", "exact": false, "matched": false},
+				{"name": "a", "exact": false, "level": "debug", "matched": true}
+			]
+		}`))
+	})
+
+	It("names the payload level", func() {
+		payload := flogging.PayloadLevel
+		fakeExplainer.ExplainReturns(flogging.LevelExplanation{
+			Logger:     "a.b",
+			Level:      flogging.PayloadLevel,
+			Segment:    "a=payload",
+			Candidates: []flogging.LevelCandidate{{Name: "a", Level: &payload, Matched: true}},
+		})
+
+		req := httptest.NewRequest("GET", "/logspec/explain?logger=a.b", nil)
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body).To(MatchJSON(`{
+			"logger": "a.b",
+			"level": "payload",
+			"segment": "a=payload",
+			"default": false,
+			"candidates": [{"name": "a", "exact": false, "level": "payload", "matched": true}]
+		}`))
+	})
+
+	Context("when the logger parameter is missing", func() {
+		It("responds with an error", func() {
+			req := httptest.NewRequest("GET", "/logspec/explain", nil)
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(fakeExplainer.ExplainCallCount()).To(Equal(0))
+			Expect(resp.Code).To(Equal(http.StatusBadRequest))
+			Expect(resp.Body).To(MatchJSON(`{"error": "missing logger parameter"}`))
+		})
+	})
+
+	Context("when an unsupported method is used", func() {
+		It("responds with an error", func() {
+			req := httptest.NewRequest("POST", "/logspec/explain?logger=a", nil)
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(fakeExplainer.ExplainCallCount()).To(Equal(0))
+			Expect(resp.Code).To(Equal(http.StatusBadRequest))
+			Expect(resp.Body).To(MatchJSON(`{"error": "invalid request method: POST"}`))
+		})
+	})
+
+	Describe("NewExplainHandler", func() {
+		It("constructs a handler that explains the global spec", func() {
+			explainHandler := httpadmin.NewExplainHandler()
+			Expect(explainHandler.Explainer).To(Equal(flogging.Global))
+			Expect(explainHandler.Logger).NotTo(BeNil())
+		})
+	})
+})
@@ -0,0 +1,112 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"github.com/redresseur/flogging/httpadmin"
+	"go.uber.org/zap/zapcore"
+)
+
+type EntryStreamer struct {
+	SubscribeEntriesStub        func(func(zapcore.Entry, []zapcore.Field)) func()
+	subscribeEntriesMutex       sync.RWMutex
+	subscribeEntriesArgsForCall []struct {
+		arg1 func(zapcore.Entry, []zapcore.Field)
+	}
+	subscribeEntriesReturns struct {
+		result1 func()
+	}
+	subscribeEntriesReturnsOnCall map[int]struct {
+		result1 func()
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *EntryStreamer) SubscribeEntries(arg1 func(zapcore.Entry, []zapcore.Field)) func() {
+	fake.subscribeEntriesMutex.Lock()
+	ret, specificReturn := fake.subscribeEntriesReturnsOnCall[len(fake.subscribeEntriesArgsForCall)]
+	fake.subscribeEntriesArgsForCall = append(fake.subscribeEntriesArgsForCall, struct {
+		arg1 func(zapcore.Entry, []zapcore.Field)
+	}{arg1})
+	stub := fake.SubscribeEntriesStub
+	fakeReturns := fake.subscribeEntriesReturns
+	fake.recordInvocation("SubscribeEntries", []interface{}{arg1})
+	fake.subscribeEntriesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *EntryStreamer) SubscribeEntriesCallCount() int {
+	fake.subscribeEntriesMutex.RLock()
+	defer fake.subscribeEntriesMutex.RUnlock()
+	return len(fake.subscribeEntriesArgsForCall)
+}
+
+func (fake *EntryStreamer) SubscribeEntriesCalls(stub func(func(zapcore.Entry, []zapcore.Field)) func()) {
+	fake.subscribeEntriesMutex.Lock()
+	defer fake.subscribeEntriesMutex.Unlock()
+	fake.SubscribeEntriesStub = stub
+}
+
+func (fake *EntryStreamer) SubscribeEntriesArgsForCall(i int) func(zapcore.Entry, []zapcore.Field) {
+	fake.subscribeEntriesMutex.RLock()
+	defer fake.subscribeEntriesMutex.RUnlock()
+	argsForCall := fake.subscribeEntriesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *EntryStreamer) SubscribeEntriesReturns(result1 func()) {
+	fake.subscribeEntriesMutex.Lock()
+	defer fake.subscribeEntriesMutex.Unlock()
+	fake.SubscribeEntriesStub = nil
+	fake.subscribeEntriesReturns = struct {
+		result1 func()
+	}{result1}
+}
+
+func (fake *EntryStreamer) SubscribeEntriesReturnsOnCall(i int, result1 func()) {
+	fake.subscribeEntriesMutex.Lock()
+	defer fake.subscribeEntriesMutex.Unlock()
+	fake.SubscribeEntriesStub = nil
+	if fake.subscribeEntriesReturnsOnCall == nil {
+		fake.subscribeEntriesReturnsOnCall = make(map[int]struct {
+			result1 func()
+		})
+	}
+	fake.subscribeEntriesReturnsOnCall[i] = struct {
+		result1 func()
+	}{result1}
+}
+
+func (fake *EntryStreamer) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.subscribeEntriesMutex.RLock()
+	defer fake.subscribeEntriesMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *EntryStreamer) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ httpadmin.EntryStreamer = new(EntryStreamer)
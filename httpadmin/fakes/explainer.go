@@ -0,0 +1,112 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"github.com/redresseur/flogging"
+	"github.com/redresseur/flogging/httpadmin"
+)
+
+type Explainer struct {
+	ExplainStub        func(string) flogging.LevelExplanation
+	explainMutex       sync.RWMutex
+	explainArgsForCall []struct {
+		arg1 string
+	}
+	explainReturns struct {
+		result1 flogging.LevelExplanation
+	}
+	explainReturnsOnCall map[int]struct {
+		result1 flogging.LevelExplanation
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *Explainer) Explain(arg1 string) flogging.LevelExplanation {
+	fake.explainMutex.Lock()
+	ret, specificReturn := fake.explainReturnsOnCall[len(fake.explainArgsForCall)]
+	fake.explainArgsForCall = append(fake.explainArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.ExplainStub
+	fakeReturns := fake.explainReturns
+	fake.recordInvocation("Explain", []interface{}{arg1})
+	fake.explainMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Explainer) ExplainCallCount() int {
+	fake.explainMutex.RLock()
+	defer fake.explainMutex.RUnlock()
+	return len(fake.explainArgsForCall)
+}
+
+func (fake *Explainer) ExplainCalls(stub func(string) flogging.LevelExplanation) {
+	fake.explainMutex.Lock()
+	defer fake.explainMutex.Unlock()
+	fake.ExplainStub = stub
+}
+
+func (fake *Explainer) ExplainArgsForCall(i int) string {
+	fake.explainMutex.RLock()
+	defer fake.explainMutex.RUnlock()
+	argsForCall := fake.explainArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Explainer) ExplainReturns(result1 flogging.LevelExplanation) {
+	fake.explainMutex.Lock()
+	defer fake.explainMutex.Unlock()
+	fake.ExplainStub = nil
+	fake.explainReturns = struct {
+		result1 flogging.LevelExplanation
+	}{result1}
+}
+
+func (fake *Explainer) ExplainReturnsOnCall(i int, result1 flogging.LevelExplanation) {
+	fake.explainMutex.Lock()
+	defer fake.explainMutex.Unlock()
+	fake.ExplainStub = nil
+	if fake.explainReturnsOnCall == nil {
+		fake.explainReturnsOnCall = make(map[int]struct {
+			result1 flogging.LevelExplanation
+		})
+	}
+	fake.explainReturnsOnCall[i] = struct {
+		result1 flogging.LevelExplanation
+	}{result1}
+}
+
+func (fake *Explainer) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.explainMutex.RLock()
+	defer fake.explainMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *Explainer) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ httpadmin.Explainer = new(Explainer)
@@ -0,0 +1,176 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"github.com/redresseur/flogging/httpadmin"
+)
+
+type Formatter struct {
+	FormatStub        func() string
+	formatMutex       sync.RWMutex
+	formatArgsForCall []struct {
+	}
+	formatReturns struct {
+		result1 string
+	}
+	formatReturnsOnCall map[int]struct {
+		result1 string
+	}
+	SetFormatStub        func(string) error
+	setFormatMutex       sync.RWMutex
+	setFormatArgsForCall []struct {
+		arg1 string
+	}
+	setFormatReturns struct {
+		result1 error
+	}
+	setFormatReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *Formatter) Format() string {
+	fake.formatMutex.Lock()
+	ret, specificReturn := fake.formatReturnsOnCall[len(fake.formatArgsForCall)]
+	fake.formatArgsForCall = append(fake.formatArgsForCall, struct {
+	}{})
+	stub := fake.FormatStub
+	fakeReturns := fake.formatReturns
+	fake.recordInvocation("Format", []interface{}{})
+	fake.formatMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Formatter) FormatCallCount() int {
+	fake.formatMutex.RLock()
+	defer fake.formatMutex.RUnlock()
+	return len(fake.formatArgsForCall)
+}
+
+func (fake *Formatter) FormatCalls(stub func() string) {
+	fake.formatMutex.Lock()
+	defer fake.formatMutex.Unlock()
+	fake.FormatStub = stub
+}
+
+func (fake *Formatter) FormatReturns(result1 string) {
+	fake.formatMutex.Lock()
+	defer fake.formatMutex.Unlock()
+	fake.FormatStub = nil
+	fake.formatReturns = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *Formatter) FormatReturnsOnCall(i int, result1 string) {
+	fake.formatMutex.Lock()
+	defer fake.formatMutex.Unlock()
+	fake.FormatStub = nil
+	if fake.formatReturnsOnCall == nil {
+		fake.formatReturnsOnCall = make(map[int]struct {
+			result1 string
+		})
+	}
+	fake.formatReturnsOnCall[i] = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *Formatter) SetFormat(arg1 string) error {
+	fake.setFormatMutex.Lock()
+	ret, specificReturn := fake.setFormatReturnsOnCall[len(fake.setFormatArgsForCall)]
+	fake.setFormatArgsForCall = append(fake.setFormatArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.SetFormatStub
+	fakeReturns := fake.setFormatReturns
+	fake.recordInvocation("SetFormat", []interface{}{arg1})
+	fake.setFormatMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Formatter) SetFormatCallCount() int {
+	fake.setFormatMutex.RLock()
+	defer fake.setFormatMutex.RUnlock()
+	return len(fake.setFormatArgsForCall)
+}
+
+func (fake *Formatter) SetFormatCalls(stub func(string) error) {
+	fake.setFormatMutex.Lock()
+	defer fake.setFormatMutex.Unlock()
+	fake.SetFormatStub = stub
+}
+
+func (fake *Formatter) SetFormatArgsForCall(i int) string {
+	fake.setFormatMutex.RLock()
+	defer fake.setFormatMutex.RUnlock()
+	argsForCall := fake.setFormatArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Formatter) SetFormatReturns(result1 error) {
+	fake.setFormatMutex.Lock()
+	defer fake.setFormatMutex.Unlock()
+	fake.SetFormatStub = nil
+	fake.setFormatReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Formatter) SetFormatReturnsOnCall(i int, result1 error) {
+	fake.setFormatMutex.Lock()
+	defer fake.setFormatMutex.Unlock()
+	fake.SetFormatStub = nil
+	if fake.setFormatReturnsOnCall == nil {
+		fake.setFormatReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setFormatReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Formatter) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.formatMutex.RLock()
+	defer fake.formatMutex.RUnlock()
+	fake.setFormatMutex.RLock()
+	defer fake.setFormatMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *Formatter) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ httpadmin.Formatter = new(Formatter)
@@ -0,0 +1,177 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"github.com/redresseur/flogging"
+	"github.com/redresseur/flogging/httpadmin"
+)
+
+type LoggerRegistry struct {
+	LoggerInfoStub        func(string) flogging.LoggerInfo
+	loggerInfoMutex       sync.RWMutex
+	loggerInfoArgsForCall []struct {
+		arg1 string
+	}
+	loggerInfoReturns struct {
+		result1 flogging.LoggerInfo
+	}
+	loggerInfoReturnsOnCall map[int]struct {
+		result1 flogging.LoggerInfo
+	}
+	LoggersStub        func() []flogging.LoggerInfo
+	loggersMutex       sync.RWMutex
+	loggersArgsForCall []struct {
+	}
+	loggersReturns struct {
+		result1 []flogging.LoggerInfo
+	}
+	loggersReturnsOnCall map[int]struct {
+		result1 []flogging.LoggerInfo
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *LoggerRegistry) LoggerInfo(arg1 string) flogging.LoggerInfo {
+	fake.loggerInfoMutex.Lock()
+	ret, specificReturn := fake.loggerInfoReturnsOnCall[len(fake.loggerInfoArgsForCall)]
+	fake.loggerInfoArgsForCall = append(fake.loggerInfoArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.LoggerInfoStub
+	fakeReturns := fake.loggerInfoReturns
+	fake.recordInvocation("LoggerInfo", []interface{}{arg1})
+	fake.loggerInfoMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *LoggerRegistry) LoggerInfoCallCount() int {
+	fake.loggerInfoMutex.RLock()
+	defer fake.loggerInfoMutex.RUnlock()
+	return len(fake.loggerInfoArgsForCall)
+}
+
+func (fake *LoggerRegistry) LoggerInfoCalls(stub func(string) flogging.LoggerInfo) {
+	fake.loggerInfoMutex.Lock()
+	defer fake.loggerInfoMutex.Unlock()
+	fake.LoggerInfoStub = stub
+}
+
+func (fake *LoggerRegistry) LoggerInfoArgsForCall(i int) string {
+	fake.loggerInfoMutex.RLock()
+	defer fake.loggerInfoMutex.RUnlock()
+	argsForCall := fake.loggerInfoArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *LoggerRegistry) LoggerInfoReturns(result1 flogging.LoggerInfo) {
+	fake.loggerInfoMutex.Lock()
+	defer fake.loggerInfoMutex.Unlock()
+	fake.LoggerInfoStub = nil
+	fake.loggerInfoReturns = struct {
+		result1 flogging.LoggerInfo
+	}{result1}
+}
+
+func (fake *LoggerRegistry) LoggerInfoReturnsOnCall(i int, result1 flogging.LoggerInfo) {
+	fake.loggerInfoMutex.Lock()
+	defer fake.loggerInfoMutex.Unlock()
+	fake.LoggerInfoStub = nil
+	if fake.loggerInfoReturnsOnCall == nil {
+		fake.loggerInfoReturnsOnCall = make(map[int]struct {
+			result1 flogging.LoggerInfo
+		})
+	}
+	fake.loggerInfoReturnsOnCall[i] = struct {
+		result1 flogging.LoggerInfo
+	}{result1}
+}
+
+func (fake *LoggerRegistry) Loggers() []flogging.LoggerInfo {
+	fake.loggersMutex.Lock()
+	ret, specificReturn := fake.loggersReturnsOnCall[len(fake.loggersArgsForCall)]
+	fake.loggersArgsForCall = append(fake.loggersArgsForCall, struct {
+	}{})
+	stub := fake.LoggersStub
+	fakeReturns := fake.loggersReturns
+	fake.recordInvocation("Loggers", []interface{}{})
+	fake.loggersMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *LoggerRegistry) LoggersCallCount() int {
+	fake.loggersMutex.RLock()
+	defer fake.loggersMutex.RUnlock()
+	return len(fake.loggersArgsForCall)
+}
+
+func (fake *LoggerRegistry) LoggersCalls(stub func() []flogging.LoggerInfo) {
+	fake.loggersMutex.Lock()
+	defer fake.loggersMutex.Unlock()
+	fake.LoggersStub = stub
+}
+
+func (fake *LoggerRegistry) LoggersReturns(result1 []flogging.LoggerInfo) {
+	fake.loggersMutex.Lock()
+	defer fake.loggersMutex.Unlock()
+	fake.LoggersStub = nil
+	fake.loggersReturns = struct {
+		result1 []flogging.LoggerInfo
+	}{result1}
+}
+
+func (fake *LoggerRegistry) LoggersReturnsOnCall(i int, result1 []flogging.LoggerInfo) {
+	fake.loggersMutex.Lock()
+	defer fake.loggersMutex.Unlock()
+	fake.LoggersStub = nil
+	if fake.loggersReturnsOnCall == nil {
+		fake.loggersReturnsOnCall = make(map[int]struct {
+			result1 []flogging.LoggerInfo
+		})
+	}
+	fake.loggersReturnsOnCall[i] = struct {
+		result1 []flogging.LoggerInfo
+	}{result1}
+}
+
+func (fake *LoggerRegistry) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.loggerInfoMutex.RLock()
+	defer fake.loggerInfoMutex.RUnlock()
+	fake.loggersMutex.RLock()
+	defer fake.loggersMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *LoggerRegistry) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ httpadmin.LoggerRegistry = new(LoggerRegistry)
@@ -0,0 +1,103 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"github.com/redresseur/flogging"
+	"github.com/redresseur/flogging/httpadmin"
+)
+
+type ObserverStats struct {
+	ObserverStatsStub        func() flogging.ObserverStats
+	observerStatsMutex       sync.RWMutex
+	observerStatsArgsForCall []struct {
+	}
+	observerStatsReturns struct {
+		result1 flogging.ObserverStats
+	}
+	observerStatsReturnsOnCall map[int]struct {
+		result1 flogging.ObserverStats
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *ObserverStats) ObserverStats() flogging.ObserverStats {
+	fake.observerStatsMutex.Lock()
+	ret, specificReturn := fake.observerStatsReturnsOnCall[len(fake.observerStatsArgsForCall)]
+	fake.observerStatsArgsForCall = append(fake.observerStatsArgsForCall, struct {
+	}{})
+	stub := fake.ObserverStatsStub
+	fakeReturns := fake.observerStatsReturns
+	fake.recordInvocation("ObserverStats", []interface{}{})
+	fake.observerStatsMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *ObserverStats) ObserverStatsCallCount() int {
+	fake.observerStatsMutex.RLock()
+	defer fake.observerStatsMutex.RUnlock()
+	return len(fake.observerStatsArgsForCall)
+}
+
+func (fake *ObserverStats) ObserverStatsCalls(stub func() flogging.ObserverStats) {
+	fake.observerStatsMutex.Lock()
+	defer fake.observerStatsMutex.Unlock()
+	fake.ObserverStatsStub = stub
+}
+
+func (fake *ObserverStats) ObserverStatsReturns(result1 flogging.ObserverStats) {
+	fake.observerStatsMutex.Lock()
+	defer fake.observerStatsMutex.Unlock()
+	fake.ObserverStatsStub = nil
+	fake.observerStatsReturns = struct {
+		result1 flogging.ObserverStats
+	}{result1}
+}
+
+func (fake *ObserverStats) ObserverStatsReturnsOnCall(i int, result1 flogging.ObserverStats) {
+	fake.observerStatsMutex.Lock()
+	defer fake.observerStatsMutex.Unlock()
+	fake.ObserverStatsStub = nil
+	if fake.observerStatsReturnsOnCall == nil {
+		fake.observerStatsReturnsOnCall = make(map[int]struct {
+			result1 flogging.ObserverStats
+		})
+	}
+	fake.observerStatsReturnsOnCall[i] = struct {
+		result1 flogging.ObserverStats
+	}{result1}
+}
+
+func (fake *ObserverStats) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.observerStatsMutex.RLock()
+	defer fake.observerStatsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *ObserverStats) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ httpadmin.ObserverStats = new(ObserverStats)
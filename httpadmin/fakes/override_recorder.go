@@ -0,0 +1,331 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+	"time"
+
+	"github.com/redresseur/flogging"
+	"github.com/redresseur/flogging/httpadmin"
+)
+
+type OverrideRecorder struct {
+	ClearOverridesStub        func()
+	clearOverridesMutex       sync.RWMutex
+	clearOverridesArgsForCall []struct {
+	}
+	ClearOverridesFromStub        func(string, string)
+	clearOverridesFromMutex       sync.RWMutex
+	clearOverridesFromArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	OverrideSpecStub        func(string, time.Duration) error
+	overrideSpecMutex       sync.RWMutex
+	overrideSpecArgsForCall []struct {
+		arg1 string
+		arg2 time.Duration
+	}
+	overrideSpecReturns struct {
+		result1 error
+	}
+	overrideSpecReturnsOnCall map[int]struct {
+		result1 error
+	}
+	OverrideSpecFromStub        func(string, time.Duration, string, string) error
+	overrideSpecFromMutex       sync.RWMutex
+	overrideSpecFromArgsForCall []struct {
+		arg1 string
+		arg2 time.Duration
+		arg3 string
+		arg4 string
+	}
+	overrideSpecFromReturns struct {
+		result1 error
+	}
+	overrideSpecFromReturnsOnCall map[int]struct {
+		result1 error
+	}
+	OverridesStub        func() []flogging.LevelOverride
+	overridesMutex       sync.RWMutex
+	overridesArgsForCall []struct {
+	}
+	overridesReturns struct {
+		result1 []flogging.LevelOverride
+	}
+	overridesReturnsOnCall map[int]struct {
+		result1 []flogging.LevelOverride
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *OverrideRecorder) ClearOverrides() {
+	fake.clearOverridesMutex.Lock()
+	fake.clearOverridesArgsForCall = append(fake.clearOverridesArgsForCall, struct {
+	}{})
+	stub := fake.ClearOverridesStub
+	fake.recordInvocation("ClearOverrides", []interface{}{})
+	fake.clearOverridesMutex.Unlock()
+	if stub != nil {
+		fake.ClearOverridesStub()
+	}
+}
+
+func (fake *OverrideRecorder) ClearOverridesCallCount() int {
+	fake.clearOverridesMutex.RLock()
+	defer fake.clearOverridesMutex.RUnlock()
+	return len(fake.clearOverridesArgsForCall)
+}
+
+func (fake *OverrideRecorder) ClearOverridesCalls(stub func()) {
+	fake.clearOverridesMutex.Lock()
+	defer fake.clearOverridesMutex.Unlock()
+	fake.ClearOverridesStub = stub
+}
+
+func (fake *OverrideRecorder) ClearOverridesFrom(arg1 string, arg2 string) {
+	fake.clearOverridesFromMutex.Lock()
+	fake.clearOverridesFromArgsForCall = append(fake.clearOverridesFromArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.ClearOverridesFromStub
+	fake.recordInvocation("ClearOverridesFrom", []interface{}{arg1, arg2})
+	fake.clearOverridesFromMutex.Unlock()
+	if stub != nil {
+		fake.ClearOverridesFromStub(arg1, arg2)
+	}
+}
+
+func (fake *OverrideRecorder) ClearOverridesFromCallCount() int {
+	fake.clearOverridesFromMutex.RLock()
+	defer fake.clearOverridesFromMutex.RUnlock()
+	return len(fake.clearOverridesFromArgsForCall)
+}
+
+func (fake *OverrideRecorder) ClearOverridesFromCalls(stub func(string, string)) {
+	fake.clearOverridesFromMutex.Lock()
+	defer fake.clearOverridesFromMutex.Unlock()
+	fake.ClearOverridesFromStub = stub
+}
+
+func (fake *OverrideRecorder) ClearOverridesFromArgsForCall(i int) (string, string) {
+	fake.clearOverridesFromMutex.RLock()
+	defer fake.clearOverridesFromMutex.RUnlock()
+	argsForCall := fake.clearOverridesFromArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *OverrideRecorder) OverrideSpec(arg1 string, arg2 time.Duration) error {
+	fake.overrideSpecMutex.Lock()
+	ret, specificReturn := fake.overrideSpecReturnsOnCall[len(fake.overrideSpecArgsForCall)]
+	fake.overrideSpecArgsForCall = append(fake.overrideSpecArgsForCall, struct {
+		arg1 string
+		arg2 time.Duration
+	}{arg1, arg2})
+	stub := fake.OverrideSpecStub
+	fakeReturns := fake.overrideSpecReturns
+	fake.recordInvocation("OverrideSpec", []interface{}{arg1, arg2})
+	fake.overrideSpecMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *OverrideRecorder) OverrideSpecCallCount() int {
+	fake.overrideSpecMutex.RLock()
+	defer fake.overrideSpecMutex.RUnlock()
+	return len(fake.overrideSpecArgsForCall)
+}
+
+func (fake *OverrideRecorder) OverrideSpecCalls(stub func(string, time.Duration) error) {
+	fake.overrideSpecMutex.Lock()
+	defer fake.overrideSpecMutex.Unlock()
+	fake.OverrideSpecStub = stub
+}
+
+func (fake *OverrideRecorder) OverrideSpecArgsForCall(i int) (string, time.Duration) {
+	fake.overrideSpecMutex.RLock()
+	defer fake.overrideSpecMutex.RUnlock()
+	argsForCall := fake.overrideSpecArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *OverrideRecorder) OverrideSpecReturns(result1 error) {
+	fake.overrideSpecMutex.Lock()
+	defer fake.overrideSpecMutex.Unlock()
+	fake.OverrideSpecStub = nil
+	fake.overrideSpecReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *OverrideRecorder) OverrideSpecReturnsOnCall(i int, result1 error) {
+	fake.overrideSpecMutex.Lock()
+	defer fake.overrideSpecMutex.Unlock()
+	fake.OverrideSpecStub = nil
+	if fake.overrideSpecReturnsOnCall == nil {
+		fake.overrideSpecReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.overrideSpecReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *OverrideRecorder) OverrideSpecFrom(arg1 string, arg2 time.Duration, arg3 string, arg4 string) error {
+	fake.overrideSpecFromMutex.Lock()
+	ret, specificReturn := fake.overrideSpecFromReturnsOnCall[len(fake.overrideSpecFromArgsForCall)]
+	fake.overrideSpecFromArgsForCall = append(fake.overrideSpecFromArgsForCall, struct {
+		arg1 string
+		arg2 time.Duration
+		arg3 string
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.OverrideSpecFromStub
+	fakeReturns := fake.overrideSpecFromReturns
+	fake.recordInvocation("OverrideSpecFrom", []interface{}{arg1, arg2, arg3, arg4})
+	fake.overrideSpecFromMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *OverrideRecorder) OverrideSpecFromCallCount() int {
+	fake.overrideSpecFromMutex.RLock()
+	defer fake.overrideSpecFromMutex.RUnlock()
+	return len(fake.overrideSpecFromArgsForCall)
+}
+
+func (fake *OverrideRecorder) OverrideSpecFromCalls(stub func(string, time.Duration, string, string) error) {
+	fake.overrideSpecFromMutex.Lock()
+	defer fake.overrideSpecFromMutex.Unlock()
+	fake.OverrideSpecFromStub = stub
+}
+
+func (fake *OverrideRecorder) OverrideSpecFromArgsForCall(i int) (string, time.Duration, string, string) {
+	fake.overrideSpecFromMutex.RLock()
+	defer fake.overrideSpecFromMutex.RUnlock()
+	argsForCall := fake.overrideSpecFromArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *OverrideRecorder) OverrideSpecFromReturns(result1 error) {
+	fake.overrideSpecFromMutex.Lock()
+	defer fake.overrideSpecFromMutex.Unlock()
+	fake.OverrideSpecFromStub = nil
+	fake.overrideSpecFromReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *OverrideRecorder) OverrideSpecFromReturnsOnCall(i int, result1 error) {
+	fake.overrideSpecFromMutex.Lock()
+	defer fake.overrideSpecFromMutex.Unlock()
+	fake.OverrideSpecFromStub = nil
+	if fake.overrideSpecFromReturnsOnCall == nil {
+		fake.overrideSpecFromReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.overrideSpecFromReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *OverrideRecorder) Overrides() []flogging.LevelOverride {
+	fake.overridesMutex.Lock()
+	ret, specificReturn := fake.overridesReturnsOnCall[len(fake.overridesArgsForCall)]
+	fake.overridesArgsForCall = append(fake.overridesArgsForCall, struct {
+	}{})
+	stub := fake.OverridesStub
+	fakeReturns := fake.overridesReturns
+	fake.recordInvocation("Overrides", []interface{}{})
+	fake.overridesMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *OverrideRecorder) OverridesCallCount() int {
+	fake.overridesMutex.RLock()
+	defer fake.overridesMutex.RUnlock()
+	return len(fake.overridesArgsForCall)
+}
+
+func (fake *OverrideRecorder) OverridesCalls(stub func() []flogging.LevelOverride) {
+	fake.overridesMutex.Lock()
+	defer fake.overridesMutex.Unlock()
+	fake.OverridesStub = stub
+}
+
+func (fake *OverrideRecorder) OverridesReturns(result1 []flogging.LevelOverride) {
+	fake.overridesMutex.Lock()
+	defer fake.overridesMutex.Unlock()
+	fake.OverridesStub = nil
+	fake.overridesReturns = struct {
+		result1 []flogging.LevelOverride
+	}{result1}
+}
+
+func (fake *OverrideRecorder) OverridesReturnsOnCall(i int, result1 []flogging.LevelOverride) {
+	fake.overridesMutex.Lock()
+	defer fake.overridesMutex.Unlock()
+	fake.OverridesStub = nil
+	if fake.overridesReturnsOnCall == nil {
+		fake.overridesReturnsOnCall = make(map[int]struct {
+			result1 []flogging.LevelOverride
+		})
+	}
+	fake.overridesReturnsOnCall[i] = struct {
+		result1 []flogging.LevelOverride
+	}{result1}
+}
+
+func (fake *OverrideRecorder) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.clearOverridesMutex.RLock()
+	defer fake.clearOverridesMutex.RUnlock()
+	fake.clearOverridesFromMutex.RLock()
+	defer fake.clearOverridesFromMutex.RUnlock()
+	fake.overrideSpecMutex.RLock()
+	defer fake.overrideSpecMutex.RUnlock()
+	fake.overrideSpecFromMutex.RLock()
+	defer fake.overrideSpecFromMutex.RUnlock()
+	fake.overridesMutex.RLock()
+	defer fake.overridesMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *OverrideRecorder) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ httpadmin.OverrideRecorder = new(OverrideRecorder)
@@ -0,0 +1,210 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+	"time"
+
+	"github.com/redresseur/flogging"
+	"github.com/redresseur/flogging/httpadmin"
+)
+
+type Overrider struct {
+	ClearOverridesStub        func()
+	clearOverridesMutex       sync.RWMutex
+	clearOverridesArgsForCall []struct {
+	}
+	OverrideSpecStub        func(string, time.Duration) error
+	overrideSpecMutex       sync.RWMutex
+	overrideSpecArgsForCall []struct {
+		arg1 string
+		arg2 time.Duration
+	}
+	overrideSpecReturns struct {
+		result1 error
+	}
+	overrideSpecReturnsOnCall map[int]struct {
+		result1 error
+	}
+	OverridesStub        func() []flogging.LevelOverride
+	overridesMutex       sync.RWMutex
+	overridesArgsForCall []struct {
+	}
+	overridesReturns struct {
+		result1 []flogging.LevelOverride
+	}
+	overridesReturnsOnCall map[int]struct {
+		result1 []flogging.LevelOverride
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *Overrider) ClearOverrides() {
+	fake.clearOverridesMutex.Lock()
+	fake.clearOverridesArgsForCall = append(fake.clearOverridesArgsForCall, struct {
+	}{})
+	stub := fake.ClearOverridesStub
+	fake.recordInvocation("ClearOverrides", []interface{}{})
+	fake.clearOverridesMutex.Unlock()
+	if stub != nil {
+		fake.ClearOverridesStub()
+	}
+}
+
+func (fake *Overrider) ClearOverridesCallCount() int {
+	fake.clearOverridesMutex.RLock()
+	defer fake.clearOverridesMutex.RUnlock()
+	return len(fake.clearOverridesArgsForCall)
+}
+
+func (fake *Overrider) ClearOverridesCalls(stub func()) {
+	fake.clearOverridesMutex.Lock()
+	defer fake.clearOverridesMutex.Unlock()
+	fake.ClearOverridesStub = stub
+}
+
+func (fake *Overrider) OverrideSpec(arg1 string, arg2 time.Duration) error {
+	fake.overrideSpecMutex.Lock()
+	ret, specificReturn := fake.overrideSpecReturnsOnCall[len(fake.overrideSpecArgsForCall)]
+	fake.overrideSpecArgsForCall = append(fake.overrideSpecArgsForCall, struct {
+		arg1 string
+		arg2 time.Duration
+	}{arg1, arg2})
+	stub := fake.OverrideSpecStub
+	fakeReturns := fake.overrideSpecReturns
+	fake.recordInvocation("OverrideSpec", []interface{}{arg1, arg2})
+	fake.overrideSpecMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Overrider) OverrideSpecCallCount() int {
+	fake.overrideSpecMutex.RLock()
+	defer fake.overrideSpecMutex.RUnlock()
+	return len(fake.overrideSpecArgsForCall)
+}
+
+func (fake *Overrider) OverrideSpecCalls(stub func(string, time.Duration) error) {
+	fake.overrideSpecMutex.Lock()
+	defer fake.overrideSpecMutex.Unlock()
+	fake.OverrideSpecStub = stub
+}
+
+func (fake *Overrider) OverrideSpecArgsForCall(i int) (string, time.Duration) {
+	fake.overrideSpecMutex.RLock()
+	defer fake.overrideSpecMutex.RUnlock()
+	argsForCall := fake.overrideSpecArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *Overrider) OverrideSpecReturns(result1 error) {
+	fake.overrideSpecMutex.Lock()
+	defer fake.overrideSpecMutex.Unlock()
+	fake.OverrideSpecStub = nil
+	fake.overrideSpecReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Overrider) OverrideSpecReturnsOnCall(i int, result1 error) {
+	fake.overrideSpecMutex.Lock()
+	defer fake.overrideSpecMutex.Unlock()
+	fake.OverrideSpecStub = nil
+	if fake.overrideSpecReturnsOnCall == nil {
+		fake.overrideSpecReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.overrideSpecReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Overrider) Overrides() []flogging.LevelOverride {
+	fake.overridesMutex.Lock()
+	ret, specificReturn := fake.overridesReturnsOnCall[len(fake.overridesArgsForCall)]
+	fake.overridesArgsForCall = append(fake.overridesArgsForCall, struct {
+	}{})
+	stub := fake.OverridesStub
+	fakeReturns := fake.overridesReturns
+	fake.recordInvocation("Overrides", []interface{}{})
+	fake.overridesMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Overrider) OverridesCallCount() int {
+	fake.overridesMutex.RLock()
+	defer fake.overridesMutex.RUnlock()
+	return len(fake.overridesArgsForCall)
+}
+
+func (fake *Overrider) OverridesCalls(stub func() []flogging.LevelOverride) {
+	fake.overridesMutex.Lock()
+	defer fake.overridesMutex.Unlock()
+	fake.OverridesStub = stub
+}
+
+func (fake *Overrider) OverridesReturns(result1 []flogging.LevelOverride) {
+	fake.overridesMutex.Lock()
+	defer fake.overridesMutex.Unlock()
+	fake.OverridesStub = nil
+	fake.overridesReturns = struct {
+		result1 []flogging.LevelOverride
+	}{result1}
+}
+
+func (fake *Overrider) OverridesReturnsOnCall(i int, result1 []flogging.LevelOverride) {
+	fake.overridesMutex.Lock()
+	defer fake.overridesMutex.Unlock()
+	fake.OverridesStub = nil
+	if fake.overridesReturnsOnCall == nil {
+		fake.overridesReturnsOnCall = make(map[int]struct {
+			result1 []flogging.LevelOverride
+		})
+	}
+	fake.overridesReturnsOnCall[i] = struct {
+		result1 []flogging.LevelOverride
+	}{result1}
+}
+
+func (fake *Overrider) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.clearOverridesMutex.RLock()
+	defer fake.clearOverridesMutex.RUnlock()
+	fake.overrideSpecMutex.RLock()
+	defer fake.overrideSpecMutex.RUnlock()
+	fake.overridesMutex.RLock()
+	defer fake.overridesMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *Overrider) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ httpadmin.Overrider = new(Overrider)
@@ -0,0 +1,117 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"github.com/redresseur/flogging"
+	"github.com/redresseur/flogging/httpadmin"
+)
+
+type RingEntries struct {
+	RingEntriesStub        func(flogging.RingFilter) ([]flogging.RingEntry, error)
+	ringEntriesMutex       sync.RWMutex
+	ringEntriesArgsForCall []struct {
+		arg1 flogging.RingFilter
+	}
+	ringEntriesReturns struct {
+		result1 []flogging.RingEntry
+		result2 error
+	}
+	ringEntriesReturnsOnCall map[int]struct {
+		result1 []flogging.RingEntry
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *RingEntries) RingEntries(arg1 flogging.RingFilter) ([]flogging.RingEntry, error) {
+	fake.ringEntriesMutex.Lock()
+	ret, specificReturn := fake.ringEntriesReturnsOnCall[len(fake.ringEntriesArgsForCall)]
+	fake.ringEntriesArgsForCall = append(fake.ringEntriesArgsForCall, struct {
+		arg1 flogging.RingFilter
+	}{arg1})
+	stub := fake.RingEntriesStub
+	fakeReturns := fake.ringEntriesReturns
+	fake.recordInvocation("RingEntries", []interface{}{arg1})
+	fake.ringEntriesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *RingEntries) RingEntriesCallCount() int {
+	fake.ringEntriesMutex.RLock()
+	defer fake.ringEntriesMutex.RUnlock()
+	return len(fake.ringEntriesArgsForCall)
+}
+
+func (fake *RingEntries) RingEntriesCalls(stub func(flogging.RingFilter) ([]flogging.RingEntry, error)) {
+	fake.ringEntriesMutex.Lock()
+	defer fake.ringEntriesMutex.Unlock()
+	fake.RingEntriesStub = stub
+}
+
+func (fake *RingEntries) RingEntriesArgsForCall(i int) flogging.RingFilter {
+	fake.ringEntriesMutex.RLock()
+	defer fake.ringEntriesMutex.RUnlock()
+	argsForCall := fake.ringEntriesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *RingEntries) RingEntriesReturns(result1 []flogging.RingEntry, result2 error) {
+	fake.ringEntriesMutex.Lock()
+	defer fake.ringEntriesMutex.Unlock()
+	fake.RingEntriesStub = nil
+	fake.ringEntriesReturns = struct {
+		result1 []flogging.RingEntry
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *RingEntries) RingEntriesReturnsOnCall(i int, result1 []flogging.RingEntry, result2 error) {
+	fake.ringEntriesMutex.Lock()
+	defer fake.ringEntriesMutex.Unlock()
+	fake.RingEntriesStub = nil
+	if fake.ringEntriesReturnsOnCall == nil {
+		fake.ringEntriesReturnsOnCall = make(map[int]struct {
+			result1 []flogging.RingEntry
+			result2 error
+		})
+	}
+	fake.ringEntriesReturnsOnCall[i] = struct {
+		result1 []flogging.RingEntry
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *RingEntries) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.ringEntriesMutex.RLock()
+	defer fake.ringEntriesMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *RingEntries) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ httpadmin.RingEntries = new(RingEntries)
@@ -0,0 +1,179 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"github.com/redresseur/flogging"
+	"github.com/redresseur/flogging/httpadmin"
+)
+
+type SpecHistory struct {
+	RollbackStub        func(uint64, string) error
+	rollbackMutex       sync.RWMutex
+	rollbackArgsForCall []struct {
+		arg1 uint64
+		arg2 string
+	}
+	rollbackReturns struct {
+		result1 error
+	}
+	rollbackReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SpecHistoryStub        func() []flogging.SpecChange
+	specHistoryMutex       sync.RWMutex
+	specHistoryArgsForCall []struct {
+	}
+	specHistoryReturns struct {
+		result1 []flogging.SpecChange
+	}
+	specHistoryReturnsOnCall map[int]struct {
+		result1 []flogging.SpecChange
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *SpecHistory) Rollback(arg1 uint64, arg2 string) error {
+	fake.rollbackMutex.Lock()
+	ret, specificReturn := fake.rollbackReturnsOnCall[len(fake.rollbackArgsForCall)]
+	fake.rollbackArgsForCall = append(fake.rollbackArgsForCall, struct {
+		arg1 uint64
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.RollbackStub
+	fakeReturns := fake.rollbackReturns
+	fake.recordInvocation("Rollback", []interface{}{arg1, arg2})
+	fake.rollbackMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *SpecHistory) RollbackCallCount() int {
+	fake.rollbackMutex.RLock()
+	defer fake.rollbackMutex.RUnlock()
+	return len(fake.rollbackArgsForCall)
+}
+
+func (fake *SpecHistory) RollbackCalls(stub func(uint64, string) error) {
+	fake.rollbackMutex.Lock()
+	defer fake.rollbackMutex.Unlock()
+	fake.RollbackStub = stub
+}
+
+func (fake *SpecHistory) RollbackArgsForCall(i int) (uint64, string) {
+	fake.rollbackMutex.RLock()
+	defer fake.rollbackMutex.RUnlock()
+	argsForCall := fake.rollbackArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *SpecHistory) RollbackReturns(result1 error) {
+	fake.rollbackMutex.Lock()
+	defer fake.rollbackMutex.Unlock()
+	fake.RollbackStub = nil
+	fake.rollbackReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *SpecHistory) RollbackReturnsOnCall(i int, result1 error) {
+	fake.rollbackMutex.Lock()
+	defer fake.rollbackMutex.Unlock()
+	fake.RollbackStub = nil
+	if fake.rollbackReturnsOnCall == nil {
+		fake.rollbackReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.rollbackReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *SpecHistory) SpecHistory() []flogging.SpecChange {
+	fake.specHistoryMutex.Lock()
+	ret, specificReturn := fake.specHistoryReturnsOnCall[len(fake.specHistoryArgsForCall)]
+	fake.specHistoryArgsForCall = append(fake.specHistoryArgsForCall, struct {
+	}{})
+	stub := fake.SpecHistoryStub
+	fakeReturns := fake.specHistoryReturns
+	fake.recordInvocation("SpecHistory", []interface{}{})
+	fake.specHistoryMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *SpecHistory) SpecHistoryCallCount() int {
+	fake.specHistoryMutex.RLock()
+	defer fake.specHistoryMutex.RUnlock()
+	return len(fake.specHistoryArgsForCall)
+}
+
+func (fake *SpecHistory) SpecHistoryCalls(stub func() []flogging.SpecChange) {
+	fake.specHistoryMutex.Lock()
+	defer fake.specHistoryMutex.Unlock()
+	fake.SpecHistoryStub = stub
+}
+
+func (fake *SpecHistory) SpecHistoryReturns(result1 []flogging.SpecChange) {
+	fake.specHistoryMutex.Lock()
+	defer fake.specHistoryMutex.Unlock()
+	fake.SpecHistoryStub = nil
+	fake.specHistoryReturns = struct {
+		result1 []flogging.SpecChange
+	}{result1}
+}
+
+func (fake *SpecHistory) SpecHistoryReturnsOnCall(i int, result1 []flogging.SpecChange) {
+	fake.specHistoryMutex.Lock()
+	defer fake.specHistoryMutex.Unlock()
+	fake.SpecHistoryStub = nil
+	if fake.specHistoryReturnsOnCall == nil {
+		fake.specHistoryReturnsOnCall = make(map[int]struct {
+			result1 []flogging.SpecChange
+		})
+	}
+	fake.specHistoryReturnsOnCall[i] = struct {
+		result1 []flogging.SpecChange
+	}{result1}
+}
+
+func (fake *SpecHistory) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.rollbackMutex.RLock()
+	defer fake.rollbackMutex.RUnlock()
+	fake.specHistoryMutex.RLock()
+	defer fake.specHistoryMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *SpecHistory) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ httpadmin.SpecHistory = new(SpecHistory)
@@ -0,0 +1,412 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"github.com/redresseur/flogging"
+	"github.com/redresseur/flogging/httpadmin"
+)
+
+type VersionedLogging struct {
+	ActivateSpecStub        func(string) error
+	activateSpecMutex       sync.RWMutex
+	activateSpecArgsForCall []struct {
+		arg1 string
+	}
+	activateSpecReturns struct {
+		result1 error
+	}
+	activateSpecReturnsOnCall map[int]struct {
+		result1 error
+	}
+	PatchSpecFromStub        func(flogging.SpecPatch, string, string, string) (flogging.VersionedSpec, error)
+	patchSpecFromMutex       sync.RWMutex
+	patchSpecFromArgsForCall []struct {
+		arg1 flogging.SpecPatch
+		arg2 string
+		arg3 string
+		arg4 string
+	}
+	patchSpecFromReturns struct {
+		result1 flogging.VersionedSpec
+		result2 error
+	}
+	patchSpecFromReturnsOnCall map[int]struct {
+		result1 flogging.VersionedSpec
+		result2 error
+	}
+	ReplaceSpecFromStub        func(string, string, string, string) (flogging.VersionedSpec, error)
+	replaceSpecFromMutex       sync.RWMutex
+	replaceSpecFromArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 string
+	}
+	replaceSpecFromReturns struct {
+		result1 flogging.VersionedSpec
+		result2 error
+	}
+	replaceSpecFromReturnsOnCall map[int]struct {
+		result1 flogging.VersionedSpec
+		result2 error
+	}
+	SpecStub        func() string
+	specMutex       sync.RWMutex
+	specArgsForCall []struct {
+	}
+	specReturns struct {
+		result1 string
+	}
+	specReturnsOnCall map[int]struct {
+		result1 string
+	}
+	VersionedSpecStub        func() flogging.VersionedSpec
+	versionedSpecMutex       sync.RWMutex
+	versionedSpecArgsForCall []struct {
+	}
+	versionedSpecReturns struct {
+		result1 flogging.VersionedSpec
+	}
+	versionedSpecReturnsOnCall map[int]struct {
+		result1 flogging.VersionedSpec
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *VersionedLogging) ActivateSpec(arg1 string) error {
+	fake.activateSpecMutex.Lock()
+	ret, specificReturn := fake.activateSpecReturnsOnCall[len(fake.activateSpecArgsForCall)]
+	fake.activateSpecArgsForCall = append(fake.activateSpecArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.ActivateSpecStub
+	fakeReturns := fake.activateSpecReturns
+	fake.recordInvocation("ActivateSpec", []interface{}{arg1})
+	fake.activateSpecMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *VersionedLogging) ActivateSpecCallCount() int {
+	fake.activateSpecMutex.RLock()
+	defer fake.activateSpecMutex.RUnlock()
+	return len(fake.activateSpecArgsForCall)
+}
+
+func (fake *VersionedLogging) ActivateSpecCalls(stub func(string) error) {
+	fake.activateSpecMutex.Lock()
+	defer fake.activateSpecMutex.Unlock()
+	fake.ActivateSpecStub = stub
+}
+
+func (fake *VersionedLogging) ActivateSpecArgsForCall(i int) string {
+	fake.activateSpecMutex.RLock()
+	defer fake.activateSpecMutex.RUnlock()
+	argsForCall := fake.activateSpecArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *VersionedLogging) ActivateSpecReturns(result1 error) {
+	fake.activateSpecMutex.Lock()
+	defer fake.activateSpecMutex.Unlock()
+	fake.ActivateSpecStub = nil
+	fake.activateSpecReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *VersionedLogging) ActivateSpecReturnsOnCall(i int, result1 error) {
+	fake.activateSpecMutex.Lock()
+	defer fake.activateSpecMutex.Unlock()
+	fake.ActivateSpecStub = nil
+	if fake.activateSpecReturnsOnCall == nil {
+		fake.activateSpecReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.activateSpecReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *VersionedLogging) PatchSpecFrom(arg1 flogging.SpecPatch, arg2 string, arg3 string, arg4 string) (flogging.VersionedSpec, error) {
+	fake.patchSpecFromMutex.Lock()
+	ret, specificReturn := fake.patchSpecFromReturnsOnCall[len(fake.patchSpecFromArgsForCall)]
+	fake.patchSpecFromArgsForCall = append(fake.patchSpecFromArgsForCall, struct {
+		arg1 flogging.SpecPatch
+		arg2 string
+		arg3 string
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.PatchSpecFromStub
+	fakeReturns := fake.patchSpecFromReturns
+	fake.recordInvocation("PatchSpecFrom", []interface{}{arg1, arg2, arg3, arg4})
+	fake.patchSpecFromMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *VersionedLogging) PatchSpecFromCallCount() int {
+	fake.patchSpecFromMutex.RLock()
+	defer fake.patchSpecFromMutex.RUnlock()
+	return len(fake.patchSpecFromArgsForCall)
+}
+
+func (fake *VersionedLogging) PatchSpecFromCalls(stub func(flogging.SpecPatch, string, string, string) (flogging.VersionedSpec, error)) {
+	fake.patchSpecFromMutex.Lock()
+	defer fake.patchSpecFromMutex.Unlock()
+	fake.PatchSpecFromStub = stub
+}
+
+func (fake *VersionedLogging) PatchSpecFromArgsForCall(i int) (flogging.SpecPatch, string, string, string) {
+	fake.patchSpecFromMutex.RLock()
+	defer fake.patchSpecFromMutex.RUnlock()
+	argsForCall := fake.patchSpecFromArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *VersionedLogging) PatchSpecFromReturns(result1 flogging.VersionedSpec, result2 error) {
+	fake.patchSpecFromMutex.Lock()
+	defer fake.patchSpecFromMutex.Unlock()
+	fake.PatchSpecFromStub = nil
+	fake.patchSpecFromReturns = struct {
+		result1 flogging.VersionedSpec
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *VersionedLogging) PatchSpecFromReturnsOnCall(i int, result1 flogging.VersionedSpec, result2 error) {
+	fake.patchSpecFromMutex.Lock()
+	defer fake.patchSpecFromMutex.Unlock()
+	fake.PatchSpecFromStub = nil
+	if fake.patchSpecFromReturnsOnCall == nil {
+		fake.patchSpecFromReturnsOnCall = make(map[int]struct {
+			result1 flogging.VersionedSpec
+			result2 error
+		})
+	}
+	fake.patchSpecFromReturnsOnCall[i] = struct {
+		result1 flogging.VersionedSpec
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *VersionedLogging) ReplaceSpecFrom(arg1 string, arg2 string, arg3 string, arg4 string) (flogging.VersionedSpec, error) {
+	fake.replaceSpecFromMutex.Lock()
+	ret, specificReturn := fake.replaceSpecFromReturnsOnCall[len(fake.replaceSpecFromArgsForCall)]
+	fake.replaceSpecFromArgsForCall = append(fake.replaceSpecFromArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.ReplaceSpecFromStub
+	fakeReturns := fake.replaceSpecFromReturns
+	fake.recordInvocation("ReplaceSpecFrom", []interface{}{arg1, arg2, arg3, arg4})
+	fake.replaceSpecFromMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *VersionedLogging) ReplaceSpecFromCallCount() int {
+	fake.replaceSpecFromMutex.RLock()
+	defer fake.replaceSpecFromMutex.RUnlock()
+	return len(fake.replaceSpecFromArgsForCall)
+}
+
+func (fake *VersionedLogging) ReplaceSpecFromCalls(stub func(string, string, string, string) (flogging.VersionedSpec, error)) {
+	fake.replaceSpecFromMutex.Lock()
+	defer fake.replaceSpecFromMutex.Unlock()
+	fake.ReplaceSpecFromStub = stub
+}
+
+func (fake *VersionedLogging) ReplaceSpecFromArgsForCall(i int) (string, string, string, string) {
+	fake.replaceSpecFromMutex.RLock()
+	defer fake.replaceSpecFromMutex.RUnlock()
+	argsForCall := fake.replaceSpecFromArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *VersionedLogging) ReplaceSpecFromReturns(result1 flogging.VersionedSpec, result2 error) {
+	fake.replaceSpecFromMutex.Lock()
+	defer fake.replaceSpecFromMutex.Unlock()
+	fake.ReplaceSpecFromStub = nil
+	fake.replaceSpecFromReturns = struct {
+		result1 flogging.VersionedSpec
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *VersionedLogging) ReplaceSpecFromReturnsOnCall(i int, result1 flogging.VersionedSpec, result2 error) {
+	fake.replaceSpecFromMutex.Lock()
+	defer fake.replaceSpecFromMutex.Unlock()
+	fake.ReplaceSpecFromStub = nil
+	if fake.replaceSpecFromReturnsOnCall == nil {
+		fake.replaceSpecFromReturnsOnCall = make(map[int]struct {
+			result1 flogging.VersionedSpec
+			result2 error
+		})
+	}
+	fake.replaceSpecFromReturnsOnCall[i] = struct {
+		result1 flogging.VersionedSpec
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *VersionedLogging) Spec() string {
+	fake.specMutex.Lock()
+	ret, specificReturn := fake.specReturnsOnCall[len(fake.specArgsForCall)]
+	fake.specArgsForCall = append(fake.specArgsForCall, struct {
+	}{})
+	stub := fake.SpecStub
+	fakeReturns := fake.specReturns
+	fake.recordInvocation("Spec", []interface{}{})
+	fake.specMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *VersionedLogging) SpecCallCount() int {
+	fake.specMutex.RLock()
+	defer fake.specMutex.RUnlock()
+	return len(fake.specArgsForCall)
+}
+
+func (fake *VersionedLogging) SpecCalls(stub func() string) {
+	fake.specMutex.Lock()
+	defer fake.specMutex.Unlock()
+	fake.SpecStub = stub
+}
+
+func (fake *VersionedLogging) SpecReturns(result1 string) {
+	fake.specMutex.Lock()
+	defer fake.specMutex.Unlock()
+	fake.SpecStub = nil
+	fake.specReturns = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *VersionedLogging) SpecReturnsOnCall(i int, result1 string) {
+	fake.specMutex.Lock()
+	defer fake.specMutex.Unlock()
+	fake.SpecStub = nil
+	if fake.specReturnsOnCall == nil {
+		fake.specReturnsOnCall = make(map[int]struct {
+			result1 string
+		})
+	}
+	fake.specReturnsOnCall[i] = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *VersionedLogging) VersionedSpec() flogging.VersionedSpec {
+	fake.versionedSpecMutex.Lock()
+	ret, specificReturn := fake.versionedSpecReturnsOnCall[len(fake.versionedSpecArgsForCall)]
+	fake.versionedSpecArgsForCall = append(fake.versionedSpecArgsForCall, struct {
+	}{})
+	stub := fake.VersionedSpecStub
+	fakeReturns := fake.versionedSpecReturns
+	fake.recordInvocation("VersionedSpec", []interface{}{})
+	fake.versionedSpecMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *VersionedLogging) VersionedSpecCallCount() int {
+	fake.versionedSpecMutex.RLock()
+	defer fake.versionedSpecMutex.RUnlock()
+	return len(fake.versionedSpecArgsForCall)
+}
+
+func (fake *VersionedLogging) VersionedSpecCalls(stub func() flogging.VersionedSpec) {
+	fake.versionedSpecMutex.Lock()
+	defer fake.versionedSpecMutex.Unlock()
+	fake.VersionedSpecStub = stub
+}
+
+func (fake *VersionedLogging) VersionedSpecReturns(result1 flogging.VersionedSpec) {
+	fake.versionedSpecMutex.Lock()
+	defer fake.versionedSpecMutex.Unlock()
+	fake.VersionedSpecStub = nil
+	fake.versionedSpecReturns = struct {
+		result1 flogging.VersionedSpec
+	}{result1}
+}
+
+func (fake *VersionedLogging) VersionedSpecReturnsOnCall(i int, result1 flogging.VersionedSpec) {
+	fake.versionedSpecMutex.Lock()
+	defer fake.versionedSpecMutex.Unlock()
+	fake.VersionedSpecStub = nil
+	if fake.versionedSpecReturnsOnCall == nil {
+		fake.versionedSpecReturnsOnCall = make(map[int]struct {
+			result1 flogging.VersionedSpec
+		})
+	}
+	fake.versionedSpecReturnsOnCall[i] = struct {
+		result1 flogging.VersionedSpec
+	}{result1}
+}
+
+func (fake *VersionedLogging) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.activateSpecMutex.RLock()
+	defer fake.activateSpecMutex.RUnlock()
+	fake.patchSpecFromMutex.RLock()
+	defer fake.patchSpecFromMutex.RUnlock()
+	fake.replaceSpecFromMutex.RLock()
+	defer fake.replaceSpecFromMutex.RUnlock()
+	fake.specMutex.RLock()
+	defer fake.specMutex.RUnlock()
+	fake.versionedSpecMutex.RLock()
+	defer fake.versionedSpecMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *VersionedLogging) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ httpadmin.VersionedLogging = new(VersionedLogging)
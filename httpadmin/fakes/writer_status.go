@@ -0,0 +1,103 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"github.com/redresseur/flogging"
+	"github.com/redresseur/flogging/httpadmin"
+)
+
+type WriterStatus struct {
+	WriterStatusStub        func() flogging.WriterStatus
+	writerStatusMutex       sync.RWMutex
+	writerStatusArgsForCall []struct {
+	}
+	writerStatusReturns struct {
+		result1 flogging.WriterStatus
+	}
+	writerStatusReturnsOnCall map[int]struct {
+		result1 flogging.WriterStatus
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *WriterStatus) WriterStatus() flogging.WriterStatus {
+	fake.writerStatusMutex.Lock()
+	ret, specificReturn := fake.writerStatusReturnsOnCall[len(fake.writerStatusArgsForCall)]
+	fake.writerStatusArgsForCall = append(fake.writerStatusArgsForCall, struct {
+	}{})
+	stub := fake.WriterStatusStub
+	fakeReturns := fake.writerStatusReturns
+	fake.recordInvocation("WriterStatus", []interface{}{})
+	fake.writerStatusMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *WriterStatus) WriterStatusCallCount() int {
+	fake.writerStatusMutex.RLock()
+	defer fake.writerStatusMutex.RUnlock()
+	return len(fake.writerStatusArgsForCall)
+}
+
+func (fake *WriterStatus) WriterStatusCalls(stub func() flogging.WriterStatus) {
+	fake.writerStatusMutex.Lock()
+	defer fake.writerStatusMutex.Unlock()
+	fake.WriterStatusStub = stub
+}
+
+func (fake *WriterStatus) WriterStatusReturns(result1 flogging.WriterStatus) {
+	fake.writerStatusMutex.Lock()
+	defer fake.writerStatusMutex.Unlock()
+	fake.WriterStatusStub = nil
+	fake.writerStatusReturns = struct {
+		result1 flogging.WriterStatus
+	}{result1}
+}
+
+func (fake *WriterStatus) WriterStatusReturnsOnCall(i int, result1 flogging.WriterStatus) {
+	fake.writerStatusMutex.Lock()
+	defer fake.writerStatusMutex.Unlock()
+	fake.WriterStatusStub = nil
+	if fake.writerStatusReturnsOnCall == nil {
+		fake.writerStatusReturnsOnCall = make(map[int]struct {
+			result1 flogging.WriterStatus
+		})
+	}
+	fake.writerStatusReturnsOnCall[i] = struct {
+		result1 flogging.WriterStatus
+	}{result1}
+}
+
+func (fake *WriterStatus) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.writerStatusMutex.RLock()
+	defer fake.writerStatusMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *WriterStatus) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ httpadmin.WriterStatus = new(WriterStatus)
@@ -0,0 +1,70 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpadmin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/redresseur/flogging"
+)
+
+//go:generate counterfeiter -o fakes/formatter.go -fake-name Formatter . Formatter
+
+type Formatter interface {
+	Format() string
+	SetFormat(format string) error
+}
+
+// LogFormat is the request and response payload used to change and report
+// the log record format.
+type LogFormat struct {
+	Format string `json:"format"`
+}
+
+// NewFormatHandler creates a FormatHandler for the global logging system.
+// It is intended to be served at /logspec/format.
+func NewFormatHandler() *FormatHandler {
+	return &FormatHandler{
+		Formatter: flogging.Global,
+		Logger:    flogging.MustGetLogger("flogging.httpadmin"),
+	}
+}
+
+// FormatHandler reports the log record format on GET and changes it on PUT.
+// The format accepts the same values as the Format field of flogging.Config.
+type FormatHandler struct {
+	Formatter Formatter
+	Logger    *flogging.FabricLogger
+}
+
+func (h *FormatHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodPut:
+		var logFormat LogFormat
+		decoder := json.NewDecoder(req.Body)
+		if err := decoder.Decode(&logFormat); err != nil {
+			sendResponse(h.Logger, resp, http.StatusBadRequest, err)
+			return
+		}
+		req.Body.Close()
+
+		if err := h.Formatter.SetFormat(logFormat.Format); err != nil {
+			sendResponse(h.Logger, resp, http.StatusBadRequest, err)
+			return
+		}
+		resp.WriteHeader(http.StatusNoContent)
+
+	case http.MethodGet:
+		sendResponse(h.Logger, resp, http.StatusOK, &LogFormat{Format: h.Formatter.Format()})
+
+	default:
+		err := fmt.Errorf("invalid request method: %s", req.Method)
+		sendResponse(h.Logger, resp, http.StatusBadRequest, err)
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpadmin_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/redresseur/flogging"
+	"github.com/redresseur/flogging/httpadmin"
+	"github.com/redresseur/flogging/httpadmin/fakes"
+)
+
+var _ = Describe("FormatHandler", func() {
+	var (
+		fakeFormatter *fakes.Formatter
+		handler       *httpadmin.FormatHandler
+	)
+
+	BeforeEach(func() {
+		fakeFormatter = &fakes.Formatter{}
+		fakeFormatter.FormatReturns("json")
+		handler = &httpadmin.FormatHandler{
+			Formatter: fakeFormatter,
+		}
+	})
+
+	It("responds with the current format", func() {
+		req := httptest.NewRequest("GET", "/logspec/format", nil)
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		Expect(fakeFormatter.FormatCallCount()).To(Equal(1))
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Header().Get("Content-Type")).To(Equal("application/json"))
+		Expect(resp.Body).To(MatchJSON(`{"format": "json"}`))
+	})
+
+	It("sets the current format", func() {
+		req := httptest.NewRequest("PUT", "/logspec/format", strings.NewReader(`{"format": "logfmt"}`))
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		Expect(fakeFormatter.SetFormatCallCount()).To(Equal(1))
+		Expect(fakeFormatter.SetFormatArgsForCall(0)).To(Equal("logfmt"))
+		Expect(resp.Code).To(Equal(http.StatusNoContent))
+	})
+
+	Context("when the payload cannot be decoded", func() {
+		It("responds with an error", func() {
+			req := httptest.NewRequest("PUT", "/logspec/format", strings.NewReader(`goo`))
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(fakeFormatter.SetFormatCallCount()).To(Equal(0))
+			Expect(resp.Code).To(Equal(http.StatusBadRequest))
+			Expect(resp.Body).To(MatchJSON(`{"error": "invalid character 'g' looking for beginning of value"}`))
+		})
+	})
+
+	Context("when setting the format fails", func() {
+		BeforeEach(func() {
+			fakeFormatter.SetFormatReturns(errors.New("ewww; that's not a format"))
+		})
+
+		It("responds with an error", func() {
+			req := httptest.NewRequest("PUT", "/logspec/format", strings.NewReader(`{"format": "%{color:bad}"}`))
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(resp.Code).To(Equal(http.StatusBadRequest))
+			Expect(resp.Body).To(MatchJSON(`{"error": "ewww; that's not a format"}`))
+		})
+	})
+
+	Context("when an unsupported method is used", func() {
+		It("responds with an error", func() {
+			req := httptest.NewRequest("DELETE", "/logspec/format", nil)
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(resp.Code).To(Equal(http.StatusBadRequest))
+			Expect(resp.Body).To(MatchJSON(`{"error": "invalid request method: DELETE"}`))
+		})
+	})
+
+	Describe("NewFormatHandler", func() {
+		It("constructs a handler for the global logging system", func() {
+			formatHandler := httpadmin.NewFormatHandler()
+			Expect(formatHandler.Formatter).To(Equal(flogging.Global))
+			Expect(formatHandler.Logger).NotTo(BeNil())
+		})
+	})
+})
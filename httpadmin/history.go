@@ -0,0 +1,79 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpadmin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/redresseur/flogging"
+)
+
+//go:generate counterfeiter -o fakes/spec_history.go -fake-name SpecHistory . SpecHistory
+
+type SpecHistory interface {
+	SpecHistory() []flogging.SpecChange
+	Rollback(id uint64, caller string) error
+}
+
+// SpecHistoryList is the response payload that lists the spec history.
+type SpecHistoryList struct {
+	History []flogging.SpecChange `json:"history"`
+}
+
+// RollbackRequest is the request payload used to activate the spec of a
+// history entry again.
+type RollbackRequest struct {
+	ID uint64 `json:"id"`
+}
+
+// NewHistoryHandler creates a HistoryHandler for the global logging system.
+// It is intended to be served at /logspec/history.
+func NewHistoryHandler() *HistoryHandler {
+	return &HistoryHandler{
+		SpecHistory: flogging.Global,
+		Logger:      flogging.MustGetLogger("flogging.httpadmin"),
+	}
+}
+
+// HistoryHandler lists the recent logging spec changes on GET and rolls back
+// to the spec of a history entry on POST.
+type HistoryHandler struct {
+	SpecHistory SpecHistory
+	Logger      *flogging.FabricLogger
+}
+
+func (h *HistoryHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodPost:
+		var rollback RollbackRequest
+		decoder := json.NewDecoder(req.Body)
+		if err := decoder.Decode(&rollback); err != nil {
+			sendResponse(h.Logger, resp, http.StatusBadRequest, err)
+			return
+		}
+		req.Body.Close()
+
+		if err := h.SpecHistory.Rollback(rollback.ID, requestCaller(req)); err != nil {
+			sendResponse(h.Logger, resp, http.StatusBadRequest, err)
+			return
+		}
+		resp.WriteHeader(http.StatusNoContent)
+
+	case http.MethodGet:
+		list := SpecHistoryList{History: h.SpecHistory.SpecHistory()}
+		if list.History == nil {
+			list.History = []flogging.SpecChange{}
+		}
+		sendResponse(h.Logger, resp, http.StatusOK, &list)
+
+	default:
+		err := fmt.Errorf("invalid request method: %s", req.Method)
+		sendResponse(h.Logger, resp, http.StatusBadRequest, err)
+	}
+}
@@ -0,0 +1,141 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpadmin_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/redresseur/flogging"
+	"github.com/redresseur/flogging/httpadmin"
+	"github.com/redresseur/flogging/httpadmin/fakes"
+)
+
+var _ = Describe("HistoryHandler", func() {
+	var (
+		fakeSpecHistory *fakes.SpecHistory
+		handler         *httpadmin.HistoryHandler
+	)
+
+	BeforeEach(func() {
+		fakeSpecHistory = &fakes.SpecHistory{}
+		handler = &httpadmin.HistoryHandler{
+			SpecHistory: fakeSpecHistory,
+		}
+	})
+
+	It("responds with the spec history", func() {
+		fakeSpecHistory.SpecHistoryReturns([]flogging.SpecChange{
+			{ID: 1, Time: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), Spec: "info", Source: "default"},
+			{ID: 2, Time: time.Date(2020, 1, 2, 3, 5, 0, 0, time.UTC), Spec: "debug", Source: "httpadmin", Caller: "admin"},
+		})
+
+		req := httptest.NewRequest("GET", "/logspec/history", nil)
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		Expect(fakeSpecHistory.SpecHistoryCallCount()).To(Equal(1))
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Header().Get("Content-Type")).To(Equal("application/json"))
+		Expect(resp.Body).To(MatchJSON(`{
+			"history": [
+				{"id": 1, "time": "2020-01-02T03:04:05Z", "spec": "info", "source": "default"},
+				{"id": 2, "time": "2020-01-02T03:05:00Z", "spec": "debug", "source": "httpadmin", "caller": "admin"}
+			]
+		}`))
+	})
+
+	It("responds with an empty history", func() {
+		req := httptest.NewRequest("GET", "/logspec/history", nil)
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body).To(MatchJSON(`{"history": []}`))
+	})
+
+	It("rolls back to a history entry", func() {
+		req := httptest.NewRequest("POST", "/logspec/history", strings.NewReader(`{"id": 7}`))
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		Expect(resp.Code).To(Equal(http.StatusNoContent))
+		Expect(fakeSpecHistory.RollbackCallCount()).To(Equal(1))
+		id, caller := fakeSpecHistory.RollbackArgsForCall(0)
+		Expect(id).To(Equal(uint64(7)))
+		Expect(caller).To(Equal(req.RemoteAddr))
+	})
+
+	Context("when the client is authenticated with TLS", func() {
+		It("identifies the caller by the certificate common name", func() {
+			req := httptest.NewRequest("POST", "/logspec/history", strings.NewReader(`{"id": 7}`))
+			req.TLS = &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: "operator"}}},
+			}
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(resp.Code).To(Equal(http.StatusNoContent))
+			_, caller := fakeSpecHistory.RollbackArgsForCall(0)
+			Expect(caller).To(Equal("operator"))
+		})
+	})
+
+	Context("when the rollback payload cannot be decoded", func() {
+		It("responds with an error payload", func() {
+			req := httptest.NewRequest("POST", "/logspec/history", strings.NewReader(`goo`))
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(fakeSpecHistory.RollbackCallCount()).To(Equal(0))
+			Expect(resp.Code).To(Equal(http.StatusBadRequest))
+			Expect(resp.Body).To(MatchJSON(`{"error": "invalid character 'g' looking for beginning of value"}`))
+		})
+	})
+
+	Context("when the rollback fails", func() {
+		BeforeEach(func() {
+			fakeSpecHistory.RollbackReturns(errors.New("spec history entry 7 not found"))
+		})
+
+		It("responds with an error payload", func() {
+			req := httptest.NewRequest("POST", "/logspec/history", strings.NewReader(`{"id": 7}`))
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(resp.Code).To(Equal(http.StatusBadRequest))
+			Expect(resp.Body).To(MatchJSON(`{"error": "spec history entry 7 not found"}`))
+		})
+	})
+
+	Context("when an unsupported method is used", func() {
+		It("responds with an error", func() {
+			req := httptest.NewRequest("PUT", "/logspec/history", nil)
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(resp.Code).To(Equal(http.StatusBadRequest))
+			Expect(resp.Body).To(MatchJSON(`{"error": "invalid request method: PUT"}`))
+		})
+	})
+
+	Describe("NewHistoryHandler", func() {
+		It("constructs a handler for the global spec history", func() {
+			historyHandler := httpadmin.NewHistoryHandler()
+			Expect(historyHandler.SpecHistory).To(Equal(flogging.Global))
+			Expect(historyHandler.Logger).NotTo(BeNil())
+		})
+	})
+})
@@ -0,0 +1,91 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpadmin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/redresseur/flogging"
+)
+
+//go:generate counterfeiter -o fakes/logger_registry.go -fake-name LoggerRegistry . LoggerRegistry
+
+type LoggerRegistry interface {
+	Loggers() []flogging.LoggerInfo
+	LoggerInfo(name string) flogging.LoggerInfo
+}
+
+// LoggerList is the response payload that lists the known loggers.
+type LoggerList struct {
+	Loggers []flogging.LoggerInfo `json:"loggers"`
+}
+
+// NewLoggersHandler creates a LoggersHandler for the global logging system.
+// It is intended to be served at /logspec/loggers.
+func NewLoggersHandler() *LoggersHandler {
+	return &LoggersHandler{
+		Registry: flogging.Global,
+		Logger:   flogging.MustGetLogger("flogging.httpadmin"),
+	}
+}
+
+// LoggersHandler lists the known loggers with their effective level, the
+// spec segment that determined it, and the number of entries written.
+type LoggersHandler struct {
+	Registry LoggerRegistry
+	Logger   *flogging.FabricLogger
+}
+
+func (h *LoggersHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		err := fmt.Errorf("invalid request method: %s", req.Method)
+		sendResponse(h.Logger, resp, http.StatusBadRequest, err)
+		return
+	}
+
+	list := LoggerList{Loggers: h.Registry.Loggers()}
+	if list.Loggers == nil {
+		list.Loggers = []flogging.LoggerInfo{}
+	}
+	sendResponse(h.Logger, resp, http.StatusOK, &list)
+}
+
+// NewLoggerHandler creates a LoggerHandler for the global logging system. It
+// is intended to be served under /logspec/loggers/ with the prefix stripped.
+func NewLoggerHandler() *LoggerHandler {
+	return &LoggerHandler{
+		Registry: flogging.Global,
+		Logger:   flogging.MustGetLogger("flogging.httpadmin"),
+	}
+}
+
+// LoggerHandler reports the effective level of the logger named by the
+// request path. The logger does not need to have been created.
+type LoggerHandler struct {
+	Registry LoggerRegistry
+	Logger   *flogging.FabricLogger
+}
+
+func (h *LoggerHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		err := fmt.Errorf("invalid request method: %s", req.Method)
+		sendResponse(h.Logger, resp, http.StatusBadRequest, err)
+		return
+	}
+
+	name := strings.Trim(req.URL.Path, "/")
+	if name == "" {
+		sendResponse(h.Logger, resp, http.StatusBadRequest, errors.New("missing logger name"))
+		return
+	}
+
+	info := h.Registry.LoggerInfo(name)
+	sendResponse(h.Logger, resp, http.StatusOK, &info)
+}
@@ -0,0 +1,153 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpadmin_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/redresseur/flogging"
+	"github.com/redresseur/flogging/httpadmin"
+	"github.com/redresseur/flogging/httpadmin/fakes"
+	"go.uber.org/zap/zapcore"
+)
+
+var _ = Describe("LoggersHandler", func() {
+	var (
+		fakeRegistry *fakes.LoggerRegistry
+		handler      *httpadmin.LoggersHandler
+	)
+
+	BeforeEach(func() {
+		fakeRegistry = &fakes.LoggerRegistry{}
+		handler = &httpadmin.LoggersHandler{
+			Registry: fakeRegistry,
+		}
+	})
+
+	It("responds with the known loggers", func() {
+		fakeRegistry.LoggersReturns([]flogging.LoggerInfo{
+			{Name: "gossip", Level: zapcore.DebugLevel, Segment: "gossip=debug", Entries: 12},
+			{Name: "ledger", Level: zapcore.InfoLevel, Segment: "info", Default: true},
+		})
+
+		req := httptest.NewRequest("GET", "/logspec/loggers", nil)
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		Expect(fakeRegistry.LoggersCallCount()).To(Equal(1))
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Header().Get("Content-Type")).To(Equal("application/json"))
+		Expect(resp.Body).To(MatchJSON(`{
+			"loggers": [
+				{"name": "gossip", "level": "debug", "segment": "gossip=debug", "default": false, "entries": 12},
+				{"name": "ledger", "level": "info", "segment": "info", "default": true, "entries": 0}
+			]
+		}`))
+	})
+
+	It("responds with an empty list when there are no loggers", func() {
+		req := httptest.NewRequest("GET", "/logspec/loggers", nil)
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body).To(MatchJSON(`{"loggers": []}`))
+	})
+
+	Context("when an unsupported method is used", func() {
+		It("responds with an error", func() {
+			req := httptest.NewRequest("POST", "/logspec/loggers", nil)
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(fakeRegistry.LoggersCallCount()).To(Equal(0))
+			Expect(resp.Code).To(Equal(http.StatusBadRequest))
+			Expect(resp.Body).To(MatchJSON(`{"error": "invalid request method: POST"}`))
+		})
+	})
+
+	Describe("NewLoggersHandler", func() {
+		It("constructs a handler for the global logger registry", func() {
+			loggersHandler := httpadmin.NewLoggersHandler()
+			Expect(loggersHandler.Registry).To(Equal(flogging.Global))
+			Expect(loggersHandler.Logger).NotTo(BeNil())
+		})
+	})
+})
+
+var _ = Describe("LoggerHandler", func() {
+	var (
+		fakeRegistry *fakes.LoggerRegistry
+		handler      *httpadmin.LoggerHandler
+	)
+
+	BeforeEach(func() {
+		fakeRegistry = &fakes.LoggerRegistry{}
+		fakeRegistry.LoggerInfoReturns(flogging.LoggerInfo{Name: "gossip.comm", Level: zapcore.DebugLevel, Segment: "gossip=debug", Entries: 3})
+		handler = &httpadmin.LoggerHandler{
+			Registry: fakeRegistry,
+		}
+	})
+
+	It("responds with the logger named by the path", func() {
+		req := httptest.NewRequest("GET", "/gossip.comm", nil)
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		Expect(fakeRegistry.LoggerInfoCallCount()).To(Equal(1))
+		Expect(fakeRegistry.LoggerInfoArgsForCall(0)).To(Equal("gossip.comm"))
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Header().Get("Content-Type")).To(Equal("application/json"))
+		Expect(resp.Body).To(MatchJSON(`{"name": "gossip.comm", "level": "debug", "segment": "gossip=debug", "default": false, "entries": 3}`))
+	})
+
+	It("names the payload level", func() {
+		fakeRegistry.LoggerInfoReturns(flogging.LoggerInfo{Name: "gossip.comm", Level: flogging.PayloadLevel, Segment: "gossip=payload"})
+
+		req := httptest.NewRequest("GET", "/gossip.comm", nil)
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body).To(MatchJSON(`{"name": "gossip.comm", "level": "payload", "segment": "gossip=payload", "default": false, "entries": 0}`))
+	})
+
+	Context("when the logger name is missing", func() {
+		It("responds with an error", func() {
+			req := httptest.NewRequest("GET", "/", nil)
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(fakeRegistry.LoggerInfoCallCount()).To(Equal(0))
+			Expect(resp.Code).To(Equal(http.StatusBadRequest))
+			Expect(resp.Body).To(MatchJSON(`{"error": "missing logger name"}`))
+		})
+	})
+
+	Context("when an unsupported method is used", func() {
+		It("responds with an error", func() {
+			req := httptest.NewRequest("PUT", "/gossip", nil)
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(fakeRegistry.LoggerInfoCallCount()).To(Equal(0))
+			Expect(resp.Code).To(Equal(http.StatusBadRequest))
+			Expect(resp.Body).To(MatchJSON(`{"error": "invalid request method: PUT"}`))
+		})
+	})
+
+	Describe("NewLoggerHandler", func() {
+		It("constructs a handler for the global logger registry", func() {
+			loggerHandler := httpadmin.NewLoggerHandler()
+			Expect(loggerHandler.Registry).To(Equal(flogging.Global))
+			Expect(loggerHandler.Logger).NotTo(BeNil())
+		})
+	})
+})
@@ -0,0 +1,47 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpadmin
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/redresseur/flogging"
+)
+
+//go:generate counterfeiter -o fakes/observer_stats.go -fake-name ObserverStats . ObserverStats
+
+type ObserverStats interface {
+	ObserverStats() flogging.ObserverStats
+}
+
+// NewObserverHandler creates an ObserverHandler for the global logging
+// system. It is intended to be served at /logspec/observer.
+func NewObserverHandler() *ObserverHandler {
+	return &ObserverHandler{
+		ObserverStats: flogging.Global,
+		Logger:        flogging.MustGetLogger("flogging.httpadmin"),
+	}
+}
+
+// ObserverHandler reports the number of entries checked and written by level
+// and the observer they are passed to.
+type ObserverHandler struct {
+	ObserverStats ObserverStats
+	Logger        *flogging.FabricLogger
+}
+
+func (h *ObserverHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		err := fmt.Errorf("invalid request method: %s", req.Method)
+		sendResponse(h.Logger, resp, http.StatusBadRequest, err)
+		return
+	}
+
+	stats := h.ObserverStats.ObserverStats()
+	sendResponse(h.Logger, resp, http.StatusOK, &stats)
+}
@@ -0,0 +1,73 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpadmin_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/redresseur/flogging"
+	"github.com/redresseur/flogging/httpadmin"
+	"github.com/redresseur/flogging/httpadmin/fakes"
+)
+
+var _ = Describe("ObserverHandler", func() {
+	var (
+		fakeObserverStats *fakes.ObserverStats
+		handler           *httpadmin.ObserverHandler
+	)
+
+	BeforeEach(func() {
+		fakeObserverStats = &fakes.ObserverStats{}
+		handler = &httpadmin.ObserverHandler{
+			ObserverStats: fakeObserverStats,
+		}
+	})
+
+	It("responds with the observer stats", func() {
+		fakeObserverStats.ObserverStatsReturns(flogging.ObserverStats{
+			Observer: "*metrics.Observer",
+			Checked:  map[string]uint64{"info": 3, "warn": 1},
+			Written:  map[string]uint64{"info": 3},
+		})
+
+		req := httptest.NewRequest("GET", "/logspec/observer", nil)
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		Expect(fakeObserverStats.ObserverStatsCallCount()).To(Equal(1))
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Header().Get("Content-Type")).To(Equal("application/json"))
+		Expect(resp.Body).To(MatchJSON(`{
+			"observer": "*metrics.Observer",
+			"checked": {"info": 3, "warn": 1},
+			"written": {"info": 3}
+		}`))
+	})
+
+	Context("when an unsupported method is used", func() {
+		It("responds with an error", func() {
+			req := httptest.NewRequest("POST", "/logspec/observer", nil)
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(fakeObserverStats.ObserverStatsCallCount()).To(Equal(0))
+			Expect(resp.Code).To(Equal(http.StatusBadRequest))
+			Expect(resp.Body).To(MatchJSON(`{"error": "invalid request method: POST"}`))
+		})
+	})
+
+	Describe("NewObserverHandler", func() {
+		It("constructs a handler for the global logging system", func() {
+			observerHandler := httpadmin.NewObserverHandler()
+			Expect(observerHandler.ObserverStats).To(Equal(flogging.Global))
+			Expect(observerHandler.Logger).NotTo(BeNil())
+		})
+	})
+})
@@ -0,0 +1,124 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpadmin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/redresseur/flogging"
+)
+
+//go:generate counterfeiter -o fakes/overrider.go -fake-name Overrider . Overrider
+
+type Overrider interface {
+	OverrideSpec(spec string, ttl time.Duration) error
+	Overrides() []flogging.LevelOverride
+	ClearOverrides()
+}
+
+//go:generate counterfeiter -o fakes/override_recorder.go -fake-name OverrideRecorder . OverrideRecorder
+
+// OverrideRecorder is implemented by Overriders that record the source and
+// caller of override changes in a spec history. The changes made by an
+// OverrideHandler are recorded when its Overrider is an OverrideRecorder.
+type OverrideRecorder interface {
+	Overrider
+	OverrideSpecFrom(spec string, ttl time.Duration, source, caller string) error
+	ClearOverridesFrom(source, caller string)
+}
+
+// LogSpecOverride is the request payload used to activate a temporary
+// logging spec. The TTL is a duration such as "10m" or "1h30m".
+type LogSpecOverride struct {
+	Spec string `json:"spec"`
+	TTL  string `json:"ttl"`
+}
+
+// OverrideStatus describes an active override.
+type OverrideStatus struct {
+	Spec      string    `json:"spec"`
+	Expires   time.Time `json:"expires"`
+	Remaining string    `json:"remaining"`
+}
+
+// OverrideList is the response payload that lists the active overrides.
+type OverrideList struct {
+	Overrides []OverrideStatus `json:"overrides"`
+}
+
+// NewOverrideHandler creates an OverrideHandler for the global logging
+// system. It is intended to be served at /logspec/override.
+func NewOverrideHandler() *OverrideHandler {
+	return &OverrideHandler{
+		Overrider: flogging.Global,
+		Logger:    flogging.MustGetLogger("flogging.httpadmin"),
+	}
+}
+
+// OverrideHandler manages logging spec overrides that are removed
+// automatically when their TTL expires. POST activates an override, GET
+// lists the active overrides with their remaining TTL, and DELETE removes
+// all overrides.
+type OverrideHandler struct {
+	Overrider Overrider
+	Logger    *flogging.FabricLogger
+}
+
+func (h *OverrideHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodPost:
+		var override LogSpecOverride
+		decoder := json.NewDecoder(req.Body)
+		if err := decoder.Decode(&override); err != nil {
+			sendResponse(h.Logger, resp, http.StatusBadRequest, err)
+			return
+		}
+		req.Body.Close()
+
+		ttl, err := time.ParseDuration(override.TTL)
+		if err != nil {
+			sendResponse(h.Logger, resp, http.StatusBadRequest, fmt.Errorf("invalid ttl: %s", err))
+			return
+		}
+		if recorder, ok := h.Overrider.(OverrideRecorder); ok {
+			err = recorder.OverrideSpecFrom(override.Spec, ttl, flogging.SpecSourceHTTPAdmin, requestCaller(req))
+		} else {
+			err = h.Overrider.OverrideSpec(override.Spec, ttl)
+		}
+		if err != nil {
+			sendResponse(h.Logger, resp, http.StatusBadRequest, err)
+			return
+		}
+		resp.WriteHeader(http.StatusNoContent)
+
+	case http.MethodGet:
+		list := OverrideList{Overrides: []OverrideStatus{}}
+		for _, o := range h.Overrider.Overrides() {
+			list.Overrides = append(list.Overrides, OverrideStatus{
+				Spec:      o.Spec,
+				Expires:   o.Expires,
+				Remaining: o.Remaining().Round(time.Second).String(),
+			})
+		}
+		sendResponse(h.Logger, resp, http.StatusOK, &list)
+
+	case http.MethodDelete:
+		if recorder, ok := h.Overrider.(OverrideRecorder); ok {
+			recorder.ClearOverridesFrom(flogging.SpecSourceHTTPAdmin, requestCaller(req))
+		} else {
+			h.Overrider.ClearOverrides()
+		}
+		resp.WriteHeader(http.StatusNoContent)
+
+	default:
+		err := fmt.Errorf("invalid request method: %s", req.Method)
+		sendResponse(h.Logger, resp, http.StatusBadRequest, err)
+	}
+}
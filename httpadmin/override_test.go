@@ -0,0 +1,184 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpadmin_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/redresseur/flogging"
+	"github.com/redresseur/flogging/httpadmin"
+	"github.com/redresseur/flogging/httpadmin/fakes"
+)
+
+var _ = Describe("OverrideHandler", func() {
+	var (
+		fakeOverrider *fakes.Overrider
+		handler       *httpadmin.OverrideHandler
+	)
+
+	BeforeEach(func() {
+		fakeOverrider = &fakes.Overrider{}
+		handler = &httpadmin.OverrideHandler{
+			Overrider: fakeOverrider,
+		}
+	})
+
+	It("activates an override", func() {
+		req := httptest.NewRequest("POST", "/logspec/override", strings.NewReader(`{"spec": "gossip=debug", "ttl": "10m"}`))
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		Expect(resp.Code).To(Equal(http.StatusNoContent))
+		Expect(fakeOverrider.OverrideSpecCallCount()).To(Equal(1))
+		spec, ttl := fakeOverrider.OverrideSpecArgsForCall(0)
+		Expect(spec).To(Equal("gossip=debug"))
+		Expect(ttl).To(Equal(10 * time.Minute))
+	})
+
+	It("lists the active overrides", func() {
+		expires := time.Now().Add(time.Hour + 30*time.Second)
+		fakeOverrider.OverridesReturns([]flogging.LevelOverride{
+			{Spec: "gossip=debug", Expires: expires},
+		})
+
+		req := httptest.NewRequest("GET", "/logspec/override", nil)
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		Expect(fakeOverrider.OverridesCallCount()).To(Equal(1))
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Header().Get("Content-Type")).To(Equal("application/json"))
+
+		var list httpadmin.OverrideList
+		err := json.NewDecoder(resp.Body).Decode(&list)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(list.Overrides).To(HaveLen(1))
+		Expect(list.Overrides[0].Spec).To(Equal("gossip=debug"))
+		Expect(list.Overrides[0].Expires.Equal(expires)).To(BeTrue())
+		Expect(list.Overrides[0].Remaining).To(Equal("1h0m30s"))
+	})
+
+	It("responds with an empty list when there are no overrides", func() {
+		req := httptest.NewRequest("GET", "/logspec/override", nil)
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body).To(MatchJSON(`{"overrides": []}`))
+	})
+
+	It("clears the overrides", func() {
+		req := httptest.NewRequest("DELETE", "/logspec/override", nil)
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		Expect(resp.Code).To(Equal(http.StatusNoContent))
+		Expect(fakeOverrider.ClearOverridesCallCount()).To(Equal(1))
+	})
+
+	Context("when the payload cannot be decoded", func() {
+		It("responds with an error payload", func() {
+			req := httptest.NewRequest("POST", "/logspec/override", strings.NewReader(`goo`))
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(fakeOverrider.OverrideSpecCallCount()).To(Equal(0))
+			Expect(resp.Code).To(Equal(http.StatusBadRequest))
+			Expect(resp.Body).To(MatchJSON(`{"error": "invalid character 'g' looking for beginning of value"}`))
+		})
+	})
+
+	Context("when the ttl is invalid", func() {
+		It("responds with an error payload", func() {
+			req := httptest.NewRequest("POST", "/logspec/override", strings.NewReader(`{"spec": "debug", "ttl": "soon"}`))
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(fakeOverrider.OverrideSpecCallCount()).To(Equal(0))
+			Expect(resp.Code).To(Equal(http.StatusBadRequest))
+			Expect(resp.Body).To(MatchJSON(`{"error": "invalid ttl: time: invalid duration \"soon\""}`))
+		})
+	})
+
+	Context("when the override fails", func() {
+		BeforeEach(func() {
+			fakeOverrider.OverrideSpecReturns(errors.New("ewww; that's not right!"))
+		})
+
+		It("responds with an error payload", func() {
+			req := httptest.NewRequest("POST", "/logspec/override", strings.NewReader(`{"spec": "bogus", "ttl": "1m"}`))
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(resp.Code).To(Equal(http.StatusBadRequest))
+			Expect(resp.Body).To(MatchJSON(`{"error": "ewww; that's not right!"}`))
+		})
+	})
+
+	Context("when the overrider records the changes", func() {
+		var fakeRecorder *fakes.OverrideRecorder
+
+		BeforeEach(func() {
+			fakeRecorder = &fakes.OverrideRecorder{}
+			handler.Overrider = fakeRecorder
+		})
+
+		It("records the override with the caller", func() {
+			req := httptest.NewRequest("POST", "/logspec/override", strings.NewReader(`{"spec": "gossip=debug", "ttl": "10m"}`))
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(resp.Code).To(Equal(http.StatusNoContent))
+			Expect(fakeRecorder.OverrideSpecCallCount()).To(Equal(0))
+			Expect(fakeRecorder.OverrideSpecFromCallCount()).To(Equal(1))
+			spec, ttl, source, caller := fakeRecorder.OverrideSpecFromArgsForCall(0)
+			Expect(spec).To(Equal("gossip=debug"))
+			Expect(ttl).To(Equal(10 * time.Minute))
+			Expect(source).To(Equal(flogging.SpecSourceHTTPAdmin))
+			Expect(caller).To(Equal(req.RemoteAddr))
+		})
+
+		It("records the removal with the caller", func() {
+			req := httptest.NewRequest("DELETE", "/logspec/override", nil)
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(resp.Code).To(Equal(http.StatusNoContent))
+			Expect(fakeRecorder.ClearOverridesCallCount()).To(Equal(0))
+			Expect(fakeRecorder.ClearOverridesFromCallCount()).To(Equal(1))
+			source, caller := fakeRecorder.ClearOverridesFromArgsForCall(0)
+			Expect(source).To(Equal(flogging.SpecSourceHTTPAdmin))
+			Expect(caller).To(Equal(req.RemoteAddr))
+		})
+	})
+
+	Context("when an unsupported method is used", func() {
+		It("responds with an error", func() {
+			req := httptest.NewRequest("PUT", "/logspec/override", nil)
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(resp.Code).To(Equal(http.StatusBadRequest))
+			Expect(resp.Body).To(MatchJSON(`{"error": "invalid request method: PUT"}`))
+		})
+	})
+
+	Describe("NewOverrideHandler", func() {
+		It("constructs a handler that overrides the global spec", func() {
+			overrideHandler := httpadmin.NewOverrideHandler()
+			Expect(overrideHandler.Overrider).To(Equal(flogging.Global))
+			Expect(overrideHandler.Logger).NotTo(BeNil())
+		})
+	})
+})
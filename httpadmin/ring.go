@@ -0,0 +1,104 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpadmin
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/redresseur/flogging"
+)
+
+//go:generate counterfeiter -o fakes/ring_entries.go -fake-name RingEntries . RingEntries
+
+type RingEntries interface {
+	RingEntries(filter flogging.RingFilter) ([]flogging.RingEntry, error)
+}
+
+// RingEntryList is the response payload that lists the entries of the ring
+// buffer.
+type RingEntryList struct {
+	Entries []flogging.RingEntry `json:"entries"`
+}
+
+// NewRingHandler creates a RingHandler for the global logging system. It is
+// intended to be served at /logspec/ring.
+func NewRingHandler() *RingHandler {
+	return &RingHandler{
+		RingEntries: flogging.Global,
+		Logger:      flogging.MustGetLogger("flogging.httpadmin"),
+	}
+}
+
+// RingHandler lists the recent entries retained by the ring buffer of the
+// logging system. The optional query parameters are:
+//
+//	level     the minimum level of the entries
+//	logger    the logger name prefix of the entries
+//	since     the RFC 3339 time of the oldest entry
+//	contains  a substring of the entry messages
+//	limit     the maximum number of the most recent entries
+type RingHandler struct {
+	RingEntries RingEntries
+	Logger      *flogging.FabricLogger
+}
+
+func (h *RingHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		err := fmt.Errorf("invalid request method: %s", req.Method)
+		sendResponse(h.Logger, resp, http.StatusBadRequest, err)
+		return
+	}
+
+	filter, err := ringFilter(req)
+	if err != nil {
+		sendResponse(h.Logger, resp, http.StatusBadRequest, err)
+		return
+	}
+
+	entries, err := h.RingEntries.RingEntries(filter)
+	switch {
+	case err == flogging.ErrNoRingBuffer:
+		sendResponse(h.Logger, resp, http.StatusNotFound, err)
+	case err != nil:
+		sendResponse(h.Logger, resp, http.StatusBadRequest, err)
+	default:
+		list := RingEntryList{Entries: entries}
+		if list.Entries == nil {
+			list.Entries = []flogging.RingEntry{}
+		}
+		sendResponse(h.Logger, resp, http.StatusOK, &list)
+	}
+}
+
+func ringFilter(req *http.Request) (flogging.RingFilter, error) {
+	query := req.URL.Query()
+	filter := flogging.RingFilter{
+		Level:    query.Get("level"),
+		Logger:   query.Get("logger"),
+		Contains: query.Get("contains"),
+	}
+
+	if since := query.Get("since"); since != "" {
+		t, err := time.Parse(time.RFC3339Nano, since)
+		if err != nil {
+			return flogging.RingFilter{}, fmt.Errorf("invalid since: %s", since)
+		}
+		filter.Since = t
+	}
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 0 {
+			return flogging.RingFilter{}, fmt.Errorf("invalid limit: %s", limit)
+		}
+		filter.Limit = n
+	}
+
+	return filter, nil
+}
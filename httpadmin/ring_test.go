@@ -0,0 +1,142 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpadmin_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/redresseur/flogging"
+	"github.com/redresseur/flogging/httpadmin"
+	"github.com/redresseur/flogging/httpadmin/fakes"
+)
+
+var _ = Describe("RingHandler", func() {
+	var (
+		fakeRingEntries *fakes.RingEntries
+		handler         *httpadmin.RingHandler
+	)
+
+	BeforeEach(func() {
+		fakeRingEntries = &fakes.RingEntries{}
+		handler = &httpadmin.RingHandler{
+			RingEntries: fakeRingEntries,
+		}
+	})
+
+	It("responds with the retained entries", func() {
+		fakeRingEntries.RingEntriesReturns([]flogging.RingEntry{{
+			Time:    time.Date(2019, 10, 7, 12, 0, 0, 0, time.UTC),
+			Level:   "debug",
+			Logger:  "gossip",
+			Message: "pulled block",
+			Fields:  map[string]interface{}{"block": 7},
+		}}, nil)
+
+		req := httptest.NewRequest("GET", "/logspec/ring", nil)
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		Expect(fakeRingEntries.RingEntriesCallCount()).To(Equal(1))
+		Expect(fakeRingEntries.RingEntriesArgsForCall(0)).To(Equal(flogging.RingFilter{}))
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Header().Get("Content-Type")).To(Equal("application/json"))
+		Expect(resp.Body).To(MatchJSON(`{
+			"entries": [
+				{"time": "2019-10-07T12:00:00Z", "level": "debug", "logger": "gossip", "message": "pulled block", "fields": {"block": 7}}
+			]
+		}`))
+	})
+
+	It("passes the query to the filter", func() {
+		req := httptest.NewRequest("GET", "/logspec/ring?level=warn&logger=gossip&since=2019-10-07T12:00:00Z&contains=block&limit=20", nil)
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body).To(MatchJSON(`{"entries": []}`))
+		Expect(fakeRingEntries.RingEntriesArgsForCall(0)).To(Equal(flogging.RingFilter{
+			Level:    "warn",
+			Logger:   "gossip",
+			Since:    time.Date(2019, 10, 7, 12, 0, 0, 0, time.UTC),
+			Contains: "block",
+			Limit:    20,
+		}))
+	})
+
+	Context("when the query is invalid", func() {
+		It("responds with an error", func() {
+			for query, message := range map[string]string{
+				"?since=yesterday": "invalid since: yesterday",
+				"?limit=many":      "invalid limit: many",
+				"?limit=-1":        "invalid limit: -1",
+			} {
+				req := httptest.NewRequest("GET", "/logspec/ring"+query, nil)
+				resp := httptest.NewRecorder()
+				handler.ServeHTTP(resp, req)
+
+				Expect(resp.Code).To(Equal(http.StatusBadRequest))
+				Expect(resp.Body).To(MatchJSON(`{"error": "` + message + `"}`))
+			}
+			Expect(fakeRingEntries.RingEntriesCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the filter is rejected", func() {
+		BeforeEach(func() {
+			fakeRingEntries.RingEntriesReturns(nil, errors.New("invalid level: loud"))
+		})
+
+		It("responds with an error", func() {
+			req := httptest.NewRequest("GET", "/logspec/ring?level=loud", nil)
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(resp.Code).To(Equal(http.StatusBadRequest))
+			Expect(resp.Body).To(MatchJSON(`{"error": "invalid level: loud"}`))
+		})
+	})
+
+	Context("when there is no ring buffer", func() {
+		BeforeEach(func() {
+			fakeRingEntries.RingEntriesReturns(nil, flogging.ErrNoRingBuffer)
+		})
+
+		It("responds with not found", func() {
+			req := httptest.NewRequest("GET", "/logspec/ring", nil)
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(resp.Code).To(Equal(http.StatusNotFound))
+			Expect(resp.Body).To(MatchJSON(`{"error": "no ring buffer has been set"}`))
+		})
+	})
+
+	Context("when an unsupported method is used", func() {
+		It("responds with an error", func() {
+			req := httptest.NewRequest("DELETE", "/logspec/ring", nil)
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(fakeRingEntries.RingEntriesCallCount()).To(Equal(0))
+			Expect(resp.Code).To(Equal(http.StatusBadRequest))
+			Expect(resp.Body).To(MatchJSON(`{"error": "invalid request method: DELETE"}`))
+		})
+	})
+
+	Describe("NewRingHandler", func() {
+		It("constructs a handler for the global logging system", func() {
+			ringHandler := httpadmin.NewRingHandler()
+			Expect(ringHandler.RingEntries).To(Equal(flogging.Global))
+			Expect(ringHandler.Logger).NotTo(BeNil())
+		})
+	})
+})
@@ -0,0 +1,91 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpadmin
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/redresseur/flogging"
+)
+
+// NewRouter creates a Router that serves the logging admin API of the global
+// logging system:
+//
+//	/logspec           the active logging spec
+//	/logspec/explain   the spec segment that determines the level of a logger
+//	/logspec/override  temporary spec overrides
+//	/logspec/history   recent spec changes and rollback
+//	/logspec/loggers   the known loggers
+//	/logspec/loggers/  the level of a single logger, e.g. /logspec/loggers/gossip
+//	/logspec/format    the log record format
+//	/logspec/writer    the writer and rotation status
+//	/logspec/observer  the entries checked and written by level
+//	/logspec/stream    the entries written, as Server-Sent Events
+//	/logspec/ring      the recent entries retained by the ring buffer
+//
+// When authenticators are provided, every route is wrapped in an AuthHandler
+// and the stream and ring routes, which expose log entries, are privileged.
+// Without authenticators, the routes are served to any client and the router
+// should only be exposed to trusted networks.
+func NewRouter(authenticators ...Authenticator) *Router {
+	r := &Router{
+		mux:    http.NewServeMux(),
+		Logger: flogging.MustGetLogger("flogging.httpadmin"),
+	}
+	handle := func(pattern string, handler http.Handler, privileged bool) {
+		if len(authenticators) > 0 {
+			auth := NewAuthHandler(handler, authenticators...)
+			auth.Privileged = privileged
+			handler = auth
+		}
+		r.Handle(pattern, handler)
+	}
+	handle("/logspec", NewSpecHandler(), false)
+	handle("/logspec/explain", NewExplainHandler(), false)
+	handle("/logspec/override", NewOverrideHandler(), false)
+	handle("/logspec/history", NewHistoryHandler(), false)
+	handle("/logspec/loggers", NewLoggersHandler(), false)
+	handle("/logspec/loggers/", http.StripPrefix("/logspec/loggers/", NewLoggerHandler()), false)
+	handle("/logspec/format", NewFormatHandler(), false)
+	handle("/logspec/writer", NewWriterHandler(), false)
+	handle("/logspec/observer", NewObserverHandler(), false)
+	handle("/logspec/stream", NewStreamHandler(), true)
+	handle("/logspec/ring", NewRingHandler(), true)
+	return r
+}
+
+// Router dispatches requests to the admin handlers by path. Requests for
+// paths without a handler receive a JSON ErrorResponse.
+type Router struct {
+	mux    *http.ServeMux
+	Logger *flogging.FabricLogger
+}
+
+// Handle registers the handler for the given pattern. Patterns are
+// interpreted as by http.ServeMux and Handle panics if a handler already
+// exists for the pattern.
+func (r *Router) Handle(pattern string, handler http.Handler) {
+	if r.mux == nil {
+		r.mux = http.NewServeMux()
+	}
+	r.mux.Handle(pattern, handler)
+}
+
+func (r *Router) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	var handler http.Handler
+	var pattern string
+	if r.mux != nil {
+		handler, pattern = r.mux.Handler(req)
+	}
+	if pattern == "" {
+		err := fmt.Errorf("no handler for path: %s", req.URL.Path)
+		sendResponse(r.Logger, resp, http.StatusNotFound, err)
+		return
+	}
+	handler.ServeHTTP(resp, req)
+}
@@ -0,0 +1,153 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpadmin_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/redresseur/flogging"
+	"github.com/redresseur/flogging/httpadmin"
+	"github.com/redresseur/flogging/httpadmin/fakes"
+	"go.uber.org/zap/zapcore"
+)
+
+var _ = Describe("Router", func() {
+	var (
+		fakeRegistry *fakes.LoggerRegistry
+		router       *httpadmin.Router
+	)
+
+	BeforeEach(func() {
+		fakeRegistry = &fakes.LoggerRegistry{}
+		fakeRegistry.LoggerInfoReturns(flogging.LoggerInfo{Name: "gossip", Level: zapcore.WarnLevel, Segment: "warn", Default: true})
+
+		router = &httpadmin.Router{}
+		router.Handle("/logspec/loggers", &httpadmin.LoggersHandler{Registry: fakeRegistry})
+		router.Handle("/logspec/loggers/", http.StripPrefix("/logspec/loggers/", &httpadmin.LoggerHandler{Registry: fakeRegistry}))
+	})
+
+	It("dispatches requests by path", func() {
+		req := httptest.NewRequest("GET", "/logspec/loggers", nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		Expect(fakeRegistry.LoggersCallCount()).To(Equal(1))
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body).To(MatchJSON(`{"loggers": []}`))
+
+		req = httptest.NewRequest("GET", "/logspec/loggers/gossip", nil)
+		resp = httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		Expect(fakeRegistry.LoggerInfoCallCount()).To(Equal(1))
+		Expect(fakeRegistry.LoggerInfoArgsForCall(0)).To(Equal("gossip"))
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body).To(MatchJSON(`{"name": "gossip", "level": "warn", "segment": "warn", "default": true, "entries": 0}`))
+	})
+
+	Context("when no handler matches the path", func() {
+		It("responds with a JSON error", func() {
+			req := httptest.NewRequest("GET", "/logspec/bogus", nil)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			Expect(resp.Code).To(Equal(http.StatusNotFound))
+			Expect(resp.Header().Get("Content-Type")).To(Equal("application/json"))
+			Expect(resp.Body).To(MatchJSON(`{"error": "no handler for path: /logspec/bogus"}`))
+		})
+	})
+
+	Describe("NewRouter", func() {
+		var router *httpadmin.Router
+
+		BeforeEach(func() {
+			router = httpadmin.NewRouter()
+		})
+
+		It("serves the admin API of the global logging system", func() {
+			for _, path := range []string{
+				"/logspec",
+				"/logspec/explain?logger=gossip",
+				"/logspec/override",
+				"/logspec/history",
+				"/logspec/loggers",
+				"/logspec/loggers/gossip",
+				"/logspec/format",
+				"/logspec/writer",
+				"/logspec/observer",
+			} {
+				req := httptest.NewRequest("GET", path, nil)
+				resp := httptest.NewRecorder()
+				router.ServeHTTP(resp, req)
+
+				Expect(resp.Code).To(Equal(http.StatusOK), path)
+				Expect(resp.Header().Get("Content-Type")).To(Equal("application/json"), path)
+			}
+		})
+
+		It("responds to unknown paths with a JSON error", func() {
+			req := httptest.NewRequest("GET", "/metrics", nil)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			Expect(resp.Code).To(Equal(http.StatusNotFound))
+			Expect(resp.Body).To(MatchJSON(`{"error": "no handler for path: /metrics"}`))
+		})
+	})
+
+	Describe("NewRouter with authenticators", func() {
+		var router *httpadmin.Router
+
+		BeforeEach(func() {
+			router = httpadmin.NewRouter(&httpadmin.TokenAuthenticator{
+				Tokens: map[string]httpadmin.Principal{
+					"reader-token": {Name: "reader", Role: httpadmin.ReadOnly},
+					"writer-token": {Name: "writer", Role: httpadmin.ReadWrite},
+				},
+			})
+		})
+
+		It("requires authentication", func() {
+			req := httptest.NewRequest("GET", "/logspec", nil)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			Expect(resp.Code).To(Equal(http.StatusUnauthorized))
+		})
+
+		It("serves read-only clients", func() {
+			req := httptest.NewRequest("GET", "/logspec/loggers/gossip", nil)
+			req.Header.Set("Authorization", "Bearer reader-token")
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			Expect(resp.Code).To(Equal(http.StatusOK))
+		})
+
+		It("serves the entry routes to read-write clients only", func() {
+			for _, path := range []string{"/logspec/stream", "/logspec/ring"} {
+				req := httptest.NewRequest("GET", path, nil)
+				req.Header.Set("Authorization", "Bearer reader-token")
+				resp := httptest.NewRecorder()
+				router.ServeHTTP(resp, req)
+
+				Expect(resp.Code).To(Equal(http.StatusForbidden), path)
+			}
+
+			req := httptest.NewRequest("GET", "/logspec/ring", nil)
+			req.Header.Set("Authorization", "Bearer writer-token")
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			Expect(resp.Code).NotTo(Equal(http.StatusUnauthorized))
+			Expect(resp.Code).NotTo(Equal(http.StatusForbidden))
+		})
+	})
+})
@@ -10,6 +10,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/redresseur/flogging"
 )
@@ -21,6 +23,19 @@ type Logging interface {
 	Spec() string
 }
 
+//go:generate counterfeiter -o fakes/versioned_logging.go -fake-name VersionedLogging . VersionedLogging
+
+// VersionedLogging is implemented by logging systems that version their spec
+// and record the source and caller of spec changes in a history. Partial
+// updates and the ETag header are only supported when the Logging of a
+// SpecHandler is a VersionedLogging; otherwise, If-Match preconditions fail.
+type VersionedLogging interface {
+	Logging
+	ReplaceSpecFrom(spec, version, source, caller string) (flogging.VersionedSpec, error)
+	PatchSpecFrom(patch flogging.SpecPatch, version, source, caller string) (flogging.VersionedSpec, error)
+	VersionedSpec() flogging.VersionedSpec
+}
+
 type LogSpec struct {
 	Spec string `json:"spec,omitempty"`
 }
@@ -52,13 +67,66 @@ func (h *SpecHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
 		}
 		req.Body.Close()
 
-		if err := h.Logging.ActivateSpec(logSpec.Spec); err != nil {
+		vl, ok := h.Logging.(VersionedLogging)
+		if !ok {
+			// the spec is not versioned so no version can match
+			if ifMatch(req) != "" {
+				h.sendResponse(resp, http.StatusPreconditionFailed, flogging.ErrSpecVersionMismatch)
+				return
+			}
+			if err := h.Logging.ActivateSpec(logSpec.Spec); err != nil {
+				h.sendResponse(resp, http.StatusBadRequest, err)
+				return
+			}
+			resp.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		result, err := vl.ReplaceSpecFrom(logSpec.Spec, ifMatch(req), flogging.SpecSourceHTTPAdmin, requestCaller(req))
+		switch {
+		case err == flogging.ErrSpecVersionMismatch:
+			h.sendResponse(resp, http.StatusPreconditionFailed, err)
+		case err != nil:
+			h.sendResponse(resp, http.StatusBadRequest, err)
+		default:
+			resp.Header().Set("ETag", strconv.Quote(result.Version))
+			resp.WriteHeader(http.StatusNoContent)
+		}
+
+	case http.MethodPatch:
+		vl, ok := h.Logging.(VersionedLogging)
+		if !ok {
+			err := fmt.Errorf("invalid request method: %s", req.Method)
 			h.sendResponse(resp, http.StatusBadRequest, err)
 			return
 		}
-		resp.WriteHeader(http.StatusNoContent)
+
+		var patch flogging.SpecPatch
+		decoder := json.NewDecoder(req.Body)
+		if err := decoder.Decode(&patch); err != nil {
+			h.sendResponse(resp, http.StatusBadRequest, err)
+			return
+		}
+		req.Body.Close()
+
+		result, err := vl.PatchSpecFrom(patch, ifMatch(req), flogging.SpecSourceHTTPAdmin, requestCaller(req))
+		switch {
+		case err == flogging.ErrSpecVersionMismatch:
+			h.sendResponse(resp, http.StatusPreconditionFailed, err)
+		case err != nil:
+			h.sendResponse(resp, http.StatusBadRequest, err)
+		default:
+			resp.Header().Set("ETag", strconv.Quote(result.Version))
+			h.sendResponse(resp, http.StatusOK, &LogSpec{Spec: result.Spec})
+		}
 
 	case http.MethodGet:
+		if vl, ok := h.Logging.(VersionedLogging); ok {
+			current := vl.VersionedSpec()
+			resp.Header().Set("ETag", strconv.Quote(current.Version))
+			h.sendResponse(resp, http.StatusOK, &LogSpec{Spec: current.Spec})
+			return
+		}
 		h.sendResponse(resp, http.StatusOK, &LogSpec{Spec: h.Logging.Spec()})
 
 	default:
@@ -68,15 +136,46 @@ func (h *SpecHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
 }
 
 func (h *SpecHandler) sendResponse(resp http.ResponseWriter, code int, payload interface{}) {
+	sendResponse(h.Logger, resp, code, payload)
+}
+
+// ifMatch returns the spec version from the If-Match header of a request. An
+// empty version is returned when the header is missing or matches any
+// version.
+func ifMatch(req *http.Request) string {
+	etag := strings.TrimSpace(req.Header.Get("If-Match"))
+	if etag == "*" {
+		return ""
+	}
+	return strings.Trim(strings.TrimPrefix(etag, "W/"), `"`)
+}
+
+// requestCaller identifies the client of a request for the spec history. The
+// principal authenticated by an AuthHandler is preferred, followed by the
+// common name of the client certificate when the client has been
+// authenticated with TLS; otherwise the remote address is used.
+func requestCaller(req *http.Request) string {
+	if principal, ok := PrincipalFromContext(req.Context()); ok {
+		return principal.Name
+	}
+	if req.TLS != nil && len(req.TLS.PeerCertificates) > 0 {
+		return req.TLS.PeerCertificates[0].Subject.CommonName
+	}
+	return req.RemoteAddr
+}
+
+// sendResponse writes the payload as JSON. Errors are wrapped in an
+// ErrorResponse.
+func sendResponse(logger *flogging.FabricLogger, resp http.ResponseWriter, code int, payload interface{}) {
 	encoder := json.NewEncoder(resp)
 	if err, ok := payload.(error); ok {
 		payload = &ErrorResponse{Error: err.Error()}
 	}
 
+	resp.Header().Set("Content-Type", "application/json")
 	resp.WriteHeader(code)
 
-	resp.Header().Set("Content-Type", "application/json")
 	if err := encoder.Encode(payload); err != nil {
-		h.Logger.Errorw("failed to encode payload", "error", err)
+		logger.Errorw("failed to encode payload", "error", err)
 	}
 }
@@ -21,13 +21,14 @@ import (
 
 var _ = Describe("SpecHandler", func() {
 	var (
-		fakeLogging *fakes.Logging
+		fakeLogging *fakes.VersionedLogging
 		handler     *httpadmin.SpecHandler
 	)
 
 	BeforeEach(func() {
-		fakeLogging = &fakes.Logging{}
-		fakeLogging.SpecReturns("the-returned-specification")
+		fakeLogging = &fakes.VersionedLogging{}
+		fakeLogging.VersionedSpecReturns(flogging.VersionedSpec{Spec: "the-returned-specification", Version: "0123456789abcdef"})
+		fakeLogging.ReplaceSpecFromReturns(flogging.VersionedSpec{Spec: "updated-spec", Version: "fedcba9876543210"}, nil)
 		handler = &httpadmin.SpecHandler{
 			Logging: fakeLogging,
 		}
@@ -38,10 +39,11 @@ var _ = Describe("SpecHandler", func() {
 		resp := httptest.NewRecorder()
 		handler.ServeHTTP(resp, req)
 
-		Expect(fakeLogging.SpecCallCount()).To(Equal(1))
+		Expect(fakeLogging.VersionedSpecCallCount()).To(Equal(1))
 		Expect(resp.Code).To(Equal(http.StatusOK))
 		Expect(resp.Body).To(MatchJSON(`{"spec": "the-returned-specification"}`))
 		Expect(resp.Header().Get("Content-Type")).To(Equal("application/json"))
+		Expect(resp.Header().Get("ETag")).To(Equal(`"0123456789abcdef"`))
 	})
 
 	It("sets the current logging spec", func() {
@@ -50,8 +52,131 @@ var _ = Describe("SpecHandler", func() {
 		handler.ServeHTTP(resp, req)
 
 		Expect(resp.Code).To(Equal(http.StatusNoContent))
-		Expect(fakeLogging.ActivateSpecCallCount()).To(Equal(1))
-		Expect(fakeLogging.ActivateSpecArgsForCall(0)).To(Equal("updated-spec"))
+		Expect(resp.Header().Get("ETag")).To(Equal(`"fedcba9876543210"`))
+		Expect(fakeLogging.ReplaceSpecFromCallCount()).To(Equal(1))
+		spec, version, source, caller := fakeLogging.ReplaceSpecFromArgsForCall(0)
+		Expect(spec).To(Equal("updated-spec"))
+		Expect(version).To(BeEmpty())
+		Expect(source).To(Equal(flogging.SpecSourceHTTPAdmin))
+		Expect(caller).To(Equal(req.RemoteAddr))
+	})
+
+	It("sets the current logging spec when the precondition holds", func() {
+		req := httptest.NewRequest("PUT", "/ignored", strings.NewReader(`{"spec": "updated-spec"}`))
+		req.Header.Set("If-Match", `"0123456789abcdef"`)
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		Expect(resp.Code).To(Equal(http.StatusNoContent))
+		Expect(fakeLogging.ReplaceSpecFromCallCount()).To(Equal(1))
+		_, version, _, _ := fakeLogging.ReplaceSpecFromArgsForCall(0)
+		Expect(version).To(Equal("0123456789abcdef"))
+	})
+
+	Context("when the spec version does not match on PUT", func() {
+		BeforeEach(func() {
+			fakeLogging.ReplaceSpecFromReturns(flogging.VersionedSpec{}, flogging.ErrSpecVersionMismatch)
+		})
+
+		It("responds with precondition failed", func() {
+			req := httptest.NewRequest("PUT", "/ignored", strings.NewReader(`{"spec": "updated-spec"}`))
+			req.Header.Set("If-Match", `"0000000000000000"`)
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(resp.Code).To(Equal(http.StatusPreconditionFailed))
+			Expect(resp.Body).To(MatchJSON(`{"error": "logging spec version mismatch"}`))
+			Expect(resp.Header().Get("ETag")).To(BeEmpty())
+		})
+	})
+
+	Describe("PATCH", func() {
+		BeforeEach(func() {
+			fakeLogging.PatchSpecFromReturns(flogging.VersionedSpec{Spec: "gossip=debug:warn", Version: "fedcba9876543210"}, nil)
+		})
+
+		It("patches the current logging spec", func() {
+			req := httptest.NewRequest("PATCH", "/ignored", strings.NewReader(`{"set": {"gossip": "debug"}, "remove": ["ledger"], "default": "warn"}`))
+			req.Header.Set("If-Match", `"0123456789abcdef"`)
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(resp.Code).To(Equal(http.StatusOK))
+			Expect(resp.Header().Get("ETag")).To(Equal(`"fedcba9876543210"`))
+			Expect(resp.Body).To(MatchJSON(`{"spec": "gossip=debug:warn"}`))
+			Expect(fakeLogging.SpecCallCount()).To(Equal(0))
+			Expect(fakeLogging.VersionedSpecCallCount()).To(Equal(0))
+
+			Expect(fakeLogging.PatchSpecFromCallCount()).To(Equal(1))
+			patch, version, source, caller := fakeLogging.PatchSpecFromArgsForCall(0)
+			Expect(patch).To(Equal(flogging.SpecPatch{
+				Set:     map[string]string{"gossip": "debug"},
+				Remove:  []string{"ledger"},
+				Default: "warn",
+			}))
+			Expect(version).To(Equal("0123456789abcdef"))
+			Expect(source).To(Equal(flogging.SpecSourceHTTPAdmin))
+			Expect(caller).To(Equal(req.RemoteAddr))
+		})
+
+		It("patches unconditionally without a precondition", func() {
+			for _, ifMatch := range []string{"", "*"} {
+				req := httptest.NewRequest("PATCH", "/ignored", strings.NewReader(`{"default": "warn"}`))
+				req.Header.Set("If-Match", ifMatch)
+				resp := httptest.NewRecorder()
+				handler.ServeHTTP(resp, req)
+
+				Expect(resp.Code).To(Equal(http.StatusOK))
+			}
+
+			Expect(fakeLogging.PatchSpecFromCallCount()).To(Equal(2))
+			for i := 0; i < 2; i++ {
+				_, version, _, _ := fakeLogging.PatchSpecFromArgsForCall(i)
+				Expect(version).To(BeEmpty())
+			}
+		})
+
+		Context("when the spec version does not match", func() {
+			BeforeEach(func() {
+				fakeLogging.PatchSpecFromReturns(flogging.VersionedSpec{}, flogging.ErrSpecVersionMismatch)
+			})
+
+			It("responds with precondition failed", func() {
+				req := httptest.NewRequest("PATCH", "/ignored", strings.NewReader(`{"default": "warn"}`))
+				req.Header.Set("If-Match", `"0000000000000000"`)
+				resp := httptest.NewRecorder()
+				handler.ServeHTTP(resp, req)
+
+				Expect(resp.Code).To(Equal(http.StatusPreconditionFailed))
+				Expect(resp.Body).To(MatchJSON(`{"error": "logging spec version mismatch"}`))
+			})
+		})
+
+		Context("when the patch is invalid", func() {
+			BeforeEach(func() {
+				fakeLogging.PatchSpecFromReturns(flogging.VersionedSpec{}, errors.New("ewww; that's not right!"))
+			})
+
+			It("responds with an error payload", func() {
+				req := httptest.NewRequest("PATCH", "/ignored", strings.NewReader(`{"default": "bogus"}`))
+				resp := httptest.NewRecorder()
+				handler.ServeHTTP(resp, req)
+
+				Expect(resp.Code).To(Equal(http.StatusBadRequest))
+				Expect(resp.Body).To(MatchJSON(`{"error": "ewww; that's not right!"}`))
+			})
+		})
+
+		Context("when the patch payload cannot be decoded", func() {
+			It("responds with an error payload", func() {
+				req := httptest.NewRequest("PATCH", "/ignored", strings.NewReader(`goo`))
+				resp := httptest.NewRecorder()
+				handler.ServeHTTP(resp, req)
+
+				Expect(fakeLogging.PatchSpecFromCallCount()).To(Equal(0))
+				Expect(resp.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
 	})
 
 	Context("when the update spec payload cannot be decoded", func() {
@@ -60,7 +185,7 @@ var _ = Describe("SpecHandler", func() {
 			resp := httptest.NewRecorder()
 			handler.ServeHTTP(resp, req)
 
-			Expect(fakeLogging.ActivateSpecCallCount()).To(Equal(0))
+			Expect(fakeLogging.ReplaceSpecFromCallCount()).To(Equal(0))
 			Expect(resp.Code).To(Equal(http.StatusBadRequest))
 			Expect(resp.Body).To(MatchJSON(`{"error": "invalid character 'g' looking for beginning of value"}`))
 		})
@@ -68,7 +193,7 @@ var _ = Describe("SpecHandler", func() {
 
 	Context("when activating the spec fails", func() {
 		BeforeEach(func() {
-			fakeLogging.ActivateSpecReturns(errors.New("ewww; that's not right!"))
+			fakeLogging.ReplaceSpecFromReturns(flogging.VersionedSpec{}, errors.New("ewww; that's not right!"))
 		})
 
 		It("responds with an error payload", func() {
@@ -96,8 +221,57 @@ var _ = Describe("SpecHandler", func() {
 			resp := httptest.NewRecorder()
 			handler.ServeHTTP(resp, req)
 
-			Expect(fakeLogging.ActivateSpecCallCount()).To(Equal(0))
-			Expect(fakeLogging.SpecCallCount()).To(Equal(0))
+			Expect(fakeLogging.ReplaceSpecFromCallCount()).To(Equal(0))
+			Expect(fakeLogging.VersionedSpecCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the logging system is not versioned", func() {
+		var plainLogging *fakes.Logging
+
+		BeforeEach(func() {
+			plainLogging = &fakes.Logging{}
+			plainLogging.SpecReturns("the-returned-specification")
+			handler.Logging = plainLogging
+		})
+
+		It("responds with the current logging spec without an ETag", func() {
+			req := httptest.NewRequest("GET", "/ignored", nil)
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(resp.Code).To(Equal(http.StatusOK))
+			Expect(resp.Body).To(MatchJSON(`{"spec": "the-returned-specification"}`))
+			Expect(resp.Header().Get("ETag")).To(BeEmpty())
+		})
+
+		It("activates the spec", func() {
+			req := httptest.NewRequest("PUT", "/ignored", strings.NewReader(`{"spec": "updated-spec"}`))
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(resp.Code).To(Equal(http.StatusNoContent))
+			Expect(plainLogging.ActivateSpecCallCount()).To(Equal(1))
+			Expect(plainLogging.ActivateSpecArgsForCall(0)).To(Equal("updated-spec"))
+		})
+
+		It("rejects preconditions", func() {
+			req := httptest.NewRequest("PUT", "/ignored", strings.NewReader(`{"spec": "updated-spec"}`))
+			req.Header.Set("If-Match", `"0123456789abcdef"`)
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(resp.Code).To(Equal(http.StatusPreconditionFailed))
+			Expect(plainLogging.ActivateSpecCallCount()).To(Equal(0))
+		})
+
+		It("rejects patches", func() {
+			req := httptest.NewRequest("PATCH", "/ignored", strings.NewReader(`{"default": "warn"}`))
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(resp.Code).To(Equal(http.StatusBadRequest))
+			Expect(resp.Body).To(MatchJSON(`{"error": "invalid request method: PATCH"}`))
 		})
 	})
 
@@ -0,0 +1,188 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpadmin
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/redresseur/flogging"
+	"github.com/redresseur/flogging/fabenc"
+	"go.uber.org/zap/zapcore"
+)
+
+//go:generate counterfeiter -o fakes/entry_streamer.go -fake-name EntryStreamer . EntryStreamer
+
+type EntryStreamer interface {
+	SubscribeEntries(fn func(zapcore.Entry, []zapcore.Field)) (cancel func())
+}
+
+// defaultStreamBuffer is the number of encoded entries buffered for a client
+// before entries are dropped.
+const defaultStreamBuffer = 256
+
+// NewStreamHandler creates a StreamHandler for the global logging system.
+// It is intended to be served at /logspec/stream.
+func NewStreamHandler() *StreamHandler {
+	return &StreamHandler{
+		Streamer: flogging.Global,
+		Logger:   flogging.MustGetLogger("flogging.httpadmin"),
+	}
+}
+
+// StreamHandler streams the entries written by the logging system to the
+// client as Server-Sent Events. Every entry is sent as the data of a message
+// event. The optional query parameters are:
+//
+//	level   the minimum level of the streamed entries
+//	logger  the logger name prefix of the streamed entries
+//	format  "json" (the default) or "console"
+//
+// Only the entries enabled by the active logging spec can be streamed. When
+// the client does not keep up, entries are dropped and a dropped event with
+// the number of dropped entries is sent before the next entry.
+type StreamHandler struct {
+	Streamer EntryStreamer
+	Logger   *flogging.FabricLogger
+	// Buffer is the number of entries buffered for a client. It defaults to
+	// 256.
+	Buffer int
+}
+
+func (h *StreamHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		err := fmt.Errorf("invalid request method: %s", req.Method)
+		sendResponse(h.Logger, resp, http.StatusBadRequest, err)
+		return
+	}
+
+	filter, err := newStreamFilter(req)
+	if err != nil {
+		sendResponse(h.Logger, resp, http.StatusBadRequest, err)
+		return
+	}
+	flusher, ok := resp.(http.Flusher)
+	if !ok {
+		sendResponse(h.Logger, resp, http.StatusInternalServerError, errors.New("streaming is not supported"))
+		return
+	}
+
+	size := h.Buffer
+	if size <= 0 {
+		size = defaultStreamBuffer
+	}
+	entries := make(chan []byte, size)
+	var dropped uint64
+
+	cancel := h.Streamer.SubscribeEntries(func(e zapcore.Entry, fields []zapcore.Field) {
+		if !filter.matches(e) {
+			return
+		}
+		buf, err := filter.encoder.EncodeEntry(e, fields)
+		if err != nil {
+			return
+		}
+		line := append([]byte(nil), bytes.TrimRight(buf.Bytes(), "\n")...)
+		buf.Free()
+
+		select {
+		case entries <- line:
+		default:
+			atomic.AddUint64(&dropped, 1)
+		}
+	})
+	defer cancel()
+
+	resp.Header().Set("Content-Type", "text/event-stream")
+	resp.Header().Set("Cache-Control", "no-cache")
+	resp.Header().Set("Connection", "keep-alive")
+	resp.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-req.Context().Done():
+			return
+		case line := <-entries:
+			if n := atomic.SwapUint64(&dropped, 0); n > 0 {
+				fmt.Fprintf(resp, "event: dropped\ndata: %d\n\n", n)
+			}
+			if err := writeEvent(resp, line); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// writeEvent writes an encoded entry as the data of a message event. Every
+// line of the entry is sent as a data field.
+func writeEvent(resp http.ResponseWriter, line []byte) error {
+	var event bytes.Buffer
+	for _, l := range bytes.Split(line, []byte("\n")) {
+		event.WriteString("data: ")
+		event.Write(l)
+		event.WriteByte('\n')
+	}
+	event.WriteByte('\n')
+
+	_, err := resp.Write(event.Bytes())
+	return err
+}
+
+// streamFilter selects and encodes the entries of a stream.
+type streamFilter struct {
+	level   zapcore.Level
+	prefix  string
+	encoder zapcore.Encoder
+}
+
+func newStreamFilter(req *http.Request) (*streamFilter, error) {
+	query := req.URL.Query()
+	filter := &streamFilter{
+		level:  flogging.PayloadLevel,
+		prefix: query.Get("logger"),
+	}
+
+	if level := query.Get("level"); level != "" {
+		if !flogging.IsValidLevel(level) {
+			return nil, fmt.Errorf("invalid level: %s", level)
+		}
+		filter.level = flogging.NameToLevel(level)
+	}
+
+	switch format := query.Get("format"); format {
+	case "", "json":
+		filter.encoder = zapcore.NewJSONEncoder(flogging.NewDefaultEncoderConfig())
+	case "console":
+		formatters, err := fabenc.ParseFormat(flogging.DefaultFormat)
+		if err != nil {
+			return nil, err
+		}
+		filter.encoder = fabenc.NewFormatEncoder(fabenc.DisableColor(formatters)...)
+	default:
+		return nil, fmt.Errorf("invalid format: %s", format)
+	}
+
+	return filter, nil
+}
+
+// matches returns true when the entry has at least the minimum level and is
+// written by the logger named by the prefix or one of its descendants.
+func (f *streamFilter) matches(e zapcore.Entry) bool {
+	if e.Level < f.level {
+		return false
+	}
+	if f.prefix == "" || e.LoggerName == f.prefix {
+		return true
+	}
+	return strings.HasPrefix(e.LoggerName, f.prefix+".")
+}
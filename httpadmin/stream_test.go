@@ -0,0 +1,173 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpadmin_test
+
+import (
+	"bufio"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/redresseur/flogging"
+	"github.com/redresseur/flogging/httpadmin"
+	"github.com/redresseur/flogging/httpadmin/fakes"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+var _ = Describe("StreamHandler", func() {
+	var (
+		fakeStreamer *fakes.EntryStreamer
+		subscribed   chan func(zapcore.Entry, []zapcore.Field)
+		cancelled    chan struct{}
+		handler      *httpadmin.StreamHandler
+		server       *httptest.Server
+	)
+
+	BeforeEach(func() {
+		subscribed = make(chan func(zapcore.Entry, []zapcore.Field), 1)
+		cancelled = make(chan struct{})
+		fakeStreamer = &fakes.EntryStreamer{}
+		fakeStreamer.SubscribeEntriesStub = func(fn func(zapcore.Entry, []zapcore.Field)) func() {
+			subscribed <- fn
+			return func() { close(cancelled) }
+		}
+		handler = &httpadmin.StreamHandler{
+			Streamer: fakeStreamer,
+		}
+		server = httptest.NewServer(handler)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	entry := func(logger string, level zapcore.Level, message string) zapcore.Entry {
+		return zapcore.Entry{
+			LoggerName: logger,
+			Level:      level,
+			Message:    message,
+			Time:       time.Date(2019, 10, 7, 12, 0, 0, 0, time.UTC),
+		}
+	}
+
+	readEvent := func(reader *bufio.Reader) string {
+		var event string
+		for {
+			line, err := reader.ReadString('\n')
+			Expect(err).NotTo(HaveOccurred())
+			if line == "\n" {
+				return event
+			}
+			event += line
+		}
+	}
+
+	It("streams the matching entries as JSON events", func() {
+		resp, err := http.Get(server.URL + "?level=info&logger=gossip")
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(resp.Header.Get("Content-Type")).To(Equal("text/event-stream"))
+		Expect(resp.Header.Get("Cache-Control")).To(Equal("no-cache"))
+
+		var publish func(zapcore.Entry, []zapcore.Field)
+		Eventually(subscribed).Should(Receive(&publish))
+		publish(entry("gossip", zapcore.DebugLevel, "too low"), nil)
+		publish(entry("gossiper", zapcore.InfoLevel, "other logger"), nil)
+		publish(entry("gossip.comm", zapcore.WarnLevel, "warning"), []zapcore.Field{zap.Int("peers", 3)})
+
+		event := readEvent(bufio.NewReader(resp.Body))
+		Expect(event).To(HavePrefix("data: "))
+		Expect(strings.TrimPrefix(event, "data: ")).To(MatchJSON(
+			`{"level": "warn", "ts": 1570449600, "name": "gossip.comm", "msg": "warning", "peers": 3}`,
+		))
+
+		resp.Body.Close()
+		Eventually(cancelled).Should(BeClosed())
+	})
+
+	It("streams entries as console text", func() {
+		resp, err := http.Get(server.URL + "?format=console")
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+
+		var publish func(zapcore.Entry, []zapcore.Field)
+		Eventually(subscribed).Should(Receive(&publish))
+		publish(entry("ledger", zapcore.InfoLevel, "committed"), []zapcore.Field{zap.Uint64("block", 7)})
+
+		event := readEvent(bufio.NewReader(resp.Body))
+		Expect(event).To(MatchRegexp(`^data: 2019-10-07 12:00:00.000 UTC \[ledger\] \(unknown\) -> INFO [0-9a-f]{3} committed block=7\n$`))
+	})
+
+	Context("when the client does not keep up", func() {
+		BeforeEach(func() {
+			handler.Buffer = 1
+			fakeStreamer.SubscribeEntriesStub = func(fn func(zapcore.Entry, []zapcore.Field)) func() {
+				for _, message := range []string{"kept", "dropped", "dropped"} {
+					fn(entry("ledger", zapcore.InfoLevel, message), nil)
+				}
+				return func() {}
+			}
+		})
+
+		It("reports the dropped entries", func() {
+			resp, err := http.Get(server.URL)
+			Expect(err).NotTo(HaveOccurred())
+			defer resp.Body.Close()
+
+			reader := bufio.NewReader(resp.Body)
+			Expect(readEvent(reader)).To(Equal("event: dropped\ndata: 2\n"))
+			Expect(readEvent(reader)).To(ContainSubstring(`"msg":"kept"`))
+		})
+	})
+
+	Context("when the query is invalid", func() {
+		It("responds with an error", func() {
+			for query, message := range map[string]string{
+				"?level=loud":   "invalid level: loud",
+				"?format=xml":   "invalid format: xml",
+				"?format=json2": "invalid format: json2",
+			} {
+				resp, err := http.Get(server.URL + query)
+				Expect(err).NotTo(HaveOccurred())
+				body, err := ioutil.ReadAll(resp.Body)
+				resp.Body.Close()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+				Expect(body).To(MatchJSON(`{"error": "` + message + `"}`))
+			}
+			Expect(fakeStreamer.SubscribeEntriesCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when an unsupported method is used", func() {
+		It("responds with an error", func() {
+			req := httptest.NewRequest("POST", "/logspec/stream", nil)
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(fakeStreamer.SubscribeEntriesCallCount()).To(Equal(0))
+			Expect(resp.Code).To(Equal(http.StatusBadRequest))
+			Expect(resp.Body).To(MatchJSON(`{"error": "invalid request method: POST"}`))
+		})
+	})
+
+	Describe("NewStreamHandler", func() {
+		It("constructs a handler for the global logging system", func() {
+			streamHandler := httpadmin.NewStreamHandler()
+			Expect(streamHandler.Streamer).To(Equal(flogging.Global))
+			Expect(streamHandler.Logger).NotTo(BeNil())
+		})
+	})
+})
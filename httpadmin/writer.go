@@ -0,0 +1,47 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpadmin
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/redresseur/flogging"
+)
+
+//go:generate counterfeiter -o fakes/writer_status.go -fake-name WriterStatus . WriterStatus
+
+type WriterStatus interface {
+	WriterStatus() flogging.WriterStatus
+}
+
+// NewWriterHandler creates a WriterHandler for the global logging system.
+// It is intended to be served at /logspec/writer.
+func NewWriterHandler() *WriterHandler {
+	return &WriterHandler{
+		WriterStatus: flogging.Global,
+		Logger:       flogging.MustGetLogger("flogging.httpadmin"),
+	}
+}
+
+// WriterHandler reports the writer log records are written to and, for
+// rotating file writers, the file that is currently written.
+type WriterHandler struct {
+	WriterStatus WriterStatus
+	Logger       *flogging.FabricLogger
+}
+
+func (h *WriterHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		err := fmt.Errorf("invalid request method: %s", req.Method)
+		sendResponse(h.Logger, resp, http.StatusBadRequest, err)
+		return
+	}
+
+	status := h.WriterStatus.WriterStatus()
+	sendResponse(h.Logger, resp, http.StatusOK, &status)
+}
@@ -0,0 +1,105 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpadmin_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/redresseur/flogging"
+	"github.com/redresseur/flogging/httpadmin"
+	"github.com/redresseur/flogging/httpadmin/fakes"
+	"github.com/redresseur/flogging/output"
+)
+
+var _ = Describe("WriterHandler", func() {
+	var (
+		fakeWriterStatus *fakes.WriterStatus
+		handler          *httpadmin.WriterHandler
+	)
+
+	BeforeEach(func() {
+		fakeWriterStatus = &fakes.WriterStatus{}
+		handler = &httpadmin.WriterHandler{
+			WriterStatus: fakeWriterStatus,
+		}
+	})
+
+	It("responds with the writer status", func() {
+		fakeWriterStatus.WriterStatusReturns(flogging.WriterStatus{Type: "*os.File", Name: "/dev/stderr", Color: true})
+
+		req := httptest.NewRequest("GET", "/logspec/writer", nil)
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		Expect(fakeWriterStatus.WriterStatusCallCount()).To(Equal(1))
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Header().Get("Content-Type")).To(Equal("application/json"))
+		Expect(resp.Body).To(MatchJSON(`{"type": "*os.File", "name": "/dev/stderr", "color": true}`))
+	})
+
+	It("includes the rotation status of rotating writers", func() {
+		fakeWriterStatus.WriterStatusReturns(flogging.WriterStatus{
+			Type: "*output.fileWriter",
+			Rotation: &output.Status{
+				Dir:          "/var/log/peer",
+				Prefix:       "peer",
+				Model:        output.SizeModel,
+				MaxSize:      1024,
+				MaxFileCount: 3,
+				Path:         "/var/log/peer/peer2019-10-07_0002.log",
+				Size:         512,
+				Date:         time.Date(2019, 10, 7, 0, 0, 0, 0, time.UTC),
+				Index:        2,
+			},
+		})
+
+		req := httptest.NewRequest("GET", "/logspec/writer", nil)
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body).To(MatchJSON(`{
+			"type": "*output.fileWriter",
+			"color": false,
+			"rotation": {
+				"dir": "/var/log/peer",
+				"prefix": "peer",
+				"model": "size",
+				"max_size": 1024,
+				"max_file_count": 3,
+				"path": "/var/log/peer/peer2019-10-07_0002.log",
+				"size": 512,
+				"date": "2019-10-07T00:00:00Z",
+				"index": 2
+			}
+		}`))
+	})
+
+	Context("when an unsupported method is used", func() {
+		It("responds with an error", func() {
+			req := httptest.NewRequest("PUT", "/logspec/writer", nil)
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(fakeWriterStatus.WriterStatusCallCount()).To(Equal(0))
+			Expect(resp.Code).To(Equal(http.StatusBadRequest))
+			Expect(resp.Body).To(MatchJSON(`{"error": "invalid request method: PUT"}`))
+		})
+	})
+
+	Describe("NewWriterHandler", func() {
+		It("constructs a handler for the global logging system", func() {
+			writerHandler := httpadmin.NewWriterHandler()
+			Expect(writerHandler.WriterStatus).To(Equal(flogging.Global))
+			Expect(writerHandler.Logger).NotTo(BeNil())
+		})
+	})
+})
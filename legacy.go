@@ -27,7 +27,7 @@ import (
 // SetFormat sets the logging format.
 func SetFormat(formatSpec string) logging.Formatter {
 	if formatSpec == "" {
-		formatSpec = defaultFormat
+		formatSpec = DefaultFormat
 	}
 	return logging.MustStringFormatter(formatSpec)
 }
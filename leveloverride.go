@@ -0,0 +1,174 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package flogging
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// A LevelOverride is a logging spec that temporarily takes precedence over
+// the spec activated with ActivateSpec.
+type LevelOverride struct {
+	// Spec is the logging spec of the override.
+	Spec string `json:"spec"`
+	// Expires is the time the override is removed.
+	Expires time.Time `json:"expires"`
+}
+
+// Remaining returns the time left before the override expires.
+func (o LevelOverride) Remaining() time.Duration {
+	if remaining := time.Until(o.Expires); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+type levelOverride struct {
+	spec    string
+	expires time.Time
+	timer   *time.Timer
+}
+
+// OverrideSpec activates a logging spec on top of the active spec for the
+// provided duration. The segments of the override take precedence over the
+// segments of the active spec and of earlier overrides as if they were
+// appended to them. When the duration elapses, the override is removed and
+// the levels it replaced are restored.
+func (l *LoggerLevels) OverrideSpec(spec string, ttl time.Duration) error {
+	change, err := l.overrideSpec(spec, ttl, func(o *levelOverride) {
+		// the error is reported by Logging; the overrides that cannot be
+		// combined have been removed
+		if change, ok, _ := l.removeOverride(o); ok {
+			l.notify(change)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	l.notify(change)
+	return nil
+}
+
+// overrideSpec activates an override without notifying the subscribers. The
+// expire function is called when the duration of the override elapses.
+func (l *LoggerLevels) overrideSpec(spec string, ttl time.Duration, expire func(*levelOverride)) (LevelChange, error) {
+	if spec == "" {
+		return LevelChange{}, errors.New("invalid override: empty logging specification")
+	}
+	if ttl <= 0 {
+		return LevelChange{}, errors.Errorf("invalid override duration: %s", ttl)
+	}
+	if _, err := parseSpec(spec); err != nil {
+		return LevelChange{}, err
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	o := &levelOverride{spec: spec, expires: time.Now().Add(ttl)}
+	overrides := append(l.overrides[:len(l.overrides):len(l.overrides)], o)
+	ls, err := effectiveSpec(l.spec, overrides)
+	if err != nil {
+		return LevelChange{}, err
+	}
+	o.timer = time.AfterFunc(ttl, func() { expire(o) })
+	l.overrides = overrides
+	return l.apply(ls), nil
+}
+
+// Overrides returns the overrides that are in effect in the order they were
+// activated.
+func (l *LoggerLevels) Overrides() []LevelOverride {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	overrides := make([]LevelOverride, 0, len(l.overrides))
+	for _, o := range l.overrides {
+		overrides = append(overrides, LevelOverride{Spec: o.spec, Expires: o.expires})
+	}
+	return overrides
+}
+
+// ClearOverrides removes all overrides and restores the spec activated with
+// ActivateSpec.
+func (l *LoggerLevels) ClearOverrides() {
+	if change, ok := l.clearOverrides(); ok {
+		l.notify(change)
+	}
+}
+
+// clearOverrides removes all overrides without notifying the subscribers. It
+// returns false when there were no overrides.
+func (l *LoggerLevels) clearOverrides() (LevelChange, bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if len(l.overrides) == 0 {
+		return LevelChange{}, false
+	}
+	for _, o := range l.overrides {
+		o.timer.Stop()
+	}
+	l.overrides = nil
+	return l.apply(l.baseLevelSpec()), true
+}
+
+// removeOverride removes an override once its duration has elapsed without
+// notifying the subscribers. It returns false when the override has already
+// been removed.
+//
+// If the remaining overrides cannot be combined with the active spec, they
+// are removed as well and the error is returned with the change.
+func (l *LoggerLevels) removeOverride(o *levelOverride) (LevelChange, bool, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for i := range l.overrides {
+		if l.overrides[i] != o {
+			continue
+		}
+
+		overrides := append(l.overrides[:i:i], l.overrides[i+1:]...)
+		if len(overrides) == 0 {
+			l.overrides = nil
+			return l.apply(l.baseLevelSpec()), true, nil
+		}
+		ls, err := effectiveSpec(l.spec, overrides)
+		if err != nil {
+			for _, remaining := range overrides {
+				remaining.timer.Stop()
+			}
+			l.overrides = nil
+			return l.apply(l.baseLevelSpec()), true, err
+		}
+		l.overrides = overrides
+		return l.apply(ls), true, nil
+	}
+	return LevelChange{}, false, nil
+}
+
+// baseLevelSpec returns the parsed spec activated with ActivateSpec. The
+// caller must hold the mutex.
+func (l *LoggerLevels) baseLevelSpec() *levelSpec {
+	if l.base == nil {
+		return &levelSpec{}
+	}
+	return l.base
+}
+
+// effectiveSpec parses a spec followed by overrides.
+func effectiveSpec(spec string, overrides []*levelOverride) (*levelSpec, error) {
+	segments := []string{spec}
+	for _, o := range overrides {
+		segments = append(segments, o.spec)
+	}
+	return parseSpec(strings.Join(segments, ":"))
+}
@@ -0,0 +1,98 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package flogging_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/redresseur/flogging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLoggerLevelsOverrideSpec(t *testing.T) {
+	ll := &flogging.LoggerLevels{}
+	err := ll.ActivateSpec("gossip=warn:info")
+	require.NoError(t, err)
+
+	err = ll.OverrideSpec("gossip.comm=info", time.Hour)
+	require.NoError(t, err)
+	err = ll.OverrideSpec("ledger=error:gossip=debug", 50*time.Millisecond)
+	require.NoError(t, err)
+
+	assert.Equal(t, zapcore.InfoLevel, ll.Level("gossip.comm"))
+	assert.Equal(t, zapcore.DebugLevel, ll.Level("gossip.state"))
+	assert.Equal(t, zapcore.ErrorLevel, ll.Level("ledger"))
+	assert.True(t, ll.Enabled(zapcore.DebugLevel))
+	assert.Equal(t, "gossip.comm=info:gossip=debug:ledger=error:info", ll.Spec())
+
+	overrides := ll.Overrides()
+	require.Len(t, overrides, 2)
+	assert.Equal(t, "gossip.comm=info", overrides[0].Spec)
+	assert.InDelta(t, time.Hour, overrides[0].Remaining(), float64(time.Minute))
+	assert.Equal(t, "ledger=error:gossip=debug", overrides[1].Spec)
+
+	assert.Eventually(t, func() bool { return len(ll.Overrides()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, zapcore.InfoLevel, ll.Level("gossip.comm"))
+	assert.Equal(t, zapcore.WarnLevel, ll.Level("gossip.state"))
+	assert.Equal(t, zapcore.InfoLevel, ll.Level("ledger"))
+	assert.False(t, ll.Enabled(zapcore.DebugLevel))
+	assert.Equal(t, "gossip.comm=info:gossip=warn:info", ll.Spec())
+
+	err = ll.ActivateSpec("gossip=error:fatal")
+	require.NoError(t, err)
+	assert.Equal(t, zapcore.InfoLevel, ll.Level("gossip.comm"))
+	assert.Equal(t, "gossip.comm=info:gossip=error:fatal", ll.Spec())
+
+	ll.ClearOverrides()
+	assert.Empty(t, ll.Overrides())
+	assert.Equal(t, zapcore.ErrorLevel, ll.Level("gossip.comm"))
+	assert.Equal(t, "gossip=error:fatal", ll.Spec())
+}
+
+func TestLoggerLevelsOverrideSpecDefault(t *testing.T) {
+	ll := &flogging.LoggerLevels{}
+	err := ll.ActivateSpec("gossip=warn:error")
+	require.NoError(t, err)
+
+	err = ll.OverrideSpec("debug", 50*time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, zapcore.DebugLevel, ll.DefaultLevel())
+	assert.Equal(t, zapcore.WarnLevel, ll.Level("gossip"))
+
+	assert.Eventually(t, func() bool { return ll.DefaultLevel() == zapcore.ErrorLevel }, 5*time.Second, 10*time.Millisecond)
+	assert.Empty(t, ll.Overrides())
+	assert.Equal(t, "gossip=warn:error", ll.Spec())
+}
+
+func TestLoggerLevelsOverrideSpecErrors(t *testing.T) {
+	var tests = []struct {
+		spec string
+		ttl  time.Duration
+		err  string
+	}{
+		{spec: "", ttl: time.Minute, err: "invalid override: empty logging specification"},
+		{spec: "a=debug", ttl: 0, err: "invalid override duration: 0s"},
+		{spec: "a=debug", ttl: -time.Second, err: "invalid override duration: -1s"},
+		{spec: "a=bogus", ttl: time.Minute, err: "invalid logging specification 'a=bogus': bad segment 'a=bogus'"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.spec, func(t *testing.T) {
+			ll := &flogging.LoggerLevels{}
+			err := ll.ActivateSpec("a=warn")
+			require.NoError(t, err)
+
+			err = ll.OverrideSpec(tc.spec, tc.ttl)
+			assert.EqualError(t, err, tc.err)
+			assert.Empty(t, ll.Overrides())
+			assert.Equal(t, "a=warn:info", ll.Spec())
+		})
+	}
+}
@@ -62,6 +62,32 @@ func nameToLevel(level string) (zapcore.Level, error) {
 	}
 }
 
+// LevelName returns the name of a level that is accepted by NameToLevel.
+// Unlike zapcore.Level.String, PAYLOAD is named "payload".
+func LevelName(level zapcore.Level) string {
+	if level == PayloadLevel {
+		return "payload"
+	}
+	return level.String()
+}
+
+// textLevel encodes a level as text with the name returned by LevelName so
+// that PAYLOAD is encoded as "payload" instead of "Level(-2)".
+type textLevel zapcore.Level
+
+func (l textLevel) MarshalText() ([]byte, error) {
+	return []byte(LevelName(zapcore.Level(l))), nil
+}
+
+func (l *textLevel) UnmarshalText(text []byte) error {
+	level, err := nameToLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*l = textLevel(level)
+	return nil
+}
+
 func IsValidLevel(level string) bool {
 	_, err := nameToLevel(level)
 	return err == nil
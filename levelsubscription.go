@@ -0,0 +1,85 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package flogging
+
+// LevelChange describes a change of the active logging spec.
+type LevelChange struct {
+	// OldSpec is the normalized spec before the change.
+	OldSpec string `json:"old_spec"`
+	// NewSpec is the normalized spec after the change.
+	NewSpec string `json:"new_spec"`
+	// Loggers are the names of the known loggers whose effective level
+	// changed, in sorted order. Loggers are known once they have been
+	// created by a Logging instance or their level has been checked; at
+	// most 4096 loggers are known. Loggers are only reported to
+	// subscriptions that were active when the spec changed.
+	Loggers []string `json:"loggers"`
+}
+
+type levelSubscription struct {
+	fn func(LevelChange)
+}
+
+// Subscribe registers a callback that is invoked after the active spec has
+// been changed by ActivateSpec, PatchSpec, or an override. The callback is
+// invoked by the goroutine that changed the spec once the change has been
+// applied; callbacks for concurrent changes may be invoked concurrently.
+// No locks are held while callbacks run, so a callback may read the spec
+// history or change the spec itself.
+//
+// The returned function cancels the subscription.
+func (l *LoggerLevels) Subscribe(fn func(LevelChange)) (cancel func()) {
+	sub := &levelSubscription{fn: fn}
+
+	l.mutex.Lock()
+	l.subscriptions = append(l.subscriptions, sub)
+	l.mutex.Unlock()
+
+	return func() { l.unsubscribe(sub) }
+}
+
+// Notify relays spec changes to a channel. Like signal.Notify, sends to the
+// channel do not block: the caller must ensure that the channel has enough
+// buffer space to keep up with the expected rate of changes or changes will
+// be dropped.
+//
+// The returned function stops the relay.
+func (l *LoggerLevels) Notify(ch chan<- LevelChange) (cancel func()) {
+	return l.Subscribe(func(change LevelChange) {
+		select {
+		case ch <- change:
+		default:
+		}
+	})
+}
+
+func (l *LoggerLevels) unsubscribe(sub *levelSubscription) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for i := range l.subscriptions {
+		if l.subscriptions[i] == sub {
+			l.subscriptions = append(l.subscriptions[:i:i], l.subscriptions[i+1:]...)
+			break
+		}
+	}
+	if len(l.subscriptions) == 0 {
+		l.subscriptions = nil
+	}
+}
+
+// notify invokes the subscribed callbacks with a change. It must be called
+// without holding the mutex.
+func (l *LoggerLevels) notify(change LevelChange) {
+	l.mutex.RLock()
+	subscriptions := l.subscriptions
+	l.mutex.RUnlock()
+
+	for _, sub := range subscriptions {
+		sub.fn(change)
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package flogging_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/redresseur/flogging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerLevelsSubscribe(t *testing.T) {
+	ll := &flogging.LoggerLevels{}
+	err := ll.ActivateSpec("gossip=debug:info")
+	require.NoError(t, err)
+	ll.Level("gossip.comm")
+	ll.Level("ledger")
+	ll.Level("orderer")
+
+	var changes []flogging.LevelChange
+	cancel := ll.Subscribe(func(change flogging.LevelChange) {
+		changes = append(changes, change)
+	})
+
+	err = ll.ActivateSpec("gossip=debug:ledger=warn:orderer.=error:info")
+	require.NoError(t, err)
+	err = ll.ActivateSpec("bogus")
+	assert.Error(t, err)
+	_, err = ll.PatchSpec(flogging.SpecPatch{Default: "warn"}, "")
+	require.NoError(t, err)
+
+	require.Len(t, changes, 2)
+	assert.Equal(t, flogging.LevelChange{
+		OldSpec: "gossip=debug:info",
+		NewSpec: "gossip=debug:ledger=warn:orderer.=error:info",
+		Loggers: []string{"ledger", "orderer"},
+	}, changes[0])
+	assert.Equal(t, flogging.LevelChange{
+		OldSpec: "gossip=debug:ledger=warn:orderer.=error:info",
+		NewSpec: "gossip=debug:ledger=warn:orderer.=error:warn",
+	}, changes[1])
+
+	cancel()
+	err = ll.ActivateSpec("debug")
+	require.NoError(t, err)
+	assert.Len(t, changes, 2)
+}
+
+func TestLoggerLevelsNotify(t *testing.T) {
+	ll := &flogging.LoggerLevels{}
+	err := ll.ActivateSpec("info")
+	require.NoError(t, err)
+	ll.Level("gossip")
+
+	ch := make(chan flogging.LevelChange, 1)
+	cancel := ll.Notify(ch)
+	defer cancel()
+
+	err = ll.OverrideSpec("gossip=debug", 50*time.Millisecond)
+	require.NoError(t, err)
+	change := <-ch
+	assert.Equal(t, flogging.LevelChange{OldSpec: "info", NewSpec: "gossip=debug:info", Loggers: []string{"gossip"}}, change)
+
+	select {
+	case change = <-ch:
+	case <-time.After(5 * time.Second):
+		t.Fatal("override did not expire")
+	}
+	assert.Equal(t, flogging.LevelChange{OldSpec: "gossip=debug:info", NewSpec: "info", Loggers: []string{"gossip"}}, change)
+
+	// sends do not block when the channel is full
+	for _, spec := range []string{"debug", "warn", "error"} {
+		err = ll.ActivateSpec(spec)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, "debug", (<-ch).NewSpec)
+}
+
+func TestLoggingSubscribeCreatedLoggers(t *testing.T) {
+	logging, err := flogging.New(flogging.Config{Writer: &bytes.Buffer{}})
+	require.NoError(t, err)
+	logging.Logger("gossip")
+	logging.Logger("ledger")
+
+	var changes []flogging.LevelChange
+	cancel := logging.Subscribe(func(change flogging.LevelChange) {
+		changes = append(changes, change)
+	})
+	defer cancel()
+
+	err = logging.ActivateSpec("gossip=debug")
+	require.NoError(t, err)
+	err = logging.ActivateSpec("gossip=debug:ledger=error")
+	require.NoError(t, err)
+
+	require.Len(t, changes, 2)
+	assert.Equal(t, []string{"gossip"}, changes[0].Loggers)
+	assert.Equal(t, []string{"ledger"}, changes[1].Loggers)
+}
@@ -0,0 +1,110 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package flogging
+
+import (
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// loggerCore is the core of the loggers created by a Logging instance. Entries
+// are written by the primary Core. While a flight recorder, a ring buffer, or
+// an entry subscription is active, entries are also offered to the capture
+// cores; otherwise only the primary Core is consulted.
+//
+// The capture cores can accept entries that the active spec disables, so
+// Enabled reports their levels as well. writeEnabled reports whether entries
+// at a level are written by the primary Core.
+type loggerCore struct {
+	name    string
+	logging *Logging
+	primary zapcore.Core
+
+	recorder *recorderCore
+	stream   *streamCore
+	ring     *ringCore
+}
+
+func newLoggerCore(name string, logging *Logging, primary zapcore.Core) *loggerCore {
+	return &loggerCore{
+		name:     name,
+		logging:  logging,
+		primary:  primary,
+		recorder: &recorderCore{logging: logging},
+		stream:   &streamCore{logging: logging},
+		ring:     &ringCore{logging: logging},
+	}
+}
+
+// named returns a copy of the core for a child logger. The child is added to
+// the registry and counts its entries separately.
+func (c *loggerCore) named(name string) *loggerCore {
+	clone := *c
+	if c.name == "" {
+		clone.name = name
+	} else {
+		clone.name = c.name + "." + name
+	}
+	if core, ok := c.primary.(*Core); ok {
+		clone.primary = core.counting(c.logging.registerLogger(clone.name))
+	}
+	return &clone
+}
+
+// writeEnabled returns true when the entries of the logger at a level are
+// written by the primary Core.
+func (c *loggerCore) writeEnabled(level zapcore.Level) bool {
+	return c.primary.Enabled(level) && c.logging.LoggerLevels.Level(c.name).Enabled(level)
+}
+
+func (c *loggerCore) Enabled(level zapcore.Level) bool {
+	if c.primary.Enabled(level) {
+		return true
+	}
+	return c.logging.capturing() && (c.recorder.Enabled(level) || c.ring.Enabled(level))
+}
+
+func (c *loggerCore) With(fields []zapcore.Field) zapcore.Core {
+	return &loggerCore{
+		name:     c.name,
+		logging:  c.logging,
+		primary:  c.primary.With(fields),
+		recorder: c.recorder.With(fields).(*recorderCore),
+		stream:   c.stream.With(fields).(*streamCore),
+		ring:     c.ring.With(fields).(*ringCore),
+	}
+}
+
+func (c *loggerCore) Check(e zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.logging.capturing() {
+		return c.primary.Check(e, ce)
+	}
+
+	// the recorder is checked first so that flushed entries are written
+	// ahead of the entry that triggered the flush
+	ce = c.recorder.Check(e, ce)
+	ce = c.primary.Check(e, ce)
+	ce = c.stream.Check(e, ce)
+	return c.ring.Check(e, ce)
+}
+
+// Write writes an entry with the primary Core. The capture cores only
+// receive the entries that have been checked.
+func (c *loggerCore) Write(e zapcore.Entry, fields []zapcore.Field) error {
+	return c.primary.Write(e, fields)
+}
+
+func (c *loggerCore) Sync() error {
+	return c.primary.Sync()
+}
+
+// capturing returns true while a flight recorder, a ring buffer, or an entry
+// subscription is active.
+func (s *Logging) capturing() bool {
+	return atomic.LoadInt32(&s.streaming) > 0 || s.RingBuffer() != nil || s.FlightRecorder() != nil
+}
@@ -7,92 +7,363 @@ SPDX-License-Identifier: Apache-2.0
 package flogging
 
 import (
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/pkg/errors"
 	"go.uber.org/zap/zapcore"
 )
 
 // LoggerLevels tracks the logging level of named loggers.
+//
+// Level lookups read a snapshot of the active spec and its level cache
+// without locking. Changes to the spec create a new snapshot with an empty
+// cache that atomically replaces the previous one; the mutex serializes them.
+// The levels of loggers are added to the cache of the snapshot they were
+// calculated from as they are looked up. Both the cache and the set of
+// logger names reported in a LevelChange hold at most maxCachedLoggers
+// names.
 type LoggerLevels struct {
-	mutex        sync.RWMutex
-	levelCache   map[string]zapcore.Level
-	specs        map[string]zapcore.Level
+	mutex         sync.RWMutex
+	snapshot      atomic.Value // *levelSnapshot
+	spec          string
+	base          *levelSpec // the parsed spec
+	overrides     []*levelOverride
+	subscriptions []*levelSubscription
+}
+
+// maxCachedLoggers bounds the number of loggers in a level cache and in the
+// set of known logger names.
+const maxCachedLoggers = 4096
+
+// levelSnapshot is an immutable view of the active spec. The cache maps
+// logger names to the levels calculated from the spec; names are the
+// loggers known since the level cache was reset.
+type levelSnapshot struct {
+	levelSpec
+	minLevel zapcore.Level
+	size     int32     // the number of cached levels
+	cache    *sync.Map // map[string]zapcore.Level
+	names    *loggerNames
+}
+
+// emptySnapshot is used before a spec has been activated. Levels are not
+// cached in it.
+var emptySnapshot = &levelSnapshot{}
+
+// loggerNames is a bounded set of logger names.
+type loggerNames struct {
+	count int32
+	names sync.Map // map[string]struct{}
+}
+
+// add adds a name to the set unless the set is full.
+func (n *loggerNames) add(name string) {
+	if _, ok := n.names.Load(name); ok || atomic.LoadInt32(&n.count) >= maxCachedLoggers {
+		return
+	}
+	if _, loaded := n.names.LoadOrStore(name, struct{}{}); !loaded {
+		atomic.AddInt32(&n.count, 1)
+	}
+}
+
+// cacheLevel adds the level of a logger to the cache unless the cache is
+// full.
+func (s *levelSnapshot) cacheLevel(name string, level zapcore.Level) {
+	if s.cache == nil || atomic.LoadInt32(&s.size) >= maxCachedLoggers {
+		return
+	}
+	if _, loaded := s.cache.LoadOrStore(name, level); !loaded {
+		atomic.AddInt32(&s.size, 1)
+	}
+	s.names.add(name)
+}
+
+// levelSpec is the parsed form of a logging spec.
+type levelSpec struct {
 	defaultLevel zapcore.Level
-	minLevel     zapcore.Level
+	specs        map[string]zapcore.Level
+	excludes     map[string][]string // negated loggers of the names in specs
+	patterns     []levelPattern
 }
 
 // DefaultLevel returns the default logging level for loggers that do not have
 // an explicit level set.
 func (l *LoggerLevels) DefaultLevel() zapcore.Level {
-	l.mutex.RLock()
-	lvl := l.defaultLevel
-	l.mutex.RUnlock()
-	return lvl
+	return l.load().defaultLevel
+}
+
+// load returns the current snapshot.
+func (l *LoggerLevels) load() *levelSnapshot {
+	if snapshot, ok := l.snapshot.Load().(*levelSnapshot); ok {
+		return snapshot
+	}
+	return emptySnapshot
 }
 
 // ActivateSpec is used to modify logging levels.
 //
 // The logging specification has the following form:
 //   [<logger>[,<logger>...]=]<level>[:[<logger>[,<logger>...]=]<level>...]
+//
+// A logger is a name, a glob, or a regular expression. A name selects the
+// named logger and its descendants; when it ends with a period, only the
+// named logger is selected. A glob is a name where components may be '*',
+// matching a single component, or '**', matching any number of components.
+// A regular expression is prefixed with "re:" and selects the loggers whose
+// complete name matches it. As the expression extends to the '=' that
+// precedes the level, it must be the last logger of its segment.
+//
+// A logger name prefixed with '!' is negated: the named logger and its
+// descendants, or only the named logger when the name ends with a period,
+// are excluded from the other loggers of the segment. The level of an
+// excluded logger is determined as if the segment did not select it. For
+// example, "gossip,!gossip.comm=debug" sets the gossip loggers to DEBUG
+// except for gossip.comm and its descendants. A segment must have a logger
+// that is not negated, and globs and regular expressions cannot be negated.
+//
+// The level of a logger is determined by the exact logger name followed by
+// the logger name and the names of its parents, from the most to the least
+// specific. For each of these, a name in the spec takes precedence over
+// globs and, for the complete logger name, globs take precedence over
+// regular expressions. When several globs or regular expressions match, the
+// one that appears last in the spec wins.
+//
+// Overrides activated with OverrideSpec remain in effect on top of the new
+// spec until they expire.
 func (l *LoggerLevels) ActivateSpec(spec string) error {
+	_, change, err := l.activateSpec(spec, "")
+	if err != nil {
+		return err
+	}
+
+	l.notify(change)
+	return nil
+}
+
+// activateSpec activates a logging spec without notifying the subscribers.
+// When version is not empty, the spec is only activated if it matches the
+// version of the active spec. The change is returned so that the caller can
+// notify them once it has released its own locks.
+func (l *LoggerLevels) activateSpec(spec, version string) (VersionedSpec, LevelChange, error) {
+	ls, err := parseSpec(spec)
+	if err != nil {
+		return VersionedSpec{}, LevelChange{}, err
+	}
+
 	l.mutex.Lock()
 	defer l.mutex.Unlock()
 
+	if version != "" && version != l.specVersion() {
+		return VersionedSpec{}, LevelChange{}, ErrSpecVersionMismatch
+	}
+	base := ls
+	if len(l.overrides) > 0 {
+		if ls, err = effectiveSpec(spec, l.overrides); err != nil {
+			return VersionedSpec{}, LevelChange{}, err
+		}
+	}
+	l.spec, l.base = spec, base
+	change := l.apply(ls)
+	return VersionedSpec{Spec: change.NewSpec, Version: l.specVersion()}, change, nil
+}
+
+// parseSpec parses and validates a logging spec.
+func parseSpec(spec string) (*levelSpec, error) {
 	defaultLevel := zapcore.InfoLevel
 	specs := map[string]zapcore.Level{}
-	for _, field := range strings.Split(spec, ":") {
+	excludes := map[string][]string{}
+	var patterns []levelPattern
+	addLogger := func(logger string, level zapcore.Level, negated []string) error {
+		if isGlob(logger) {
+			pattern, ok := newGlobPattern(logger, level)
+			if !ok {
+				return errors.Errorf("invalid logging specification '%s': bad logger name '%s'", spec, logger)
+			}
+			pattern.excludes = negated
+			patterns = append(patterns, pattern)
+			return nil
+		}
+
+		// check if the logger name in the spec is valid. The
+		// trailing period is trimmed as logger names in specs
+		// ending with a period signifies that this part of the
+		// spec refers to the exact logger name (i.e. is not a prefix)
+		if !isValidLoggerName(strings.TrimSuffix(logger, ".")) {
+			return errors.Errorf("invalid logging specification '%s': bad logger name '%s'", spec, logger)
+		}
+		specs[logger] = level
+		if len(negated) > 0 {
+			excludes[logger] = negated
+		} else {
+			delete(excludes, logger)
+		}
+		return nil
+	}
+
+	for _, field := range splitSpec(spec) {
+		if idx := regexpSelectorIndex(field); idx >= 0 {
+			// [<logger>,...,]re:<regexp>=<level>
+			eq := strings.LastIndex(field, "=")
+			if eq < idx || !IsValidLevel(field[eq+1:]) {
+				return nil, errors.Errorf("invalid logging specification '%s': bad segment '%s'", spec, field)
+			}
+			level := NameToLevel(field[eq+1:])
+			var loggers, negated []string
+			if idx > 0 {
+				var err error
+				loggers, negated, err = splitNegated(spec, strings.Split(field[:idx-1], ","))
+				if err != nil {
+					return nil, err
+				}
+			}
+			for _, logger := range loggers {
+				if err := addLogger(logger, level, negated); err != nil {
+					return nil, err
+				}
+			}
+			pattern, err := newRegexpPattern(field[idx:eq], level)
+			if err != nil {
+				return nil, errors.Errorf("invalid logging specification '%s': bad regular expression '%s'", spec, field[idx:eq])
+			}
+			pattern.excludes = negated
+			patterns = append(patterns, pattern)
+			continue
+		}
+
 		split := strings.Split(field, "=")
 		switch len(split) {
 		case 1: // level
 			if field != "" && !IsValidLevel(field) {
-				return errors.Errorf("invalid logging specification '%s': bad segment '%s'", spec, field)
+				return nil, errors.Errorf("invalid logging specification '%s': bad segment '%s'", spec, field)
 			}
 			defaultLevel = NameToLevel(field)
 
 		case 2: // <logger>[,<logger>...]=<level>
 			if split[0] == "" {
-				return errors.Errorf("invalid logging specification '%s': no logger specified in segment '%s'", spec, field)
+				return nil, errors.Errorf("invalid logging specification '%s': no logger specified in segment '%s'", spec, field)
 			}
 			if field != "" && !IsValidLevel(split[1]) {
-				return errors.Errorf("invalid logging specification '%s': bad segment '%s'", spec, field)
+				return nil, errors.Errorf("invalid logging specification '%s': bad segment '%s'", spec, field)
 			}
 
 			level := NameToLevel(split[1])
-			loggers := strings.Split(split[0], ",")
+			loggers, negated, err := splitNegated(spec, strings.Split(split[0], ","))
+			if err != nil {
+				return nil, err
+			}
+			if len(loggers) == 0 {
+				return nil, errors.Errorf("invalid logging specification '%s': only negated loggers in segment '%s'", spec, field)
+			}
 			for _, logger := range loggers {
-				// check if the logger name in the spec is valid. The
-				// trailing period is trimmed as logger names in specs
-				// ending with a period signifies that this part of the
-				// spec refers to the exact logger name (i.e. is not a prefix)
-				if !isValidLoggerName(strings.TrimSuffix(logger, ".")) {
-					return errors.Errorf("invalid logging specification '%s': bad logger name '%s'", spec, logger)
+				if err := addLogger(logger, level, negated); err != nil {
+					return nil, err
 				}
-				specs[logger] = level
 			}
 
 		default:
-			return errors.Errorf("invalid logging specification '%s': bad segment '%s'", spec, field)
+			return nil, errors.Errorf("invalid logging specification '%s': bad segment '%s'", spec, field)
 		}
 	}
 
-	minLevel := defaultLevel
-	for _, lvl := range specs {
+	return &levelSpec{
+		defaultLevel: defaultLevel,
+		specs:        specs,
+		excludes:     excludes,
+		patterns:     patterns,
+	}, nil
+}
+
+// splitNegated separates the negated loggers of a segment from the other
+// loggers. The names of the negated loggers are returned sorted and without
+// the '!' prefix.
+func splitNegated(spec string, loggers []string) (selected, negated []string, err error) {
+	for _, logger := range loggers {
+		if !strings.HasPrefix(logger, "!") {
+			selected = append(selected, logger)
+			continue
+		}
+		name := strings.TrimPrefix(logger, "!")
+		if isGlob(name) || !isValidLoggerName(strings.TrimSuffix(name, ".")) {
+			return nil, nil, errors.Errorf("invalid logging specification '%s': bad negated logger name '%s'", spec, logger)
+		}
+		negated = append(negated, name)
+	}
+
+	sort.Strings(negated)
+	for i := len(negated) - 1; i > 0; i-- {
+		if negated[i] == negated[i-1] {
+			negated = append(negated[:i], negated[i+1:]...)
+		}
+	}
+	return selected, negated, nil
+}
+
+// excludedBy returns the negated logger that excludes a logger or an empty
+// string when the logger is not excluded.
+func excludedBy(loggerName string, negated []string) string {
+	for _, name := range negated {
+		if strings.HasSuffix(name, ".") {
+			if loggerName+"." == name {
+				return "!" + name
+			}
+			continue
+		}
+		if loggerName == name || strings.HasPrefix(loggerName, name+".") {
+			return "!" + name
+		}
+	}
+	return ""
+}
+
+// apply makes a parsed spec the active spec and describes the change for
+// subscribers. The level cache of the new spec starts empty. When there are
+// subscribers, the levels of the known loggers are calculated from both
+// specs to determine which loggers are affected. The caller must hold the
+// mutex.
+func (l *LoggerLevels) apply(ls *levelSpec) LevelChange {
+	current := l.load()
+	change := LevelChange{OldSpec: current.String(), NewSpec: ls.String()}
+
+	minLevel := ls.defaultLevel
+	for _, lvl := range ls.specs {
 		if lvl < minLevel {
 			minLevel = lvl
 		}
 	}
+	for _, p := range ls.patterns {
+		if p.level < minLevel {
+			minLevel = p.level
+		}
+	}
 
-	l.minLevel = minLevel
-	l.defaultLevel = defaultLevel
-	l.specs = specs
-	l.levelCache = map[string]zapcore.Level{}
+	names := current.names
+	if names == nil {
+		names = &loggerNames{}
+	}
+	if len(l.subscriptions) > 0 {
+		names.names.Range(func(name, _ interface{}) bool {
+			if ls.calculateLevel(name.(string)) != current.calculateLevel(name.(string)) {
+				change.Loggers = append(change.Loggers, name.(string))
+			}
+			return true
+		})
+		sort.Strings(change.Loggers)
+	}
 
-	return nil
+	l.snapshot.Store(&levelSnapshot{
+		levelSpec: *ls,
+		minLevel:  minLevel,
+		cache:     &sync.Map{},
+		names:     names,
+	})
+
+	return change
 }
 
 // logggerNameRegexp defines the valid logger names
@@ -110,56 +381,264 @@ func isValidLoggerName(loggerName string) bool {
 // been explicitly set for the logger, the default logging level will be
 // returned.
 func (l *LoggerLevels) Level(loggerName string) zapcore.Level {
-	if level, ok := l.cachedLevel(loggerName); ok {
-		return level
+	snapshot := l.load()
+	if snapshot.cache != nil {
+		if level, ok := snapshot.cache.Load(loggerName); ok {
+			return level.(zapcore.Level)
+		}
 	}
 
-	l.mutex.Lock()
-	level := l.calculateLevel(loggerName)
-	l.levelCache[loggerName] = level
-	l.mutex.Unlock()
-
+	level := snapshot.calculateLevel(loggerName)
+	snapshot.cacheLevel(loggerName, level)
+	if l.load() != snapshot {
+		// the spec changed while the level was calculated
+		return l.Level(loggerName)
+	}
 	return level
 }
 
 // calculateLevel walks the logger name back to find the appropriate
-// log level from the current spec.
-func (l *LoggerLevels) calculateLevel(loggerName string) zapcore.Level {
-	candidate := loggerName + "."
-	for {
-		if lvl, ok := l.specs[candidate]; ok {
-			return lvl
+// log level from the spec.
+func (ls *levelSpec) calculateLevel(loggerName string) zapcore.Level {
+	level := ls.defaultLevel
+	ls.walkCandidates(loggerName, func(c LevelCandidate) bool {
+		if c.Level == nil || c.Excluded != "" {
+			return true
+		}
+		level = *c.Level
+		return false
+	})
+	return level
+}
+
+// walkCandidates calls fn with the candidates for the level of a logger in
+// precedence order until fn returns false. Every logger name candidate is
+// provided while globs and regular expressions are only provided when they
+// match.
+func (ls *levelSpec) walkCandidates(loggerName string, fn func(LevelCandidate) bool) {
+	for _, name := range levelCandidates(loggerName) {
+		exact := strings.HasSuffix(name, ".")
+		candidate := LevelCandidate{Name: name, Exact: exact}
+		if lvl, ok := ls.specs[name]; ok {
+			candidate.Level = &lvl
+			candidate.Excluded = excludedBy(loggerName, ls.excludes[name])
+		}
+		if !fn(candidate) {
+			return
 		}
 
+		for _, glob := range []bool{true, false} {
+			if !glob && name != loggerName {
+				continue
+			}
+			// patterns that appear later in the spec take precedence
+			for i := len(ls.patterns) - 1; i >= 0; i-- {
+				p := ls.patterns[i]
+				if p.glob != glob || !p.regexp.MatchString(name) {
+					continue
+				}
+				lvl := p.level
+				candidate := LevelCandidate{Name: name, Exact: exact, Selector: p.selector, Level: &lvl}
+				candidate.Excluded = excludedBy(loggerName, p.excludes)
+				if !fn(candidate) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// levelCandidates returns the logger names from the spec that can determine
+// the level of a logger in the order they are considered. The first
+// candidate is the exact match for the logger followed by the logger and
+// each of its parents.
+func levelCandidates(loggerName string) []string {
+	candidate := loggerName + "."
+	candidates := []string{candidate}
+	for {
 		idx := strings.LastIndex(candidate, ".")
 		if idx <= 0 {
-			return l.defaultLevel
+			return candidates
 		}
 		candidate = candidate[:idx]
+		candidates = append(candidates, candidate)
+	}
+}
+
+// LevelCandidate is a logger name from the spec that is considered when
+// determining the level of a logger.
+type LevelCandidate struct {
+	// Name is the logger name as it appears in a spec segment.
+	Name string `json:"name"`
+	// Exact is true when the candidate only matches the logger itself.
+	Exact bool `json:"exact"`
+	// Selector is the glob or regular expression from the spec that matched
+	// the name. It is empty when the name appears in the spec as is.
+	Selector string `json:"selector,omitempty"`
+	// Level is the level assigned to the candidate by the active spec or
+	// nil when the spec does not refer to the candidate.
+	Level *zapcore.Level `json:"level,omitempty"`
+	// Excluded is the negated logger of the segment that excludes the logger
+	// from the candidate. An excluded candidate does not determine the level.
+	Excluded string `json:"excluded,omitempty"`
+	// Matched is true for the candidate that determined the level.
+	Matched bool `json:"matched"`
+}
+
+// MarshalJSON encodes the level of the candidate with its flogging name.
+func (c LevelCandidate) MarshalJSON() ([]byte, error) {
+	type candidate LevelCandidate
+	return json.Marshal(struct {
+		candidate
+		Level *textLevel `json:"level,omitempty"`
+	}{candidate(c), (*textLevel)(c.Level)})
+}
+
+// UnmarshalJSON decodes a candidate encoded by MarshalJSON.
+func (c *LevelCandidate) UnmarshalJSON(b []byte) error {
+	type candidate LevelCandidate
+	aux := struct {
+		*candidate
+		Level *textLevel `json:"level,omitempty"`
+	}{candidate: (*candidate)(c)}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+	c.Level = (*zapcore.Level)(aux.Level)
+	return nil
+}
+
+// LevelExplanation describes how the level of a logger was determined from
+// the active spec.
+type LevelExplanation struct {
+	// Logger is the name of the logger.
+	Logger string `json:"logger"`
+	// Level is the effective level of the logger.
+	Level zapcore.Level `json:"level"`
+	// Segment is the normalized spec segment that determined the level.
+	Segment string `json:"segment"`
+	// Default is true when no segment refers to the logger and the default
+	// level applies.
+	Default bool `json:"default"`
+	// Candidates are the logger names, globs, and regular expressions
+	// considered, in precedence order.
+	Candidates []LevelCandidate `json:"candidates"`
+}
+
+// MarshalJSON encodes the level of the explanation and of its candidates with
+// their flogging names.
+func (e LevelExplanation) MarshalJSON() ([]byte, error) {
+	type explanation LevelExplanation
+	return json.Marshal(struct {
+		explanation
+		Level textLevel `json:"level"`
+	}{explanation(e), textLevel(e.Level)})
+}
+
+// UnmarshalJSON decodes an explanation encoded by MarshalJSON.
+func (e *LevelExplanation) UnmarshalJSON(b []byte) error {
+	type explanation LevelExplanation
+	aux := struct {
+		*explanation
+		Level textLevel `json:"level"`
+	}{explanation: (*explanation)(e)}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+	e.Level = zapcore.Level(aux.Level)
+	return nil
+}
+
+// Explain reports which segment of the active spec determines the level of a
+// logger and the chain of candidates that was considered.
+func (l *LoggerLevels) Explain(loggerName string) LevelExplanation {
+	snapshot := l.load()
+
+	explanation := LevelExplanation{
+		Logger:  loggerName,
+		Level:   snapshot.defaultLevel,
+		Segment: LevelName(snapshot.defaultLevel),
+		Default: true,
 	}
+	snapshot.walkCandidates(loggerName, func(candidate LevelCandidate) bool {
+		if candidate.Level != nil && candidate.Excluded == "" && explanation.Default {
+			candidate.Matched = true
+			explanation.Level = *candidate.Level
+			explanation.Segment = snapshot.segment(candidate)
+			explanation.Default = false
+		}
+		explanation.Candidates = append(explanation.Candidates, candidate)
+		return true
+	})
+	return explanation
 }
 
-// cachedLevel attempts to retrieve the effective log level for a logger from the
-// cache. If the logger is not found, ok will be false.
-func (l *LoggerLevels) cachedLevel(loggerName string) (lvl zapcore.Level, ok bool) {
-	l.mutex.RLock()
-	level, ok := l.levelCache[loggerName]
-	l.mutex.RUnlock()
-	return level, ok
+// segment returns the normalized spec segment of a candidate.
+func (ls *levelSpec) segment(candidate LevelCandidate) string {
+	if candidate.Selector == "" {
+		return segmentString(candidate.Name, ls.excludes[candidate.Name], *candidate.Level)
+	}
+	for _, p := range ls.patterns {
+		if p.selector == candidate.Selector {
+			return segmentString(p.selector, p.excludes, p.level)
+		}
+	}
+	return segmentString(candidate.Selector, nil, *candidate.Level)
 }
 
-// Spec returns a normalized version of the active logging spec.
+// segmentString returns the normalized form of a spec segment. Negated
+// loggers follow names and globs and precede regular expressions, which
+// must be the last logger of a segment.
+func segmentString(selector string, negated []string, level zapcore.Level) string {
+	if len(negated) == 0 {
+		return fmt.Sprintf("%s=%s", selector, LevelName(level))
+	}
+	loggers := make([]string, 0, len(negated)+1)
+	for _, name := range negated {
+		loggers = append(loggers, "!"+name)
+	}
+	if strings.HasPrefix(selector, regexpPrefix) {
+		loggers = append(loggers, selector)
+	} else {
+		loggers = append([]string{selector}, loggers...)
+	}
+	return fmt.Sprintf("%s=%s", strings.Join(loggers, ","), LevelName(level))
+}
+
+// resetCache forgets the levels and the names of the loggers in the level
+// cache.
+func (l *LoggerLevels) resetCache() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	current := l.load()
+	l.snapshot.Store(&levelSnapshot{
+		levelSpec: current.levelSpec,
+		minLevel:  current.minLevel,
+		cache:     &sync.Map{},
+		names:     &loggerNames{},
+	})
+}
+
+// Spec returns a normalized version of the active logging spec. The segments
+// of overrides activated with OverrideSpec are included.
 func (l *LoggerLevels) Spec() string {
-	l.mutex.RLock()
-	defer l.mutex.RUnlock()
+	return l.load().String()
+}
 
+// String returns the normalized form of a parsed spec.
+func (ls *levelSpec) String() string {
 	var fields []string
-	for k, v := range l.specs {
-		fields = append(fields, fmt.Sprintf("%s=%s", k, v))
+	for k, v := range ls.specs {
+		fields = append(fields, segmentString(k, ls.excludes[k], v))
 	}
 
 	sort.Strings(fields)
-	fields = append(fields, l.defaultLevel.String())
+	// the order of globs and regular expressions determines their precedence
+	for _, p := range ls.patterns {
+		fields = append(fields, segmentString(p.selector, p.excludes, p.level))
+	}
+	fields = append(fields, LevelName(ls.defaultLevel))
 
 	return strings.Join(fields, ":")
 }
@@ -167,8 +646,5 @@ func (l *LoggerLevels) Spec() string {
 // Enabled function is an enabled check that evaluates the minimum active logging level.
 // It serves as a fast check before the (relatively) expensive Check call in the core.
 func (l *LoggerLevels) Enabled(lvl zapcore.Level) bool {
-	l.mutex.RLock()
-	enabled := l.minLevel.Enabled(lvl)
-	l.mutex.RUnlock()
-	return enabled
+	return l.load().minLevel.Enabled(lvl)
 }
@@ -0,0 +1,38 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package flogging
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLoggerLevelsCacheBound(t *testing.T) {
+	ll := &LoggerLevels{}
+	err := ll.ActivateSpec("gossip=debug:info")
+	require.NoError(t, err)
+
+	for i := 0; i < maxCachedLoggers+10; i++ {
+		assert.Equal(t, zapcore.DebugLevel, ll.Level(fmt.Sprintf("gossip.peer%d", i)))
+	}
+	snapshot := ll.load()
+	assert.Equal(t, int32(maxCachedLoggers), snapshot.size)
+	assert.Equal(t, int32(maxCachedLoggers), snapshot.names.count)
+	assert.Equal(t, zapcore.DebugLevel, ll.Level(fmt.Sprintf("gossip.peer%d", maxCachedLoggers+5)))
+
+	err = ll.ActivateSpec("gossip=warn:info")
+	require.NoError(t, err)
+	snapshot = ll.load()
+	assert.Zero(t, snapshot.size)
+	assert.Equal(t, int32(maxCachedLoggers), snapshot.names.count)
+	assert.Equal(t, zapcore.WarnLevel, ll.Level("gossip.peer0"))
+	assert.Equal(t, int32(1), snapshot.size)
+}
@@ -7,12 +7,19 @@ SPDX-License-Identifier: Apache-2.0
 package flogging_test
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io/ioutil"
+	"runtime"
 	"strconv"
+	"sync"
 	"testing"
 
 	"github.com/redresseur/flogging"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
@@ -80,6 +87,94 @@ func TestLoggerLevelsActivateSpec(t *testing.T) {
 			},
 			expectedDefaultLevel: zapcore.DebugLevel,
 		},
+		{
+			spec: "*.gossip=debug:peer.**.state=warn:info",
+			expectedLevels: map[string]zapcore.Level{
+				"peer.gossip":              zapcore.DebugLevel,
+				"orderer.gossip.comm":      zapcore.DebugLevel,
+				"gossip":                   zapcore.InfoLevel,
+				"peer.chain.gossip":        zapcore.InfoLevel,
+				"peer.state":               zapcore.WarnLevel,
+				"peer.gossip.state":        zapcore.WarnLevel,
+				"peer.gossip.privdata":     zapcore.DebugLevel,
+				"peer.a.b.state.transfer":  zapcore.WarnLevel,
+				"orderer.gossip.state.foo": zapcore.DebugLevel,
+			},
+			expectedDefaultLevel: zapcore.InfoLevel,
+		},
+		{
+			spec: "peer=error:peer.*.=warn:peer.**=info:**.comm=debug:fatal",
+			expectedLevels: map[string]zapcore.Level{
+				"peer":           zapcore.ErrorLevel,
+				"peer.gossip":    zapcore.WarnLevel,
+				"peer.gossip.a":  zapcore.InfoLevel,
+				"peer.comm":      zapcore.WarnLevel,
+				"peer.comm.grpc": zapcore.InfoLevel,
+				"orderer.comm":   zapcore.DebugLevel,
+				"orderer":        zapcore.FatalLevel,
+			},
+			expectedDefaultLevel: zapcore.FatalLevel,
+		},
+		{
+			spec: `re:^peer\.(ledger|gossip)$=warn:peer=debug:re:^peer\..*:ledger$=error:info`,
+			expectedLevels: map[string]zapcore.Level{
+				"peer.ledger":        zapcore.WarnLevel,
+				"peer.gossip":        zapcore.WarnLevel,
+				"peer.gossip.state":  zapcore.DebugLevel,
+				"peer.kv:ledger":     zapcore.ErrorLevel,
+				"peer.ledger:ledger": zapcore.ErrorLevel,
+				"peer":               zapcore.DebugLevel,
+				"orderer.ledger":     zapcore.InfoLevel,
+			},
+			expectedDefaultLevel: zapcore.InfoLevel,
+		},
+		{
+			spec: "a.*=info:a.b=warn:a.b.*,re:^(a\\.c|b)$=error:debug",
+			expectedLevels: map[string]zapcore.Level{
+				"a":     zapcore.DebugLevel,
+				"a.b":   zapcore.WarnLevel,
+				"a.b.c": zapcore.ErrorLevel,
+				"a.c":   zapcore.InfoLevel,
+				"a.d.e": zapcore.InfoLevel,
+				"b":     zapcore.ErrorLevel,
+				"b.c":   zapcore.DebugLevel,
+			},
+			expectedDefaultLevel: zapcore.DebugLevel,
+		},
+		{
+			spec: "a,!a.b=debug:a.b.c=error:warn",
+			expectedLevels: map[string]zapcore.Level{
+				"a":       zapcore.DebugLevel,
+				"a.c":     zapcore.DebugLevel,
+				"a.b":     zapcore.WarnLevel,
+				"a.b.d":   zapcore.WarnLevel,
+				"a.b.c":   zapcore.ErrorLevel,
+				"a.b.c.d": zapcore.ErrorLevel,
+				"b":       zapcore.WarnLevel,
+			},
+			expectedDefaultLevel: zapcore.WarnLevel,
+		},
+		{
+			spec: "a=info:a,!a.b.=debug:warn",
+			expectedLevels: map[string]zapcore.Level{
+				"a":     zapcore.DebugLevel,
+				"a.b":   zapcore.WarnLevel,
+				"a.b.c": zapcore.DebugLevel,
+			},
+			expectedDefaultLevel: zapcore.WarnLevel,
+		},
+		{
+			spec: "a.*,!a.b=debug:!a.c,re:^a\\..*$=error:info",
+			expectedLevels: map[string]zapcore.Level{
+				"a":     zapcore.InfoLevel,
+				"a.b":   zapcore.ErrorLevel,
+				"a.c":   zapcore.DebugLevel,
+				"a.d":   zapcore.DebugLevel,
+				"a.c.d": zapcore.DebugLevel,
+				"a.b.c": zapcore.ErrorLevel,
+			},
+			expectedDefaultLevel: zapcore.InfoLevel,
+		},
 		{
 			spec: "info:warn",
 			expectedLevels: map[string]zapcore.Level{
@@ -118,6 +213,16 @@ func TestLoggerLevelsActivateSpecErrors(t *testing.T) {
 		{spec: "a.b=info:a=broken:c.b=info:c.=warn:debug", err: errors.New("invalid logging specification 'a.b=info:a=broken:c.b=info:c.=warn:debug': bad segment 'a=broken'")},
 		{spec: "a*=info:debug", err: errors.New("invalid logging specification 'a*=info:debug': bad logger name 'a*'")},
 		{spec: ".a=info:debug", err: errors.New("invalid logging specification '.a=info:debug': bad logger name '.a'")},
+		{spec: "a.*b=info", err: errors.New("invalid logging specification 'a.*b=info': bad logger name 'a.*b'")},
+		{spec: "*..a=info", err: errors.New("invalid logging specification '*..a=info': bad logger name '*..a'")},
+		{spec: "re:a(=info", err: errors.New("invalid logging specification 're:a(=info': bad regular expression 're:a('")},
+		{spec: "re:a=broken", err: errors.New("invalid logging specification 're:a=broken': bad segment 're:a=broken'")},
+		{spec: "a*,re:a=info", err: errors.New("invalid logging specification 'a*,re:a=info': bad logger name 'a*'")},
+		{spec: "!a=info", err: errors.New("invalid logging specification '!a=info': only negated loggers in segment '!a=info'")},
+		{spec: "a,!a.*=info", err: errors.New("invalid logging specification 'a,!a.*=info': bad negated logger name '!a.*'")},
+		{spec: "a,!.a=info", err: errors.New("invalid logging specification 'a,!.a=info': bad negated logger name '!.a'")},
+		{spec: "a,!!a=info", err: errors.New("invalid logging specification 'a,!!a=info': bad negated logger name '!!a'")},
+		{spec: "!re:a=info", err: errors.New("invalid logging specification '!re:a=info': bad segment '!re'")},
 	}
 	for _, tc := range tests {
 		t.Run(tc.spec, func(t *testing.T) {
@@ -146,6 +251,10 @@ func TestSpec(t *testing.T) {
 		{input: "a_b=error", output: "a_b=error:info"},
 		{input: "debug:a=info:b=warn", output: "a=info:b=warn:debug"},
 		{input: "b=warn:a=error", output: "a=error:b=warn:info"},
+		{input: "debug:re:^b:c$=warn:b=info:*.a=error", output: "b=info:re:^b:c$=warn:*.a=error:debug"},
+		{input: "!a.c,a,!a.b,!a.c=debug", output: "a,!a.b,!a.c=debug:info"},
+		{input: "b,!b.c.=warn:!a.b,re:^a$=error", output: "b,!b.c.=warn:!a.b,re:^a$=error:info"},
+		{input: "*.a,!b.a=warn", output: "*.a,!b.a=warn:info"},
 	}
 
 	for _, tc := range tests {
@@ -172,6 +281,8 @@ func TestEnabled(t *testing.T) {
 		{spec: "a=fatal:b=warn", enabledAt: zapcore.InfoLevel},
 		{spec: "a=warn", enabledAt: zapcore.InfoLevel},
 		{spec: "a=debug", enabledAt: zapcore.DebugLevel},
+		{spec: "fatal:*.a=debug", enabledAt: zapcore.DebugLevel},
+		{spec: "fatal:re:a=warn", enabledAt: zapcore.WarnLevel},
 	}
 
 	for i, tc := range tests {
@@ -190,3 +301,263 @@ func TestEnabled(t *testing.T) {
 		})
 	}
 }
+
+func TestLoggerLevelsExplain(t *testing.T) {
+	levelPtr := func(l zapcore.Level) *zapcore.Level { return &l }
+
+	ll := &flogging.LoggerLevels{}
+	err := ll.ActivateSpec("a=debug:a.b.=error:a.b.c=warn:fatal")
+	assert.NoError(t, err)
+
+	var tests = []struct {
+		logger   string
+		expected flogging.LevelExplanation
+	}{
+		{
+			logger: "a.b.c.d",
+			expected: flogging.LevelExplanation{
+				Logger:  "a.b.c.d",
+				Level:   zapcore.WarnLevel,
+				Segment: "a.b.c=warn",
+				Candidates: []flogging.LevelCandidate{
+					{Name: "a.b.c.d.", Exact: true},
+					{Name: "a.b.c.d"},
+					{Name: "a.b.c", Level: levelPtr(zapcore.WarnLevel), Matched: true},
+					{Name: "a.b"},
+					{Name: "a", Level: levelPtr(zapcore.DebugLevel)},
+				},
+			},
+		},
+		{
+			logger: "a.b",
+			expected: flogging.LevelExplanation{
+				Logger:  "a.b",
+				Level:   zapcore.ErrorLevel,
+				Segment: "a.b.=error",
+				Candidates: []flogging.LevelCandidate{
+					{Name: "a.b.", Exact: true, Level: levelPtr(zapcore.ErrorLevel), Matched: true},
+					{Name: "a.b"},
+					{Name: "a", Level: levelPtr(zapcore.DebugLevel)},
+				},
+			},
+		},
+		{
+			logger: "b",
+			expected: flogging.LevelExplanation{
+				Logger:  "b",
+				Level:   zapcore.FatalLevel,
+				Segment: "fatal",
+				Default: true,
+				Candidates: []flogging.LevelCandidate{
+					{Name: "b.", Exact: true},
+					{Name: "b"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.logger, func(t *testing.T) {
+			explanation := ll.Explain(tc.logger)
+			assert.Equal(t, tc.expected, explanation)
+			assert.Equal(t, ll.Level(tc.logger), explanation.Level)
+		})
+	}
+}
+
+func TestLoggerLevelsExplainPatterns(t *testing.T) {
+	levelPtr := func(l zapcore.Level) *zapcore.Level { return &l }
+
+	ll := &flogging.LoggerLevels{}
+	err := ll.ActivateSpec(`re:^peer\.gossip$=error:peer.**=warn:*.gossip=debug:info`)
+	assert.NoError(t, err)
+
+	explanation := ll.Explain("peer.gossip")
+	assert.Equal(t, flogging.LevelExplanation{
+		Logger:  "peer.gossip",
+		Level:   zapcore.DebugLevel,
+		Segment: "*.gossip=debug",
+		Candidates: []flogging.LevelCandidate{
+			{Name: "peer.gossip.", Exact: true},
+			{Name: "peer.gossip"},
+			{Name: "peer.gossip", Selector: "*.gossip", Level: levelPtr(zapcore.DebugLevel), Matched: true},
+			{Name: "peer.gossip", Selector: "peer.**", Level: levelPtr(zapcore.WarnLevel)},
+			{Name: "peer.gossip", Selector: `re:^peer\.gossip$`, Level: levelPtr(zapcore.ErrorLevel)},
+			{Name: "peer"},
+			{Name: "peer", Selector: "peer.**", Level: levelPtr(zapcore.WarnLevel)},
+		},
+	}, explanation)
+	assert.Equal(t, ll.Level("peer.gossip"), explanation.Level)
+}
+
+func TestLoggerLevelsExplainNegated(t *testing.T) {
+	levelPtr := func(l zapcore.Level) *zapcore.Level { return &l }
+
+	ll := &flogging.LoggerLevels{}
+	err := ll.ActivateSpec("peer,!peer.gossip=debug:info")
+	assert.NoError(t, err)
+
+	explanation := ll.Explain("peer.gossip.comm")
+	assert.Equal(t, flogging.LevelExplanation{
+		Logger:  "peer.gossip.comm",
+		Level:   zapcore.InfoLevel,
+		Segment: "info",
+		Default: true,
+		Candidates: []flogging.LevelCandidate{
+			{Name: "peer.gossip.comm.", Exact: true},
+			{Name: "peer.gossip.comm"},
+			{Name: "peer.gossip"},
+			{Name: "peer", Level: levelPtr(zapcore.DebugLevel), Excluded: "!peer.gossip"},
+		},
+	}, explanation)
+
+	explanation = ll.Explain("peer.ledger")
+	assert.Equal(t, zapcore.DebugLevel, explanation.Level)
+	assert.Equal(t, "peer,!peer.gossip=debug", explanation.Segment)
+	assert.False(t, explanation.Default)
+}
+
+func TestLoggerLevelsExplainJSON(t *testing.T) {
+	ll := &flogging.LoggerLevels{}
+	err := ll.ActivateSpec("peer=payload:peer.gossip.=debug:info")
+	assert.NoError(t, err)
+
+	explanation := ll.Explain("peer.ledger")
+	b, err := json.Marshal(explanation)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"logger": "peer.ledger",
+		"level": "payload",
+		"segment": "peer=payload",
+		"default": false,
+		"candidates": [
+			{"name": "peer.ledger.", "exact": true, "matched": false},
+			{"name": "peer.ledger", "exact": false, "matched": false},
+			{"name": "peer", "exact": false, "level": "payload", "matched": true}
+		]
+	}`, string(b))
+
+	var decoded flogging.LevelExplanation
+	err = json.Unmarshal(b, &decoded)
+	require.NoError(t, err)
+	assert.Equal(t, explanation, decoded)
+
+	b, err = json.Marshal(ll.Explain("orderer"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"logger": "orderer",
+		"level": "info",
+		"segment": "info",
+		"default": true,
+		"candidates": [
+			{"name": "orderer.", "exact": true, "matched": false},
+			{"name": "orderer", "exact": false, "matched": false}
+		]
+	}`, string(b))
+
+	err = json.Unmarshal([]byte(`{"level": "bogus"}`), &decoded)
+	assert.EqualError(t, err, "invalid log level: bogus")
+}
+func TestLoggerLevelsConcurrentAccess(t *testing.T) {
+	ll := &flogging.LoggerLevels{}
+	err := ll.ActivateSpec("info")
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				name := fmt.Sprintf("logger%d.%d", i, j%10)
+				lvl := ll.Level(name)
+				assert.True(t, lvl == zapcore.InfoLevel || lvl == zapcore.DebugLevel, "unexpected level %s", lvl)
+				ll.Enabled(lvl)
+				ll.Explain(name)
+			}
+		}(i)
+	}
+	for _, spec := range []string{"logger1=debug:info", "info", "logger2.3.=debug"} {
+		err := ll.ActivateSpec(spec)
+		require.NoError(t, err)
+	}
+	wg.Wait()
+
+	assert.Equal(t, zapcore.DebugLevel, ll.Level("logger2.3"))
+	assert.Equal(t, zapcore.InfoLevel, ll.Level("logger1.3"))
+}
+
+func benchmarkGOMAXPROCS(b *testing.B, fn func(b *testing.B)) {
+	for _, procs := range []int{1, 2, 4, 8, 16} {
+		b.Run(fmt.Sprintf("GOMAXPROCS=%d", procs), func(b *testing.B) {
+			defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(procs))
+			fn(b)
+		})
+	}
+}
+
+func BenchmarkLoggerLevels_Level(b *testing.B) {
+	ll := &flogging.LoggerLevels{}
+	err := ll.ActivateSpec("gossip=debug:gossip.comm.=warn:*.state=error:info")
+	require.NoError(b, err)
+
+	names := []string{"gossip", "gossip.comm", "gossip.state", "ledger", "ledger.kvledger", "orderer.consensus"}
+	benchmarkGOMAXPROCS(b, func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			for i := 0; pb.Next(); i++ {
+				ll.Level(names[i%len(names)])
+			}
+		})
+	})
+}
+
+func BenchmarkLoggerLevels_LevelMiss(b *testing.B) {
+	names := make([]string, 4096)
+	for i := range names {
+		names[i] = fmt.Sprintf("gossip.comm.peer%d", i)
+	}
+
+	benchmarkGOMAXPROCS(b, func(b *testing.B) {
+		ll := &flogging.LoggerLevels{}
+		err := ll.ActivateSpec("gossip=debug:gossip.comm.=warn:*.state=error:info")
+		require.NoError(b, err)
+
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for i := 0; pb.Next(); i++ {
+				ll.Level(names[i%len(names)])
+			}
+		})
+	})
+}
+
+func BenchmarkLoggerLevels_Enabled(b *testing.B) {
+	ll := &flogging.LoggerLevels{}
+	err := ll.ActivateSpec("gossip=debug:info")
+	require.NoError(b, err)
+
+	benchmarkGOMAXPROCS(b, func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				ll.Enabled(zapcore.InfoLevel)
+			}
+		})
+	})
+}
+
+func BenchmarkCore_Check(b *testing.B) {
+	logging, err := flogging.New(flogging.Config{
+		LogSpec: "gossip=debug:info",
+		Writer:  ioutil.Discard,
+	})
+	require.NoError(b, err)
+
+	loggers := []*zap.Logger{logging.ZapLogger("gossip.comm"), logging.ZapLogger("ledger")}
+	benchmarkGOMAXPROCS(b, func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			for i := 0; pb.Next(); i++ {
+				loggers[i%len(loggers)].Check(zapcore.DebugLevel, "message")
+			}
+		})
+	})
+}
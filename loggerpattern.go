@@ -0,0 +1,160 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package flogging
+
+import (
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// regexpPrefix introduces a regular expression logger selector in a spec.
+const regexpPrefix = "re:"
+
+// loggerNameComponentRegexp defines the valid components of a logger name.
+var loggerNameComponentRegexp = regexp.MustCompile(`^[[:alnum:]_#:-]+$`)
+
+// A levelPattern assigns a level to the loggers matched by a glob or a
+// regular expression selector.
+type levelPattern struct {
+	selector string
+	regexp   *regexp.Regexp
+	glob     bool
+	level    zapcore.Level
+	excludes []string // negated loggers of the segment
+}
+
+// newGlobPattern creates a levelPattern from a glob selector. A '*'
+// component matches exactly one component of a logger name and a '**'
+// component matches any number of components. Like other logger names in a
+// spec, a glob matches the loggers it names and their descendants unless it
+// ends with a period.
+func newGlobPattern(selector string, level zapcore.Level) (levelPattern, bool) {
+	exact := strings.HasSuffix(selector, ".")
+	components := strings.Split(strings.TrimSuffix(selector, "."), ".")
+
+	expr := &strings.Builder{}
+	expr.WriteString("^")
+	for i, c := range components {
+		last := i == len(components)-1
+		switch {
+		case c == "**" && i == 0 && last:
+			expr.WriteString(`[^.]+(?:\.[^.]+)*`)
+			continue
+		case c == "**" && last:
+			expr.WriteString(`(?:\.[^.]+)*`)
+			continue
+		}
+
+		if i > 0 && components[i-1] != "**" {
+			expr.WriteString(`\.`)
+		}
+		switch {
+		case c == "**":
+			expr.WriteString(`(?:[^.]+\.)*`)
+		case c == "*":
+			expr.WriteString(`[^.]+`)
+		case loggerNameComponentRegexp.MatchString(c):
+			expr.WriteString(regexp.QuoteMeta(c))
+		default:
+			return levelPattern{}, false
+		}
+	}
+	if exact {
+		expr.WriteString(`\.`)
+	}
+	expr.WriteString("$")
+
+	return levelPattern{
+		selector: selector,
+		regexp:   regexp.MustCompile(expr.String()),
+		glob:     true,
+		level:    level,
+	}, true
+}
+
+// newRegexpPattern creates a levelPattern from a regular expression selector.
+// The expression is matched against complete logger names.
+func newRegexpPattern(selector string, level zapcore.Level) (levelPattern, error) {
+	re, err := regexp.Compile(strings.TrimPrefix(selector, regexpPrefix))
+	if err != nil {
+		return levelPattern{}, err
+	}
+	return levelPattern{selector: selector, regexp: re, level: level}, nil
+}
+
+// isGlob determines whether a logger selector contains wildcards.
+func isGlob(selector string) bool {
+	return strings.Contains(selector, "*")
+}
+
+// regexpSelectorIndex returns the index of the regular expression selector
+// in a spec segment or -1 when the segment does not contain one.
+func regexpSelectorIndex(segment string) int {
+	if strings.HasPrefix(segment, regexpPrefix) {
+		return 0
+	}
+	if idx := strings.Index(segment, ","+regexpPrefix); idx >= 0 {
+		return idx + 1
+	}
+	return -1
+}
+
+// splitSpec splits a logging spec into segments. Segments are separated by
+// colons except within a regular expression selector, which extends to the
+// '=' that is followed by the level of the segment.
+func splitSpec(spec string) []string {
+	var segments []string
+	rest := spec
+	for {
+		idx := strings.Index(rest, ":")
+		if idx < 0 {
+			return append(segments, rest)
+		}
+
+		head := rest[:idx]
+		// the colon that follows a regular expression prefix does not end
+		// the segment
+		if regexpSelectorIndex(head+":") < 0 {
+			segments = append(segments, head)
+			rest = rest[idx+1:]
+			continue
+		}
+
+		end := regexpSegmentEnd(rest, idx+1)
+		if end < 0 {
+			return append(segments, rest)
+		}
+		segments = append(segments, rest[:end])
+		if end == len(rest) {
+			return segments
+		}
+		rest = rest[end+1:]
+	}
+}
+
+// regexpSegmentEnd finds the end of a segment that contains a regular
+// expression selector starting before offset. The segment ends after the
+// first '=' that is followed by a valid level and the end of the segment.
+func regexpSegmentEnd(s string, offset int) int {
+	for i := offset; i < len(s); i++ {
+		if s[i] != '=' {
+			continue
+		}
+		end := strings.Index(s[i+1:], ":")
+		if end < 0 {
+			end = len(s)
+		} else {
+			end += i + 1
+		}
+		if IsValidLevel(s[i+1 : end]) {
+			return end
+		}
+	}
+	return -1
+}
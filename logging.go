@@ -10,9 +10,11 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"runtime/debug"
 	"sync"
+	"sync/atomic"
 
-	logging "github.com/op/go-logging"
+	"github.com/pkg/errors"
 	"github.com/redresseur/flogging/fabenc"
 	zaplogfmt "github.com/sykesm/zap-logfmt"
 	"go.uber.org/zap"
@@ -22,7 +24,12 @@ import (
 // Config is used to provide dependencies to a Logging instance.
 type Config struct {
 	// Format is the log record format specifier for the Logging instance. If the
-	// spec is the string "json", log records will be formatted as JSON. Any
+	// spec is the string "json", log records will be formatted as JSON. If the
+	// spec is the string "dev", log records will be formatted across multiple
+	// lines for readability during development. The strings "ecs" and "otel"
+	// format log records as Elastic Common Schema documents and OpenTelemetry
+	// log records respectively. The string "cbor" selects a compact binary
+	// encoding that can be converted back to text with cmd/flogdecode. Any
 	// other string will be provided to the FormatEncoder. Please see
 	// fabenc.ParseFormat for details on the supported verbs.
 	//
@@ -36,9 +43,34 @@ type Config struct {
 	// If LogSpec is not provided, loggers will be enabled at the INFO level.
 	LogSpec string
 
+	// EncoderConfig determines the keys and the time, level, duration, and
+	// caller encoders used by the JSON and LOGFMT formats. Keys that are empty
+	// are omitted from log records. EncoderConfigPreset provides
+	// configurations for common log pipelines.
+	//
+	// If EncoderConfig is not provided, the configuration of EncoderPreset
+	// will be used.
+	EncoderConfig *zapcore.EncoderConfig
+
+	// EncoderPreset names the EncoderConfigPreset used when EncoderConfig is
+	// not provided: "default", "ecs", or "gcp". It must not be set together
+	// with EncoderConfig.
+	//
+	// If EncoderPreset is not provided, the "default" preset will be used.
+	EncoderPreset string
+
+	// Resource holds attributes that describe the source of log records, such
+	// as service.name or host.name. The attributes are added to every log
+	// record by the ECS and OTEL formats.
+	Resource map[string]string
+
 	// Writer is the sink for encoded and formatted log records.
 	//
 	// If a Writer is not provided, os.Stderr will be used as the log sink.
+	//
+	// Color escapes requested by the format are only emitted when the writer
+	// is a terminal. The NO_COLOR and FORCE_COLOR environment variables can be
+	// used to override the detection.
 	Writer io.Writer
 }
 
@@ -46,29 +78,51 @@ type Config struct {
 // intended to bridge between the legacy logging infrastructure built around
 // go-logging and the structured, level logging provided by zap.
 type Logging struct {
+	// counters is accessed atomically and kept first for 64-bit alignment.
+	counters observerCounters
+
 	*LoggerLevels
 
 	mutex          sync.RWMutex
+	format         string
 	encoding       Encoding
 	encoderConfig  zapcore.EncoderConfig
+	resource       map[string]string
+	generation     uint64
 	multiFormatter *fabenc.MultiFormatter
+	formatters     []fabenc.Formatter
+	devOptions     *fabenc.DevOptions
+	color          bool
 	writer         zapcore.WriteSyncer
+	output         io.Writer
 	observer       Observer
+
+	historyMutex sync.Mutex
+	history      []SpecChange
+	historyID    uint64
+
+	loggersMutex sync.RWMutex
+	loggers      map[string]*uint64
+
+	streamMutex sync.RWMutex
+	streams     []*entrySubscription
+	streaming   int32
+
+	ring      atomic.Value // ringHolder
+	recorder  atomic.Value // recorderHolder
+	flushCore *Core        // writes the entries flushed by the recorder
 }
 
 // New creates a new logging system and initializes it with the provided
 // configuration.
 func New(c Config) (*Logging, error) {
-	encoderConfig := zap.NewProductionEncoderConfig()
-	encoderConfig.NameKey = "name"
-
 	s := &Logging{
-		LoggerLevels: &LoggerLevels{
-			defaultLevel: defaultLevel,
-		},
-		encoderConfig:  encoderConfig,
+		LoggerLevels:   &LoggerLevels{},
+		encoderConfig:  NewDefaultEncoderConfig(),
 		multiFormatter: fabenc.NewMultiFormatter(),
+		devOptions:     fabenc.NewDevOptions(mainModule(), false),
 	}
+	s.flushCore = s.newCore()
 
 	err := s.Apply(c)
 	if err != nil {
@@ -79,19 +133,36 @@ func New(c Config) (*Logging, error) {
 
 // Apply applies the provided configuration to the logging system.
 func (s *Logging) Apply(c Config) error {
-	err := s.SetFormat(c.Format)
+	if c.EncoderConfig != nil && c.EncoderPreset != "" {
+		return errors.New("encoder config and encoder preset are mutually exclusive")
+	}
+	encoderConfig, err := EncoderConfigPreset(c.EncoderPreset)
+	if err != nil {
+		return err
+	}
+	if c.EncoderConfig != nil {
+		encoderConfig = *c.EncoderConfig
+	}
+
+	err = s.SetFormat(c.Format)
 	if err != nil {
 		return err
 	}
 
+	s.SetEncoderConfig(encoderConfig)
+	s.SetResource(c.Resource)
+
+	source := SpecSourceConfig
 	if c.LogSpec == "" {
 		c.LogSpec = os.Getenv("FABRIC_LOGGING_SPEC")
+		source = SpecSourceEnv
 	}
 	if c.LogSpec == "" {
 		c.LogSpec = defaultLevel.String()
+		source = SpecSourceDefault
 	}
 
-	err = s.LoggerLevels.ActivateSpec(c.LogSpec)
+	err = s.ActivateSpecFrom(c.LogSpec, source, "")
 	if err != nil {
 		return err
 	}
@@ -101,15 +172,19 @@ func (s *Logging) Apply(c Config) error {
 	}
 	s.SetWriter(c.Writer)
 
-	var formatter logging.Formatter
+	format := c.Format
 	switch s.Encoding() {
-	case JSON, LOGFMT:
-		formatter = SetFormat(defaultFormat)
-	default:
-		formatter = SetFormat(c.Format)
+	case JSON, LOGFMT, DEV, ECS, OTEL, CBOR:
+		format = DefaultFormat
+	}
+	if format == "" {
+		format = DefaultFormat
+	}
+	if !colorEnabled(c.Writer) {
+		format = stripColor(format)
 	}
 
-	InitBackend(formatter, c.Writer)
+	InitBackend(SetFormat(format), c.Writer)
 
 	return nil
 }
@@ -122,32 +197,114 @@ func (s *Logging) SetFormat(format string) error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 	if format == "" {
-		format = defaultFormat
+		format = DefaultFormat
 	}
 
 	if format == "json" {
+		s.format = format
 		s.encoding = JSON
 		return nil
 	}
 
 	if format == "logfmt" {
+		s.format = format
 		s.encoding = LOGFMT
 		return nil
 	}
 
+	if format == "ecs" {
+		s.format = format
+		s.encoding = ECS
+		return nil
+	}
+
+	if format == "otel" {
+		s.format = format
+		s.encoding = OTEL
+		return nil
+	}
+
+	if format == "cbor" {
+		s.format = format
+		s.encoding = CBOR
+		return nil
+	}
+
+	if format == "dev" {
+		formatters, err := fabenc.ParseFormat(DefaultFormat)
+		if err != nil {
+			return err
+		}
+		s.formatters = formatters
+		s.setFormatters()
+		s.format = format
+		s.encoding = DEV
+		return nil
+	}
+
 	formatters, err := fabenc.ParseFormat(format)
 	if err != nil {
 		return err
 	}
-	s.multiFormatter.SetFormatters(formatters)
+	s.formatters = formatters
+	s.setFormatters()
+	s.format = format
 	s.encoding = CONSOLE
 
 	return nil
 }
 
+// Format returns the format specifier most recently provided to SetFormat or
+// the default format when none has been provided.
+func (s *Logging) Format() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if s.format == "" {
+		return DefaultFormat
+	}
+	return s.format
+}
+
+// setFormatters updates the formatters used by the console encoder, disabling
+// color when the writer does not support it. The caller must hold the mutex.
+func (s *Logging) setFormatters() {
+	if s.color {
+		s.multiFormatter.SetFormatters(s.formatters)
+		return
+	}
+	s.multiFormatter.SetFormatters(fabenc.DisableColor(s.formatters))
+}
+
+// SetEncoderConfig updates the keys and value encoders used by the JSON and
+// LOGFMT formats. Log entries created after this method has completed will
+// use the new configuration.
+func (s *Logging) SetEncoderConfig(config zapcore.EncoderConfig) {
+	s.mutex.Lock()
+	s.encoderConfig = config
+	s.generation++
+	s.mutex.Unlock()
+}
+
+// SetResource updates the resource attributes added to log records by the ECS
+// and OTEL formats.
+func (s *Logging) SetResource(resource map[string]string) {
+	copied := make(map[string]string, len(resource))
+	for k, v := range resource {
+		copied[k] = v
+	}
+
+	s.mutex.Lock()
+	s.resource = copied
+	s.generation++
+	s.mutex.Unlock()
+}
+
 // SetWriter controls which writer formatted log records are written to.
 // Writers, with the exception of an *os.File, need to be safe for concurrent
 // use by multiple go routines.
+//
+// Color escapes are disabled unless the writer is a terminal or the
+// FORCE_COLOR environment variable is set.
 func (s *Logging) SetWriter(w io.Writer) {
 	var sw zapcore.WriteSyncer
 	switch t := w.(type) {
@@ -159,8 +316,14 @@ func (s *Logging) SetWriter(w io.Writer) {
 		sw = zapcore.AddSync(w)
 	}
 
+	color := colorEnabled(w)
+
 	s.mutex.Lock()
 	s.writer = sw
+	s.output = w
+	s.color = color
+	s.setFormatters()
+	s.devOptions.SetColor(color)
 	s.mutex.Unlock()
 }
 
@@ -193,8 +356,8 @@ func (s *Logging) Sync() error {
 	return w.Sync()
 }
 
-// Encoding satisfies the Encoding interface. It determines whether the JSON or
-// CONSOLE encoder should be used by the Core when log records are written.
+// Encoding satisfies the Encoding interface. It determines which encoder
+// should be used by the Core when log records are written.
 func (s *Logging) Encoding() Encoding {
 	s.mutex.RLock()
 	e := s.encoding
@@ -208,26 +371,88 @@ func (s *Logging) ZapLogger(name string) *zap.Logger {
 	if !isValidLoggerName(name) {
 		panic(fmt.Sprintf("invalid logger name: %s", name))
 	}
+	entries := s.registerLogger(name)
+	s.LoggerLevels.Level(name)
 
 	s.mutex.RLock()
-	core := &Core{
+	core := s.newCore()
+	s.mutex.RUnlock()
+	core.entries = entries
+
+	return NewZapLogger(newLoggerCore(name, s, core)).Named(name)
+}
+
+// AuditLogger creates a FabricLogger for audit records with the specified
+// name. Its entries are written with the configuration of the logging system
+// but the active spec does not apply to it: entries at INFO and above are
+// always written, so the records cannot be silenced through the spec.
+func (s *Logging) AuditLogger(name string) *FabricLogger {
+	if !isValidLoggerName(name) {
+		panic(fmt.Sprintf("invalid logger name: %s", name))
+	}
+	levels := &LoggerLevels{}
+	if err := levels.ActivateSpec("info"); err != nil {
+		panic(err)
+	}
+
+	s.mutex.RLock()
+	core := s.newCore()
+	s.mutex.RUnlock()
+	core.LevelEnabler = levels
+	core.Levels = levels
+
+	return NewFabricLogger(NewZapLogger(core).Named(name))
+}
+
+// newCore creates a Core that encodes with the current configuration and
+// writes to the logging system. The caller must hold the mutex.
+func (s *Logging) newCore() *Core {
+	return &Core{
 		LevelEnabler: s.LoggerLevels,
 		Levels:       s.LoggerLevels,
-		Encoders: map[Encoding]zapcore.Encoder{
-			JSON:    zapcore.NewJSONEncoder(s.encoderConfig),
-			CONSOLE: fabenc.NewFormatEncoder(s.multiFormatter),
-			LOGFMT:  zaplogfmt.NewEncoder(s.encoderConfig),
-		},
-		Selector: s,
-		Output:   s,
-		Observer: s,
+		Encoders:     s.newEncoders(),
+		Selector:     s,
+		Output:       s,
+		Observer:     s,
+		Factory:      s,
+		generation:   s.generation,
 	}
+}
+
+// EncoderGeneration satisfies the EncoderFactory interface. The generation
+// changes whenever the encoder configuration is updated.
+func (s *Logging) EncoderGeneration() uint64 {
+	s.mutex.RLock()
+	generation := s.generation
 	s.mutex.RUnlock()
+	return generation
+}
+
+// NewEncoders satisfies the EncoderFactory interface. It creates the encoders
+// for all supported encodings from the current configuration.
+func (s *Logging) NewEncoders() map[Encoding]zapcore.Encoder {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.newEncoders()
+}
 
-	return NewZapLogger(core).Named(name)
+// newEncoders creates the encoders for all supported encodings. The caller
+// must hold the mutex.
+func (s *Logging) newEncoders() map[Encoding]zapcore.Encoder {
+	return map[Encoding]zapcore.Encoder{
+		JSON:    zapcore.NewJSONEncoder(s.encoderConfig),
+		CONSOLE: fabenc.NewFormatEncoder(s.multiFormatter),
+		LOGFMT:  zaplogfmt.NewEncoder(s.encoderConfig),
+		DEV:     fabenc.NewDevEncoder(s.devOptions, s.multiFormatter),
+		ECS:     fabenc.NewECSEncoder(s.resource),
+		OTEL:    fabenc.NewOTelEncoder(s.resource),
+		CBOR:    fabenc.NewCBOREncoder(),
+	}
 }
 
 func (s *Logging) Check(e zapcore.Entry, ce *zapcore.CheckedEntry) {
+	s.counters.check(e.Level)
+
 	s.mutex.RLock()
 	observer := s.observer
 	s.mutex.RUnlock()
@@ -238,6 +463,8 @@ func (s *Logging) Check(e zapcore.Entry, ce *zapcore.CheckedEntry) {
 }
 
 func (s *Logging) WriteEntry(e zapcore.Entry, fields []zapcore.Field) {
+	s.counters.write(e.Level)
+
 	s.mutex.RLock()
 	observer := s.observer
 	s.mutex.RUnlock()
@@ -247,9 +474,25 @@ func (s *Logging) WriteEntry(e zapcore.Entry, fields []zapcore.Field) {
 	}
 }
 
+// SetDevModule sets the module whose stack frames are highlighted by the dev
+// format. It defaults to the main module of the running binary.
+func (s *Logging) SetDevModule(module string) {
+	s.devOptions.SetModule(module)
+}
+
 // Logger instantiates a new FabricLogger with the specified name. The name is
 // used to determine which log levels are enabled.
 func (s *Logging) Logger(name string) *FabricLogger {
 	zl := s.ZapLogger(name)
 	return NewFabricLogger(zl)
 }
+
+// mainModule returns the path of the main module of the running binary or an
+// empty string when build information is not available.
+func mainModule() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	return info.Main.Path
+}
@@ -47,6 +47,26 @@ func TestNewWithEnvironment(t *testing.T) {
 	assert.Equal(t, zapcore.InfoLevel, logging.DefaultLevel())
 }
 
+func TestLoggingFormat(t *testing.T) {
+	logging, err := flogging.New(flogging.Config{Writer: &bytes.Buffer{}})
+	assert.NoError(t, err)
+	assert.Equal(t, flogging.DefaultFormat, logging.Format())
+
+	err = logging.SetFormat("json")
+	assert.NoError(t, err)
+	assert.Equal(t, "json", logging.Format())
+	assert.Equal(t, flogging.Encoding(flogging.JSON), logging.Encoding())
+
+	err = logging.SetFormat("%{color:bad}")
+	assert.Error(t, err)
+	assert.Equal(t, "json", logging.Format())
+
+	err = logging.SetFormat("%{level} %{message}")
+	assert.NoError(t, err)
+	assert.Equal(t, "%{level} %{message}", logging.Format())
+	assert.Equal(t, flogging.Encoding(flogging.CONSOLE), logging.Encoding())
+}
+
 //go:generate counterfeiter -o mock/write_syncer.go -fake-name WriteSyncer . writeSyncer
 type writeSyncer interface {
 	zapcore.WriteSyncer
@@ -99,6 +119,28 @@ func TestNamedLogger(t *testing.T) {
 	})
 }
 
+func TestAuditLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logging, err := flogging.New(flogging.Config{
+		Format:  "%{module} %{level} %{message}",
+		LogSpec: "audit=error:error",
+		Writer:  buf,
+	})
+	assert.NoError(t, err)
+
+	audit := logging.AuditLogger("audit")
+	audit.Debug("hidden")
+	audit.Info("recorded")
+	assert.Equal(t, "audit INFO recorded\n", buf.String())
+
+	err = logging.ActivateSpec("audit=fatal")
+	assert.NoError(t, err)
+	audit.Info("still recorded")
+	assert.Equal(t, "audit INFO recorded\naudit INFO still recorded\n", buf.String())
+
+	assert.Panics(t, func() { logging.AuditLogger(".bad") })
+}
+
 func TestInvalidLoggerName(t *testing.T) {
 	names := []string{"test*", ".test", "test.", ".", ""}
 	for _, name := range names {
@@ -148,3 +190,70 @@ func TestLoggerCoreCheck(t *testing.T) {
 	assert.NoError(t, err)
 	assert.True(t, logger.Core().Enabled(zapcore.DebugLevel), "debug should now be enabled at debug level")
 }
+
+func TestLoggingColor(t *testing.T) {
+	for _, env := range []string{"NO_COLOR", "FORCE_COLOR"} {
+		if old, set := os.LookupEnv(env); set {
+			defer os.Setenv(env, old)
+		} else {
+			defer os.Unsetenv(env)
+		}
+		os.Unsetenv(env)
+	}
+
+	var tests = []struct {
+		desc       string
+		noColor    string
+		forceColor string
+		expected   string
+	}{
+		{desc: "not a terminal", expected: "INFO message\n"},
+		{desc: "forced", forceColor: "1", expected: "\x1b[34mINFO\x1b[0m message\n"},
+		{desc: "forced with false", forceColor: "false", expected: "INFO message\n"},
+		{desc: "no color", noColor: "1", expected: "INFO message\n"},
+		{desc: "no color wins", noColor: "1", forceColor: "1", expected: "INFO message\n"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			os.Setenv("NO_COLOR", tc.noColor)
+			os.Setenv("FORCE_COLOR", tc.forceColor)
+
+			buf := &bytes.Buffer{}
+			logging, err := flogging.New(flogging.Config{
+				Format: "%{color}%{level}%{color:reset} %{message}",
+				Writer: buf,
+			})
+			assert.NoError(t, err)
+
+			logging.Logger("color").Info("message")
+			assert.Equal(t, tc.expected, buf.String())
+		})
+	}
+}
+
+func TestLoggingColorSetWriter(t *testing.T) {
+	if old, set := os.LookupEnv("FORCE_COLOR"); set {
+		defer os.Setenv("FORCE_COLOR", old)
+	} else {
+		defer os.Unsetenv("FORCE_COLOR")
+	}
+	os.Setenv("FORCE_COLOR", "1")
+
+	buf := &bytes.Buffer{}
+	logging, err := flogging.New(flogging.Config{
+		Format: "%{color}%{message}%{color:reset}",
+		Writer: buf,
+	})
+	assert.NoError(t, err)
+	logger := logging.Logger("color")
+
+	logger.Info("colored")
+	assert.Equal(t, "\x1b[34mcolored\x1b[0m\n", buf.String())
+
+	os.Setenv("FORCE_COLOR", "0")
+	buf.Reset()
+	logging.SetWriter(buf)
+	logger.Info("plain")
+	assert.Equal(t, "plain\n", buf.String())
+}
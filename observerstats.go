@@ -0,0 +1,82 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package flogging
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// observerLevels is the number of levels counted by the observer stats, from
+// PayloadLevel to FatalLevel.
+const observerLevels = int(zapcore.FatalLevel-PayloadLevel) + 1
+
+// ObserverStats describes the entries the logging system has passed to its
+// observer.
+type ObserverStats struct {
+	// Observer is the type of the observer or empty when none is set.
+	Observer string `json:"observer,omitempty"`
+	// Checked counts the entries that were checked by level name.
+	Checked map[string]uint64 `json:"checked"`
+	// Written counts the entries that were written by level name.
+	Written map[string]uint64 `json:"written"`
+}
+
+// observerCounters counts checked and written entries by level. The counters
+// are accessed atomically.
+type observerCounters struct {
+	checked [observerLevels]uint64
+	written [observerLevels]uint64
+}
+
+// ObserverStats returns the number of entries checked and written by level
+// since the logging system was created. Entries are counted whether or not an
+// observer is set.
+func (s *Logging) ObserverStats() ObserverStats {
+	s.mutex.RLock()
+	observer := s.observer
+	s.mutex.RUnlock()
+
+	stats := ObserverStats{
+		Checked: map[string]uint64{},
+		Written: map[string]uint64{},
+	}
+	if observer != nil {
+		stats.Observer = fmt.Sprintf("%T", observer)
+	}
+	for i := 0; i < observerLevels; i++ {
+		name := LevelName(PayloadLevel + zapcore.Level(i))
+		if n := atomic.LoadUint64(&s.counters.checked[i]); n > 0 {
+			stats.Checked[name] = n
+		}
+		if n := atomic.LoadUint64(&s.counters.written[i]); n > 0 {
+			stats.Written[name] = n
+		}
+	}
+	return stats
+}
+
+// counterIndex returns the index of the counters for a level and false when
+// the level is not counted.
+func counterIndex(level zapcore.Level) (int, bool) {
+	i := int(level - PayloadLevel)
+	return i, i >= 0 && i < observerLevels
+}
+
+func (c *observerCounters) check(level zapcore.Level) {
+	if i, ok := counterIndex(level); ok {
+		atomic.AddUint64(&c.checked[i], 1)
+	}
+}
+
+func (c *observerCounters) write(level zapcore.Level) {
+	if i, ok := counterIndex(level); ok {
+		atomic.AddUint64(&c.written[i], 1)
+	}
+}
@@ -0,0 +1,40 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package flogging_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/redresseur/flogging"
+	"github.com/redresseur/flogging/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggingObserverStats(t *testing.T) {
+	logging, err := flogging.New(flogging.Config{
+		LogSpec: "info",
+		Writer:  &bytes.Buffer{},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, flogging.ObserverStats{Checked: map[string]uint64{}, Written: map[string]uint64{}}, logging.ObserverStats())
+
+	logger := logging.Logger("stats")
+	logger.Debug("dropped")
+	logger.Info("info")
+	logger.Info("info")
+	logger.Warn("warn")
+
+	assert.Equal(t, flogging.ObserverStats{
+		Checked: map[string]uint64{"info": 2, "warn": 1},
+		Written: map[string]uint64{"info": 2, "warn": 1},
+	}, logging.ObserverStats())
+
+	logging.SetObserver(&mock.Observer{})
+	assert.Equal(t, "*mock.Observer", logging.ObserverStats().Observer)
+}
@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"strconv"
+	"sync"
 	"sync/atomic"
 	"time"
 )
@@ -77,15 +78,56 @@ func Close(w io.Writer) error {
 	return nil
 }
 
+// Status describes the file a writer created by NewWriter is writing to.
+type Status struct {
+	Dir          string    `json:"dir"`
+	Prefix       string    `json:"prefix"`
+	Model        string    `json:"model"`
+	MaxSize      int64     `json:"max_size,omitempty"`
+	MaxFileCount int       `json:"max_file_count,omitempty"`
+	Path         string    `json:"path"`  // the path of the current file
+	Size         int64     `json:"size"`  // the bytes written to the current file
+	Date         time.Time `json:"date"`  // the day the current file was created
+	Index        int       `json:"index"` // the index of the current file within the day
+}
+
+// GetStatus returns the rotation status of a writer created by NewWriter. The
+// second return value is false for any other writer.
+func GetStatus(w io.Writer) (Status, bool) {
+	if fw, ok := w.(*fileWriter); ok {
+		return fw.status(), true
+	}
+
+	return Status{}, false
+}
+
 type fileWriter struct {
 	data *structure.Queue
 	*WriterConfig
 	fileDate time.Time
+	mutex    sync.RWMutex // guards fbs and index while the file rotates
 	fbs      *fileBean
 	index    int
 	ctx      context.Context
 }
 
+func (fw *fileWriter) status() Status {
+	fw.mutex.RLock()
+	defer fw.mutex.RUnlock()
+
+	return Status{
+		Dir:          fw.Dir,
+		Prefix:       fw.Prefix,
+		Model:        fw.Model,
+		MaxSize:      fw.MaxSize,
+		MaxFileCount: fw.MaxFileCount,
+		Path:         fw.fbs.path,
+		Size:         atomic.LoadInt64(&fw.fbs.fileSize),
+		Date:         fw.fbs._date,
+		Index:        fw.index + 1,
+	}
+}
+
 func (fw *fileWriter) Write(p []byte) (n int, err error) {
 	buffer := make([]byte, len(p), len(p)+1)
 	copy(buffer, p)
@@ -196,6 +238,9 @@ func (fw *fileWriter) fileCheck() error {
 		return nil
 	}
 
+	fw.mutex.Lock()
+	defer fw.mutex.Unlock()
+
 	fs, err := fw.statisticsLogFiles()
 	if err != nil {
 		return err
@@ -225,7 +270,7 @@ func (fw *fileWriter) isMustRename(fb *fileBean) bool {
 			return true
 		}
 	case SizeModel:
-		return fb.fileSize >= fw.MaxSize
+		return atomic.LoadInt64(&fb.fileSize) >= fw.MaxSize
 	}
 	return false
 }
@@ -0,0 +1,113 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package flogging
+
+import (
+	"encoding/json"
+	"sort"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// LoggerInfo describes a logger known to the logging system.
+type LoggerInfo struct {
+	// Name is the name of the logger.
+	Name string `json:"name"`
+	// Level is the effective level of the logger.
+	Level zapcore.Level `json:"level"`
+	// Segment is the normalized spec segment that determined the level.
+	Segment string `json:"segment"`
+	// Default is true when the default level applies to the logger.
+	Default bool `json:"default"`
+	// Entries is the number of entries the logger has written.
+	Entries uint64 `json:"entries"`
+}
+
+// MarshalJSON encodes the level of the logger with its flogging name.
+func (i LoggerInfo) MarshalJSON() ([]byte, error) {
+	type info LoggerInfo
+	return json.Marshal(struct {
+		info
+		Level textLevel `json:"level"`
+	}{info(i), textLevel(i.Level)})
+}
+
+// UnmarshalJSON decodes a logger encoded by MarshalJSON.
+func (i *LoggerInfo) UnmarshalJSON(b []byte) error {
+	type info LoggerInfo
+	aux := struct {
+		*info
+		Level textLevel `json:"level"`
+	}{info: (*info)(i)}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+	i.Level = zapcore.Level(aux.Level)
+	return nil
+}
+
+// Loggers returns the loggers created by the logging system and the loggers
+// that have written entries through it, ordered by name.
+func (s *Logging) Loggers() []LoggerInfo {
+	s.loggersMutex.RLock()
+	loggers := make([]LoggerInfo, 0, len(s.loggers))
+	for name, entries := range s.loggers {
+		loggers = append(loggers, LoggerInfo{Name: name, Entries: atomic.LoadUint64(entries)})
+	}
+	s.loggersMutex.RUnlock()
+
+	sort.Slice(loggers, func(i, j int) bool { return loggers[i].Name < loggers[j].Name })
+	for i := range loggers {
+		explanation := s.Explain(loggers[i].Name)
+		loggers[i].Level = explanation.Level
+		loggers[i].Segment = explanation.Segment
+		loggers[i].Default = explanation.Default
+	}
+	return loggers
+}
+
+// LoggerInfo returns the effective level of a logger and the number of
+// entries it has written. The logger does not need to have been created.
+func (s *Logging) LoggerInfo(name string) LoggerInfo {
+	info := LoggerInfo{Name: name}
+
+	s.loggersMutex.RLock()
+	if entries, ok := s.loggers[name]; ok {
+		info.Entries = atomic.LoadUint64(entries)
+	}
+	s.loggersMutex.RUnlock()
+
+	explanation := s.Explain(name)
+	info.Level = explanation.Level
+	info.Segment = explanation.Segment
+	info.Default = explanation.Default
+	return info
+}
+
+// registerLogger adds a logger name to the registry and returns the counter
+// of entries written by the logger.
+func (s *Logging) registerLogger(name string) *uint64 {
+	s.loggersMutex.RLock()
+	entries, ok := s.loggers[name]
+	s.loggersMutex.RUnlock()
+	if ok {
+		return entries
+	}
+
+	s.loggersMutex.Lock()
+	defer s.loggersMutex.Unlock()
+	if entries, ok := s.loggers[name]; ok {
+		return entries
+	}
+	if s.loggers == nil {
+		s.loggers = map[string]*uint64{}
+	}
+	entries = new(uint64)
+	s.loggers[name] = entries
+	return entries
+}
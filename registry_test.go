@@ -0,0 +1,99 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package flogging_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/redresseur/flogging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLoggingLoggers(t *testing.T) {
+	logging, err := flogging.New(flogging.Config{
+		LogSpec: "gossip=debug:gossip.comm.=error:warn",
+		Writer:  &bytes.Buffer{},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, logging.Loggers())
+
+	gossip := logging.Logger("gossip")
+	comm := logging.Logger("gossip.comm")
+	ledger := logging.ZapLogger("ledger")
+	logging.Logger("gossip")
+
+	gossip.Debug("debug")
+	gossip.Info("info")
+	comm.Info("dropped")
+	comm.Error("error")
+	ledger.Warn("warn")
+	ledger.Info("dropped")
+	gossip.Named("state").Debug("debug")
+
+	assert.Equal(t, []flogging.LoggerInfo{
+		{Name: "gossip", Level: zapcore.DebugLevel, Segment: "gossip=debug", Entries: 2},
+		{Name: "gossip.comm", Level: zapcore.ErrorLevel, Segment: "gossip.comm.=error", Entries: 1},
+		{Name: "gossip.state", Level: zapcore.DebugLevel, Segment: "gossip=debug", Entries: 1},
+		{Name: "ledger", Level: zapcore.WarnLevel, Segment: "warn", Default: true, Entries: 1},
+	}, logging.Loggers())
+
+	err = logging.ActivateSpec("ledger=info")
+	require.NoError(t, err)
+	loggers := logging.Loggers()
+	require.Len(t, loggers, 4)
+	assert.Equal(t, flogging.LoggerInfo{Name: "gossip", Level: zapcore.InfoLevel, Segment: "info", Default: true, Entries: 2}, loggers[0])
+	assert.Equal(t, flogging.LoggerInfo{Name: "ledger", Level: zapcore.InfoLevel, Segment: "ledger=info", Entries: 1}, loggers[3])
+}
+
+func TestLoggingLoggersWith(t *testing.T) {
+	logging, err := flogging.New(flogging.Config{
+		LogSpec: "info",
+		Writer:  &bytes.Buffer{},
+	})
+	require.NoError(t, err)
+
+	gossip := logging.Logger("gossip")
+	state := gossip.Named("state")
+	for i := 0; i < 3; i++ {
+		gossip.With("peer", i).Info("info")
+		state.With("block", i).Info("info")
+	}
+
+	assert.Equal(t, []flogging.LoggerInfo{
+		{Name: "gossip", Level: zapcore.InfoLevel, Segment: "info", Default: true, Entries: 3},
+		{Name: "gossip.state", Level: zapcore.InfoLevel, Segment: "info", Default: true, Entries: 3},
+	}, logging.Loggers())
+}
+
+func TestLoggingLoggerInfo(t *testing.T) {
+	logging, err := flogging.New(flogging.Config{
+		LogSpec: "gossip=debug:warn",
+		Writer:  &bytes.Buffer{},
+	})
+	require.NoError(t, err)
+
+	logging.Logger("gossip.comm").Debug("debug")
+
+	assert.Equal(t, flogging.LoggerInfo{Name: "gossip.comm", Level: zapcore.DebugLevel, Segment: "gossip=debug", Entries: 1}, logging.LoggerInfo("gossip.comm"))
+	assert.Equal(t, flogging.LoggerInfo{Name: "ledger", Level: zapcore.WarnLevel, Segment: "warn", Default: true}, logging.LoggerInfo("ledger"))
+	assert.Len(t, logging.Loggers(), 1)
+}
+
+func TestLoggerInfoJSON(t *testing.T) {
+	info := flogging.LoggerInfo{Name: "gossip", Level: flogging.PayloadLevel, Segment: "gossip=payload", Entries: 2}
+	b, err := json.Marshal(info)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name": "gossip", "level": "payload", "segment": "gossip=payload", "default": false, "entries": 2}`, string(b))
+
+	var decoded flogging.LoggerInfo
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, info, decoded)
+}
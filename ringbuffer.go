@@ -0,0 +1,240 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package flogging
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// ErrNoRingBuffer is returned by RingEntries when no ring buffer has been
+// set.
+var ErrNoRingBuffer = errors.New("no ring buffer has been set")
+
+// A RingEntry is an entry retained by a RingBuffer.
+type RingEntry struct {
+	Time    time.Time              `json:"time"`
+	Level   string                 `json:"level"`
+	Logger  string                 `json:"logger,omitempty"`
+	Message string                 `json:"message"`
+	Caller  string                 `json:"caller,omitempty"`
+	Stack   string                 `json:"stack,omitempty"`
+	Fields  map[string]interface{} `json:"fields,omitempty"`
+
+	level zapcore.Level
+}
+
+// A RingFilter selects entries from a RingBuffer. The zero value selects all
+// entries.
+type RingFilter struct {
+	// Level is the name of the minimum level of the selected entries.
+	Level string
+	// Logger selects the entries of the named logger and its descendants.
+	Logger string
+	// Since selects the entries written at or after the time.
+	Since time.Time
+	// Contains selects the entries whose message contains the string.
+	Contains string
+	// Limit restricts the selection to the most recent entries.
+	Limit int
+}
+
+// A RingBuffer retains the most recent entries written by the loggers of a
+// logging system. Entries are captured at the level of the buffer regardless
+// of the active logging spec, so DEBUG entries can be retained while the
+// writer only receives INFO entries.
+type RingBuffer struct {
+	level zap.AtomicLevel
+
+	mutex   sync.Mutex
+	entries []RingEntry
+	next    int
+	count   int
+}
+
+// NewRingBuffer creates a RingBuffer that retains the last size entries at
+// or above level.
+func NewRingBuffer(size int, level zapcore.Level) *RingBuffer {
+	if size <= 0 {
+		size = 1
+	}
+	return &RingBuffer{
+		level:   zap.NewAtomicLevelAt(level),
+		entries: make([]RingEntry, size),
+	}
+}
+
+// Level returns the minimum level of the captured entries.
+func (r *RingBuffer) Level() zapcore.Level {
+	return r.level.Level()
+}
+
+// SetLevel changes the minimum level of the captured entries.
+func (r *RingBuffer) SetLevel(level zapcore.Level) {
+	r.level.SetLevel(level)
+}
+
+// Enabled returns true when entries at the level are captured.
+func (r *RingBuffer) Enabled(level zapcore.Level) bool {
+	return r.level.Enabled(level)
+}
+
+// Clear removes the retained entries.
+func (r *RingBuffer) Clear() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i := range r.entries {
+		r.entries[i] = RingEntry{}
+	}
+	r.next, r.count = 0, 0
+}
+
+// Entries returns the retained entries selected by the filter from the
+// oldest to the most recent.
+func (r *RingBuffer) Entries(filter RingFilter) []RingEntry {
+	minLevel := PayloadLevel
+	if filter.Level != "" {
+		minLevel = NameToLevel(filter.Level)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	entries := []RingEntry{}
+	start := r.next - r.count
+	if start < 0 {
+		start += len(r.entries)
+	}
+	for i := 0; i < r.count; i++ {
+		e := r.entries[(start+i)%len(r.entries)]
+		switch {
+		case e.level < minLevel:
+		case filter.Logger != "" && !hasLoggerPrefix(e.Logger, filter.Logger):
+		case !filter.Since.IsZero() && e.Time.Before(filter.Since):
+		case filter.Contains != "" && !strings.Contains(e.Message, filter.Contains):
+		default:
+			entries = append(entries, e)
+		}
+	}
+	if filter.Limit > 0 && len(entries) > filter.Limit {
+		entries = entries[len(entries)-filter.Limit:]
+	}
+	return entries
+}
+
+func (r *RingBuffer) add(e zapcore.Entry, fields []zapcore.Field) {
+	entry := RingEntry{
+		Time:    e.Time,
+		Level:   LevelName(e.Level),
+		Logger:  e.LoggerName,
+		Message: e.Message,
+		Stack:   e.Stack,
+		level:   e.Level,
+	}
+	if e.Caller.Defined {
+		entry.Caller = e.Caller.TrimmedPath()
+	}
+	if len(fields) > 0 {
+		enc := zapcore.NewMapObjectEncoder()
+		for _, f := range fields {
+			f.AddTo(enc)
+		}
+		entry.Fields = enc.Fields
+	}
+
+	r.mutex.Lock()
+	r.entries[r.next] = entry
+	r.next = (r.next + 1) % len(r.entries)
+	if r.count < len(r.entries) {
+		r.count++
+	}
+	r.mutex.Unlock()
+}
+
+// SetRingBuffer sets the ring buffer that captures the entries written by
+// the loggers of the logging system. A nil buffer stops the capture.
+func (s *Logging) SetRingBuffer(r *RingBuffer) {
+	s.ring.Store(ringHolder{r})
+}
+
+// RingBuffer returns the ring buffer set with SetRingBuffer or nil.
+func (s *Logging) RingBuffer() *RingBuffer {
+	holder, _ := s.ring.Load().(ringHolder)
+	return holder.buffer
+}
+
+// RingEntries returns the entries of the ring buffer selected by the filter.
+// ErrNoRingBuffer is returned when no ring buffer has been set.
+func (s *Logging) RingEntries(filter RingFilter) ([]RingEntry, error) {
+	r := s.RingBuffer()
+	if r == nil {
+		return nil, ErrNoRingBuffer
+	}
+	if filter.Level != "" && !IsValidLevel(filter.Level) {
+		return nil, errors.Errorf("invalid level: %s", filter.Level)
+	}
+	return r.Entries(filter), nil
+}
+
+// ringHolder allows a nil ring buffer to be stored in an atomic.Value.
+type ringHolder struct {
+	buffer *RingBuffer
+}
+
+// ringCore is a capture core of the loggers created by a Logging instance.
+// It captures entries at the level of the ring buffer.
+type ringCore struct {
+	logging *Logging
+	fields  []zapcore.Field
+}
+
+func (c *ringCore) Enabled(level zapcore.Level) bool {
+	r := c.logging.RingBuffer()
+	return r != nil && r.Enabled(level)
+}
+
+func (c *ringCore) With(fields []zapcore.Field) zapcore.Core {
+	return &ringCore{
+		logging: c.logging,
+		fields:  append(c.fields[:len(c.fields):len(c.fields)], fields...),
+	}
+}
+
+func (c *ringCore) Check(e zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(e.Level) {
+		return ce.AddCore(e, c)
+	}
+	return ce
+}
+
+func (c *ringCore) Write(e zapcore.Entry, fields []zapcore.Field) error {
+	r := c.logging.RingBuffer()
+	if r == nil {
+		return nil
+	}
+	if len(c.fields) > 0 {
+		fields = append(c.fields[:len(c.fields):len(c.fields)], fields...)
+	}
+	r.add(e, fields)
+	return nil
+}
+
+func (c *ringCore) Sync() error {
+	return nil
+}
+
+// hasLoggerPrefix returns true when the logger is named prefix or is a
+// descendant of the logger named prefix.
+func hasLoggerPrefix(name, prefix string) bool {
+	return name == prefix || strings.HasPrefix(name, prefix+".")
+}
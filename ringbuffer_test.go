@@ -0,0 +1,155 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package flogging_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/redresseur/flogging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func messages(entries []flogging.RingEntry) []string {
+	messages := []string{}
+	for _, e := range entries {
+		messages = append(messages, e.Message)
+	}
+	return messages
+}
+
+func TestLoggingRingBuffer(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logging, err := flogging.New(flogging.Config{
+		LogSpec: "info",
+		Writer:  buf,
+	})
+	require.NoError(t, err)
+
+	_, err = logging.RingEntries(flogging.RingFilter{})
+	assert.Equal(t, flogging.ErrNoRingBuffer, err)
+
+	ring := flogging.NewRingBuffer(3, zapcore.DebugLevel)
+	logging.SetRingBuffer(ring)
+	assert.Equal(t, ring, logging.RingBuffer())
+
+	logger := logging.Logger("gossip").With("peer", "p0")
+	logger.Debugw("captured", "block", 1)
+	logger.Info("written")
+	logging.Logger("ledger").Debug("ledger debug")
+	logger.Debug("newest")
+
+	assert.NotContains(t, buf.String(), "captured")
+	assert.Contains(t, buf.String(), "written")
+
+	entries, err := logging.RingEntries(flogging.RingFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"written", "ledger debug", "newest"}, messages(entries))
+	assert.Equal(t, "info", entries[0].Level)
+	assert.Equal(t, "gossip", entries[0].Logger)
+	assert.Equal(t, map[string]interface{}{"peer": "p0"}, entries[0].Fields)
+	assert.Contains(t, entries[0].Caller, "ringbuffer_test.go")
+
+	logging.SetRingBuffer(nil)
+	logger.Info("not captured")
+	assert.Nil(t, logging.RingBuffer())
+	assert.Equal(t, []string{"written", "ledger debug", "newest"}, messages(ring.Entries(flogging.RingFilter{})))
+}
+
+func TestRingBufferEntries(t *testing.T) {
+	logging, err := flogging.New(flogging.Config{Writer: &bytes.Buffer{}})
+	require.NoError(t, err)
+	ring := flogging.NewRingBuffer(10, flogging.PayloadLevel)
+	logging.SetRingBuffer(ring)
+
+	gossip := logging.Logger("gossip")
+	comm := logging.Logger("gossip.comm")
+	gossiper := logging.Logger("gossiper")
+	gossip.Debug("gossip debug")
+	comm.Warn("comm warn")
+	gossiper.Info("gossiper info")
+	since := time.Now()
+	time.Sleep(time.Millisecond)
+	gossip.Error("gossip error")
+	comm.Info("comm info")
+
+	tests := []struct {
+		name     string
+		filter   flogging.RingFilter
+		expected []string
+	}{
+		{"all", flogging.RingFilter{}, []string{"gossip debug", "comm warn", "gossiper info", "gossip error", "comm info"}},
+		{"level", flogging.RingFilter{Level: "warn"}, []string{"comm warn", "gossip error"}},
+		{"logger", flogging.RingFilter{Logger: "gossip"}, []string{"gossip debug", "comm warn", "gossip error", "comm info"}},
+		{"child logger", flogging.RingFilter{Logger: "gossip.comm"}, []string{"comm warn", "comm info"}},
+		{"since", flogging.RingFilter{Since: since}, []string{"gossip error", "comm info"}},
+		{"contains", flogging.RingFilter{Contains: "info"}, []string{"gossiper info", "comm info"}},
+		{"limit", flogging.RingFilter{Limit: 2}, []string{"gossip error", "comm info"}},
+		{"combined", flogging.RingFilter{Logger: "gossip", Level: "info", Limit: 2}, []string{"gossip error", "comm info"}},
+		{"none", flogging.RingFilter{Logger: "ledger"}, []string{}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, messages(ring.Entries(tc.filter)))
+		})
+	}
+
+	_, err = logging.RingEntries(flogging.RingFilter{Level: "loud"})
+	assert.EqualError(t, err, "invalid level: loud")
+
+	ring.Clear()
+	assert.Empty(t, ring.Entries(flogging.RingFilter{}))
+}
+
+func TestRingBufferSetLevel(t *testing.T) {
+	logging, err := flogging.New(flogging.Config{LogSpec: "error", Writer: &bytes.Buffer{}})
+	require.NoError(t, err)
+	ring := flogging.NewRingBuffer(10, zapcore.WarnLevel)
+	logging.SetRingBuffer(ring)
+	assert.Equal(t, zapcore.WarnLevel, ring.Level())
+
+	logger := logging.Logger("ring")
+	logger.Info("dropped")
+	logger.Warn("warn")
+	ring.SetLevel(zapcore.DebugLevel)
+	logger.Debug("debug")
+
+	assert.Equal(t, []string{"warn", "debug"}, messages(ring.Entries(flogging.RingFilter{})))
+}
+
+func TestRingBufferIsEnabledFor(t *testing.T) {
+	logging, err := flogging.New(flogging.Config{
+		LogSpec: "gossip.comm=debug:info",
+		Writer:  &bytes.Buffer{},
+	})
+	require.NoError(t, err)
+
+	gossip := logging.Logger("gossip")
+	comm := gossip.Named("comm")
+	assert.False(t, gossip.IsEnabledFor(zapcore.DebugLevel))
+	assert.True(t, comm.IsEnabledFor(zapcore.DebugLevel))
+	assert.False(t, gossip.Zap().Core().Enabled(flogging.PayloadLevel))
+
+	logging.SetRingBuffer(flogging.NewRingBuffer(10, flogging.PayloadLevel))
+	assert.True(t, gossip.Zap().Core().Enabled(flogging.PayloadLevel), "captured levels must reach the ring buffer")
+	assert.False(t, gossip.IsEnabledFor(zapcore.DebugLevel))
+	assert.False(t, gossip.With("peer", "p0").IsEnabledFor(zapcore.DebugLevel))
+	assert.True(t, gossip.IsEnabledFor(zapcore.InfoLevel))
+	assert.True(t, comm.IsEnabledFor(zapcore.DebugLevel))
+	assert.False(t, comm.IsEnabledFor(flogging.PayloadLevel))
+
+	gossip.Debug("captured")
+	entries, err := logging.RingEntries(flogging.RingFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"captured"}, messages(entries))
+
+	logging.SetRingBuffer(nil)
+	assert.False(t, gossip.Zap().Core().Enabled(flogging.PayloadLevel))
+}
@@ -0,0 +1,235 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package flogging
+
+import (
+	"runtime"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap/zapcore"
+)
+
+// The sources of logging specs recorded in the spec history.
+const (
+	SpecSourceDefault   = "default"   // the default spec applied by Apply
+	SpecSourceEnv       = "env"       // the FABRIC_LOGGING_SPEC environment variable
+	SpecSourceConfig    = "config"    // the LogSpec of a Config
+	SpecSourceAPI       = "api"       // a call to ActivateSpec
+	SpecSourceHTTPAdmin = "httpadmin" // the httpadmin handlers
+	SpecSourceGRPCAdmin = "grpcadmin" // the grpcadmin service
+	SpecSourceRollback  = "rollback"  // a call to Rollback
+	SpecSourceExpiry    = "expiry"    // the expiry of an override
+)
+
+// The actions recorded in the spec history for changes to the overrides.
+const (
+	OverrideSet    = "set"    // an override was activated
+	OverrideClear  = "clear"  // the overrides were removed
+	OverrideExpire = "expire" // an override expired
+)
+
+// specHistoryLimit is the number of spec changes retained in the history.
+const specHistoryLimit = 32
+
+// SpecChange records the activation of a logging spec.
+type SpecChange struct {
+	// ID identifies the change for Rollback. IDs increase with every change.
+	ID uint64 `json:"id"`
+	// Time is when the spec was activated.
+	Time time.Time `json:"time"`
+	// Spec is the logging spec as it was provided or, for changes to the
+	// overrides, the normalized spec that was active after the change.
+	Spec string `json:"spec"`
+	// Source describes where the spec came from.
+	Source string `json:"source"`
+	// Caller identifies who activated the spec when it is known.
+	Caller string `json:"caller,omitempty"`
+	// Action is set when the change activated, cleared or expired an
+	// override.
+	Action string `json:"action,omitempty"`
+	// Override is the spec of the override that was activated or expired.
+	Override string `json:"override,omitempty"`
+}
+
+// ActivateSpec activates a logging spec and records it in the spec history
+// with the location of the caller.
+func (s *Logging) ActivateSpec(spec string) error {
+	return s.ActivateSpecFrom(spec, SpecSourceAPI, callerLocation(1))
+}
+
+// ActivateSpecFrom activates a logging spec and records it in the spec
+// history with the provided source and caller.
+func (s *Logging) ActivateSpecFrom(spec, source, caller string) error {
+	_, err := s.ReplaceSpecFrom(spec, "", source, caller)
+	return err
+}
+
+// ReplaceSpec activates a logging spec when it matches the version of the
+// active spec and records it in the spec history with the location of the
+// caller. The resulting spec is returned with its version.
+func (s *Logging) ReplaceSpec(spec, version string) (VersionedSpec, error) {
+	return s.ReplaceSpecFrom(spec, version, SpecSourceAPI, callerLocation(1))
+}
+
+// ReplaceSpecFrom activates a logging spec when it matches the version of
+// the active spec and records it in the spec history with the provided
+// source and caller. The resulting spec is returned with its version.
+func (s *Logging) ReplaceSpecFrom(spec, version, source, caller string) (VersionedSpec, error) {
+	s.historyMutex.Lock()
+	result, change, err := s.LoggerLevels.activateSpec(spec, version)
+	if err != nil {
+		s.historyMutex.Unlock()
+		return VersionedSpec{}, err
+	}
+	s.recordChange(SpecChange{Spec: spec, Source: source, Caller: caller})
+	s.historyMutex.Unlock()
+
+	// subscribers may use the history or change the spec again
+	s.LoggerLevels.notify(change)
+	return result, nil
+}
+
+// PatchSpec applies a patch to the active spec and records the resulting
+// spec in the spec history with the location of the caller.
+func (s *Logging) PatchSpec(patch SpecPatch, version string) (VersionedSpec, error) {
+	return s.PatchSpecFrom(patch, version, SpecSourceAPI, callerLocation(1))
+}
+
+// PatchSpecFrom applies a patch to the active spec and records the resulting
+// spec in the spec history with the provided source and caller.
+func (s *Logging) PatchSpecFrom(patch SpecPatch, version, source, caller string) (VersionedSpec, error) {
+	s.historyMutex.Lock()
+	result, change, err := s.LoggerLevels.patchSpec(patch, version)
+	if err != nil {
+		s.historyMutex.Unlock()
+		return VersionedSpec{}, err
+	}
+	s.recordChange(SpecChange{Spec: s.LoggerLevels.baseSpec(), Source: source, Caller: caller})
+	s.historyMutex.Unlock()
+
+	s.LoggerLevels.notify(change)
+	return result, nil
+}
+
+// OverrideSpec activates a temporary logging spec and records the override
+// in the spec history with the location of the caller. The expiry of the
+// override is recorded as well.
+func (s *Logging) OverrideSpec(spec string, ttl time.Duration) error {
+	return s.OverrideSpecFrom(spec, ttl, SpecSourceAPI, callerLocation(1))
+}
+
+// OverrideSpecFrom activates a temporary logging spec and records the
+// override in the spec history with the provided source and caller.
+func (s *Logging) OverrideSpecFrom(spec string, ttl time.Duration, source, caller string) error {
+	s.historyMutex.Lock()
+	change, err := s.LoggerLevels.overrideSpec(spec, ttl, s.expireOverride)
+	if err != nil {
+		s.historyMutex.Unlock()
+		return err
+	}
+	s.recordChange(SpecChange{Spec: s.LoggerLevels.Spec(), Source: source, Caller: caller, Action: OverrideSet, Override: spec})
+	s.historyMutex.Unlock()
+
+	s.LoggerLevels.notify(change)
+	return nil
+}
+
+// ClearOverrides removes all overrides and records the removal in the spec
+// history with the location of the caller.
+func (s *Logging) ClearOverrides() {
+	s.ClearOverridesFrom(SpecSourceAPI, callerLocation(1))
+}
+
+// ClearOverridesFrom removes all overrides and records the removal in the
+// spec history with the provided source and caller. Nothing is recorded when
+// there are no overrides.
+func (s *Logging) ClearOverridesFrom(source, caller string) {
+	s.historyMutex.Lock()
+	change, ok := s.LoggerLevels.clearOverrides()
+	if !ok {
+		s.historyMutex.Unlock()
+		return
+	}
+	s.recordChange(SpecChange{Spec: s.LoggerLevels.Spec(), Source: source, Caller: caller, Action: OverrideClear})
+	s.historyMutex.Unlock()
+
+	s.LoggerLevels.notify(change)
+}
+
+// expireOverride removes an override once its duration has elapsed and
+// records the expiry in the spec history.
+func (s *Logging) expireOverride(o *levelOverride) {
+	s.historyMutex.Lock()
+	change, ok, err := s.LoggerLevels.removeOverride(o)
+	if !ok {
+		s.historyMutex.Unlock()
+		return
+	}
+	s.recordChange(SpecChange{Spec: s.LoggerLevels.Spec(), Source: SpecSourceExpiry, Action: OverrideExpire, Override: o.spec})
+	s.historyMutex.Unlock()
+
+	if err != nil {
+		s.Logger("flogging").Warnw("removed the overrides that could not be combined with the spec", "expired", o.spec, "error", err)
+	}
+	s.LoggerLevels.notify(change)
+}
+
+// recordChange appends a change to the spec history with a new ID and the
+// current time. The oldest changes are discarded once the history is full.
+// The caller must hold the history mutex.
+func (s *Logging) recordChange(change SpecChange) {
+	s.historyID++
+	change.ID = s.historyID
+	change.Time = time.Now()
+	s.history = append(s.history, change)
+	if len(s.history) > specHistoryLimit {
+		s.history = append([]SpecChange(nil), s.history[len(s.history)-specHistoryLimit:]...)
+	}
+}
+
+// SpecHistory returns the retained spec changes from the oldest to the most
+// recent.
+func (s *Logging) SpecHistory() []SpecChange {
+	s.historyMutex.Lock()
+	defer s.historyMutex.Unlock()
+
+	return append([]SpecChange(nil), s.history...)
+}
+
+// Rollback activates the spec of a change from the history again. The
+// rollback is recorded as a new change. Activations of overrides cannot be
+// rolled back as that would make the override permanent; the spec restored
+// by clearing or expiring overrides can.
+func (s *Logging) Rollback(id uint64, caller string) error {
+	var spec string
+	var found bool
+	for _, change := range s.SpecHistory() {
+		if change.ID == id {
+			if change.Action == OverrideSet {
+				return errors.Errorf("spec history entry %d activated an override", id)
+			}
+			spec, found = change.Spec, true
+			break
+		}
+	}
+	if !found {
+		return errors.Errorf("spec history entry %d not found", id)
+	}
+
+	return s.ActivateSpecFrom(spec, SpecSourceRollback, caller)
+}
+
+// callerLocation returns the trimmed file and line of the caller skip frames
+// above the function calling callerLocation.
+func callerLocation(skip int) string {
+	caller := zapcore.NewEntryCaller(runtime.Caller(skip + 1))
+	if !caller.Defined {
+		return ""
+	}
+	return caller.TrimmedPath()
+}
@@ -0,0 +1,229 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package flogging_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/redresseur/flogging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSpecHistory(t *testing.T) {
+	logging, err := flogging.New(flogging.Config{LogSpec: "warn"})
+	require.NoError(t, err)
+
+	err = logging.ActivateSpec("gossip=debug:info")
+	require.NoError(t, err)
+	err = logging.ActivateSpecFrom("error", flogging.SpecSourceHTTPAdmin, "admin")
+	require.NoError(t, err)
+	err = logging.ActivateSpecFrom("bogus", flogging.SpecSourceHTTPAdmin, "admin")
+	assert.EqualError(t, err, "invalid logging specification 'bogus': bad segment 'bogus'")
+
+	history := logging.SpecHistory()
+	require.Len(t, history, 3)
+	assert.Equal(t, "warn", history[0].Spec)
+	assert.Equal(t, flogging.SpecSourceConfig, history[0].Source)
+	assert.Empty(t, history[0].Caller)
+	assert.Equal(t, "gossip=debug:info", history[1].Spec)
+	assert.Equal(t, flogging.SpecSourceAPI, history[1].Source)
+	assert.Regexp(t, `(^|/)spechistory_test\.go:\d+$`, history[1].Caller)
+	assert.Equal(t, "error", history[2].Spec)
+	assert.Equal(t, flogging.SpecSourceHTTPAdmin, history[2].Source)
+	assert.Equal(t, "admin", history[2].Caller)
+	for i := 1; i < len(history); i++ {
+		assert.True(t, history[i].ID > history[i-1].ID)
+		assert.False(t, history[i].Time.Before(history[i-1].Time))
+	}
+
+	err = logging.Rollback(history[1].ID, "operator")
+	require.NoError(t, err)
+	assert.Equal(t, "gossip=debug:info", logging.Spec())
+	assert.Equal(t, zapcore.DebugLevel, logging.Level("gossip"))
+
+	history = logging.SpecHistory()
+	require.Len(t, history, 4)
+	assert.Equal(t, flogging.SpecChange{
+		ID:     history[2].ID + 1,
+		Time:   history[3].Time,
+		Spec:   "gossip=debug:info",
+		Source: flogging.SpecSourceRollback,
+		Caller: "operator",
+	}, history[3])
+
+	err = logging.Rollback(1000, "operator")
+	assert.EqualError(t, err, "spec history entry 1000 not found")
+	assert.Len(t, logging.SpecHistory(), 4)
+}
+
+func TestSpecHistorySources(t *testing.T) {
+	defer os.Unsetenv("FABRIC_LOGGING_SPEC")
+
+	logging, err := flogging.New(flogging.Config{})
+	require.NoError(t, err)
+	os.Setenv("FABRIC_LOGGING_SPEC", "debug")
+	err = logging.Apply(flogging.Config{})
+	require.NoError(t, err)
+
+	history := logging.SpecHistory()
+	require.Len(t, history, 2)
+	assert.Equal(t, "info", history[0].Spec)
+	assert.Equal(t, flogging.SpecSourceDefault, history[0].Source)
+	assert.Equal(t, "debug", history[1].Spec)
+	assert.Equal(t, flogging.SpecSourceEnv, history[1].Source)
+}
+
+func TestSpecHistoryLimit(t *testing.T) {
+	logging, err := flogging.New(flogging.Config{})
+	require.NoError(t, err)
+
+	for i := 0; i < 40; i++ {
+		err := logging.ActivateSpec(fmt.Sprintf("logger%d=debug", i))
+		require.NoError(t, err)
+	}
+
+	history := logging.SpecHistory()
+	require.Len(t, history, 32)
+	assert.Equal(t, "logger8=debug", history[0].Spec)
+	assert.Equal(t, "logger39=debug", history[31].Spec)
+
+	err = logging.Rollback(1, "")
+	assert.EqualError(t, err, "spec history entry 1 not found")
+}
+
+func TestSpecHistoryPatch(t *testing.T) {
+	logging, err := flogging.New(flogging.Config{LogSpec: "a=info:warn"})
+	require.NoError(t, err)
+
+	_, err = logging.PatchSpecFrom(flogging.SpecPatch{Set: map[string]string{"b": "debug"}}, "", flogging.SpecSourceHTTPAdmin, "admin")
+	require.NoError(t, err)
+	_, err = logging.PatchSpec(flogging.SpecPatch{Remove: []string{"a"}}, "")
+	require.NoError(t, err)
+	_, err = logging.PatchSpec(flogging.SpecPatch{Default: "bogus"}, "")
+	assert.Error(t, err)
+
+	history := logging.SpecHistory()
+	require.Len(t, history, 3)
+	assert.Equal(t, "a=info:b=debug:warn", history[1].Spec)
+	assert.Equal(t, flogging.SpecSourceHTTPAdmin, history[1].Source)
+	assert.Equal(t, "admin", history[1].Caller)
+	assert.Equal(t, "b=debug:warn", history[2].Spec)
+	assert.Equal(t, flogging.SpecSourceAPI, history[2].Source)
+	assert.Regexp(t, `(^|/)spechistory_test\.go:\d+$`, history[2].Caller)
+}
+
+func TestSpecHistoryReplace(t *testing.T) {
+	logging, err := flogging.New(flogging.Config{LogSpec: "warn"})
+	require.NoError(t, err)
+
+	result, err := logging.ReplaceSpec("a=debug:info", logging.SpecVersion())
+	require.NoError(t, err)
+	assert.Equal(t, logging.VersionedSpec(), result)
+	_, err = logging.ReplaceSpecFrom("error", "0000000000000000", flogging.SpecSourceHTTPAdmin, "admin")
+	assert.Equal(t, flogging.ErrSpecVersionMismatch, err)
+
+	history := logging.SpecHistory()
+	require.Len(t, history, 2)
+	assert.Equal(t, "a=debug:info", history[1].Spec)
+	assert.Equal(t, flogging.SpecSourceAPI, history[1].Source)
+	assert.Regexp(t, `(^|/)spechistory_test\.go:\d+$`, history[1].Caller)
+}
+
+func TestSpecHistorySubscriber(t *testing.T) {
+	logging, err := flogging.New(flogging.Config{LogSpec: "info"})
+	require.NoError(t, err)
+
+	var histories [][]flogging.SpecChange
+	cancel := logging.Subscribe(func(change flogging.LevelChange) {
+		histories = append(histories, logging.SpecHistory())
+		if change.NewSpec == "error" {
+			assert.NoError(t, logging.Rollback(1, "subscriber"))
+		}
+	})
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assert.NoError(t, logging.ActivateSpec("debug"))
+		_, err := logging.PatchSpec(flogging.SpecPatch{Default: "error"}, "")
+		assert.NoError(t, err)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscriber deadlocked")
+	}
+
+	require.Len(t, histories, 3)
+	require.Len(t, histories[0], 2)
+	assert.Equal(t, "debug", histories[0][1].Spec)
+	require.Len(t, histories[1], 3)
+	assert.Equal(t, "error", histories[1][2].Spec)
+	require.Len(t, histories[2], 4)
+	assert.Equal(t, flogging.SpecSourceRollback, histories[2][3].Source)
+	assert.Equal(t, "info", logging.Spec())
+}
+
+func TestSpecHistoryOverrides(t *testing.T) {
+	logging, err := flogging.New(flogging.Config{LogSpec: "warn"})
+	require.NoError(t, err)
+
+	err = logging.OverrideSpec("gossip=debug", time.Hour)
+	require.NoError(t, err)
+	err = logging.OverrideSpecFrom("ledger=debug", 50*time.Millisecond, flogging.SpecSourceHTTPAdmin, "admin")
+	require.NoError(t, err)
+	err = logging.OverrideSpecFrom("bogus", time.Hour, flogging.SpecSourceHTTPAdmin, "admin")
+	assert.EqualError(t, err, "invalid logging specification 'bogus': bad segment 'bogus'")
+
+	assert.Eventually(t, func() bool { return len(logging.Overrides()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, zapcore.WarnLevel, logging.Level("ledger"))
+
+	logging.ClearOverridesFrom(flogging.SpecSourceHTTPAdmin, "operator")
+	logging.ClearOverrides()
+	assert.Equal(t, zapcore.WarnLevel, logging.Level("gossip"))
+
+	history := logging.SpecHistory()
+	require.Len(t, history, 5)
+	assert.Empty(t, history[0].Action)
+	assert.Empty(t, history[0].Override)
+	assert.Equal(t, "gossip=debug:warn", history[1].Spec)
+	assert.Equal(t, flogging.SpecSourceAPI, history[1].Source)
+	assert.Regexp(t, `(^|/)spechistory_test\.go:\d+$`, history[1].Caller)
+	assert.Equal(t, flogging.OverrideSet, history[1].Action)
+	assert.Equal(t, "gossip=debug", history[1].Override)
+	assert.Equal(t, "gossip=debug:ledger=debug:warn", history[2].Spec)
+	assert.Equal(t, flogging.SpecSourceHTTPAdmin, history[2].Source)
+	assert.Equal(t, "admin", history[2].Caller)
+	assert.Equal(t, flogging.OverrideSet, history[2].Action)
+	assert.Equal(t, "ledger=debug", history[2].Override)
+	assert.Equal(t, "gossip=debug:warn", history[3].Spec)
+	assert.Equal(t, flogging.SpecSourceExpiry, history[3].Source)
+	assert.Empty(t, history[3].Caller)
+	assert.Equal(t, flogging.OverrideExpire, history[3].Action)
+	assert.Equal(t, "ledger=debug", history[3].Override)
+	assert.Equal(t, "warn", history[4].Spec)
+	assert.Equal(t, flogging.SpecSourceHTTPAdmin, history[4].Source)
+	assert.Equal(t, "operator", history[4].Caller)
+	assert.Equal(t, flogging.OverrideClear, history[4].Action)
+	assert.Empty(t, history[4].Override)
+
+	err = logging.Rollback(history[1].ID, "operator")
+	assert.EqualError(t, err, fmt.Sprintf("spec history entry %d activated an override", history[1].ID))
+	assert.Len(t, logging.SpecHistory(), 5)
+
+	err = logging.Rollback(history[3].ID, "operator")
+	require.NoError(t, err)
+	assert.Equal(t, "gossip=debug:warn", logging.Spec())
+	assert.Equal(t, zapcore.DebugLevel, logging.Level("gossip"))
+	assert.Equal(t, zapcore.WarnLevel, logging.Level("ledger"))
+}
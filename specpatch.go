@@ -0,0 +1,207 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package flogging
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// ErrSpecVersionMismatch is returned by PatchSpec and ReplaceSpec when the
+// active spec no longer has the expected version.
+var ErrSpecVersionMismatch = errors.New("logging spec version mismatch")
+
+// A VersionedSpec is the active logging spec with the version returned by
+// SpecVersion at the time it was read.
+type VersionedSpec struct {
+	Spec    string `json:"spec"`
+	Version string `json:"version"`
+}
+
+// A SpecPatch describes changes to individual segments of the active logging
+// spec. The segments that are not mentioned are preserved.
+type SpecPatch struct {
+	// Set maps logger selectors to the level they should be assigned. A
+	// selector may be a logger name, a glob, or a regular expression.
+	Set map[string]string `json:"set,omitempty"`
+	// Remove lists the logger selectors to remove from the spec.
+	Remove []string `json:"remove,omitempty"`
+	// Default is the new default level. The default level is preserved when
+	// it is empty.
+	Default string `json:"default,omitempty"`
+}
+
+// SpecVersion returns the version of the spec activated with ActivateSpec or
+// PatchSpec. The version is derived from the normalized spec and changes
+// whenever the levels the spec assigns change. Overrides do not affect the
+// version.
+func (l *LoggerLevels) SpecVersion() string {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	return l.specVersion()
+}
+
+// VersionedSpec returns the active spec with the version of the spec
+// activated with ActivateSpec or PatchSpec. Both are read atomically.
+func (l *LoggerLevels) VersionedSpec() VersionedSpec {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	return VersionedSpec{Spec: l.load().String(), Version: l.specVersion()}
+}
+
+// PatchSpec applies a patch to the spec activated with ActivateSpec and
+// returns the resulting spec with its version. When version is not empty,
+// the patch is only applied if it matches the version of the active spec;
+// otherwise ErrSpecVersionMismatch is returned.
+//
+// Selectors are removed before the new levels are set. A glob or regular
+// expression that is set again keeps its position in the spec while new
+// ones are appended, taking precedence over the existing ones.
+func (l *LoggerLevels) PatchSpec(patch SpecPatch, version string) (VersionedSpec, error) {
+	result, change, err := l.patchSpec(patch, version)
+	if err != nil {
+		return VersionedSpec{}, err
+	}
+
+	l.notify(change)
+	return result, nil
+}
+
+// ReplaceSpec activates a logging spec as ActivateSpec does and returns the
+// resulting spec with its version. When version is not empty, the spec is
+// only activated if it matches the version of the active spec; otherwise
+// ErrSpecVersionMismatch is returned.
+func (l *LoggerLevels) ReplaceSpec(spec, version string) (VersionedSpec, error) {
+	result, change, err := l.activateSpec(spec, version)
+	if err != nil {
+		return VersionedSpec{}, err
+	}
+
+	l.notify(change)
+	return result, nil
+}
+
+// patchSpec applies a patch without notifying the subscribers. The change is
+// returned so that the caller can notify them once it has released its own
+// locks.
+func (l *LoggerLevels) patchSpec(patch SpecPatch, version string) (VersionedSpec, LevelChange, error) {
+	if patch.Default != "" && !IsValidLevel(patch.Default) {
+		return VersionedSpec{}, LevelChange{}, errors.Errorf("invalid spec patch: bad default level '%s'", patch.Default)
+	}
+
+	selectors := make([]string, 0, len(patch.Set))
+	for selector := range patch.Set {
+		selectors = append(selectors, selector)
+	}
+	sort.Strings(selectors)
+
+	var updates []*levelSpec
+	for _, selector := range selectors {
+		update, err := parseSpec(fmt.Sprintf("%s=%s", selector, patch.Set[selector]))
+		if err != nil {
+			return VersionedSpec{}, LevelChange{}, err
+		}
+		updates = append(updates, update)
+	}
+
+	l.mutex.Lock()
+	if version != "" && version != l.specVersion() {
+		l.mutex.Unlock()
+		return VersionedSpec{}, LevelChange{}, ErrSpecVersionMismatch
+	}
+
+	ls, err := parseSpec(l.spec)
+	if err != nil {
+		l.mutex.Unlock()
+		return VersionedSpec{}, LevelChange{}, err
+	}
+	for _, selector := range patch.Remove {
+		delete(ls.specs, selector)
+		delete(ls.excludes, selector)
+		ls.removePattern(selector)
+	}
+	for _, update := range updates {
+		for selector, level := range update.specs {
+			ls.specs[selector] = level
+			if negated, ok := update.excludes[selector]; ok {
+				ls.excludes[selector] = negated
+			} else {
+				delete(ls.excludes, selector)
+			}
+		}
+		for _, p := range update.patterns {
+			ls.setPattern(p)
+		}
+	}
+	if patch.Default != "" {
+		ls.defaultLevel = NameToLevel(patch.Default)
+	}
+
+	spec, base := ls.String(), ls
+	if len(l.overrides) > 0 {
+		if ls, err = effectiveSpec(spec, l.overrides); err != nil {
+			l.mutex.Unlock()
+			return VersionedSpec{}, LevelChange{}, err
+		}
+	}
+	l.spec, l.base = spec, base
+	change := l.apply(ls)
+	result := VersionedSpec{Spec: change.NewSpec, Version: l.specVersion()}
+	l.mutex.Unlock()
+
+	return result, change, nil
+}
+
+// baseSpec returns the spec activated with ActivateSpec or PatchSpec.
+func (l *LoggerLevels) baseSpec() string {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	return l.spec
+}
+
+// specVersion computes the version of the spec activated with ActivateSpec
+// or PatchSpec. The caller must hold the mutex.
+func (l *LoggerLevels) specVersion() string {
+	normalized := l.spec
+	if ls, err := parseSpec(l.spec); err == nil {
+		normalized = ls.String()
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(normalized))
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// removePattern removes the glob or regular expression with the provided
+// selector.
+func (ls *levelSpec) removePattern(selector string) {
+	var patterns []levelPattern
+	for _, p := range ls.patterns {
+		if p.selector != selector {
+			patterns = append(patterns, p)
+		}
+	}
+	ls.patterns = patterns
+}
+
+// setPattern replaces the glob or regular expression with the same selector
+// or appends the pattern when there is none.
+func (ls *levelSpec) setPattern(pattern levelPattern) {
+	for i, p := range ls.patterns {
+		if p.selector == pattern.selector {
+			ls.patterns[i] = pattern
+			return
+		}
+	}
+	ls.patterns = append(ls.patterns, pattern)
+}
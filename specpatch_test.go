@@ -0,0 +1,193 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package flogging_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/redresseur/flogging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLoggerLevelsPatchSpec(t *testing.T) {
+	var tests = []struct {
+		name   string
+		spec   string
+		patch  flogging.SpecPatch
+		output string
+	}{
+		{
+			name:   "set",
+			spec:   "a=info:b=warn:debug",
+			patch:  flogging.SpecPatch{Set: map[string]string{"b": "error", "c.": "payload"}},
+			output: "a=info:b=error:c.=payload:debug",
+		},
+		{
+			name:   "remove",
+			spec:   "a=info:b=warn:*.c=error:debug",
+			patch:  flogging.SpecPatch{Remove: []string{"a", "*.c", "missing"}},
+			output: "b=warn:debug",
+		},
+		{
+			name:   "default",
+			spec:   "a=info:debug",
+			patch:  flogging.SpecPatch{Default: "WARNING"},
+			output: "a=info:warn",
+		},
+		{
+			name: "patterns",
+			spec: "*.a=info:re:^b:c$=warn:d.**=error",
+			patch: flogging.SpecPatch{
+				Set:    map[string]string{"re:^b:c$": "debug", "e.*": "fatal"},
+				Remove: []string{"d.**"},
+			},
+			output: "*.a=info:re:^b:c$=debug:e.*=fatal:info",
+		},
+		{
+			name:   "remove and set",
+			spec:   "a=info:*.b=warn:*.c=error",
+			patch:  flogging.SpecPatch{Set: map[string]string{"*.b": "debug"}, Remove: []string{"*.b"}},
+			output: "a=info:*.c=error:*.b=debug:info",
+		},
+		{
+			name:   "empty",
+			spec:   "a=info:fatal",
+			patch:  flogging.SpecPatch{},
+			output: "a=info:fatal",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ll := &flogging.LoggerLevels{}
+			err := ll.ActivateSpec(tc.spec)
+			require.NoError(t, err)
+
+			result, err := ll.PatchSpec(tc.patch, ll.SpecVersion())
+			require.NoError(t, err)
+			assert.Equal(t, tc.output, ll.Spec())
+			assert.Equal(t, flogging.VersionedSpec{Spec: tc.output, Version: ll.SpecVersion()}, result)
+
+			expected := &flogging.LoggerLevels{}
+			err = expected.ActivateSpec(tc.output)
+			require.NoError(t, err)
+			assert.Equal(t, expected.SpecVersion(), result.Version)
+		})
+	}
+}
+
+func TestLoggerLevelsPatchSpecLevels(t *testing.T) {
+	ll := &flogging.LoggerLevels{}
+	err := ll.ActivateSpec("gossip=warn:info")
+	require.NoError(t, err)
+	assert.Equal(t, zapcore.WarnLevel, ll.Level("gossip.comm"))
+
+	_, err = ll.PatchSpec(flogging.SpecPatch{Set: map[string]string{"gossip.comm": "debug"}}, "")
+	require.NoError(t, err)
+	assert.Equal(t, zapcore.DebugLevel, ll.Level("gossip.comm"))
+	assert.Equal(t, zapcore.WarnLevel, ll.Level("gossip.state"))
+	assert.True(t, ll.Enabled(zapcore.DebugLevel))
+}
+
+func TestLoggerLevelsPatchSpecNegated(t *testing.T) {
+	ll := &flogging.LoggerLevels{}
+	err := ll.ActivateSpec("gossip=warn:info")
+	require.NoError(t, err)
+
+	_, err = ll.PatchSpec(flogging.SpecPatch{Set: map[string]string{"gossip,!gossip.comm": "debug"}}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "gossip,!gossip.comm=debug:info", ll.Spec())
+	assert.Equal(t, zapcore.DebugLevel, ll.Level("gossip.state"))
+	assert.Equal(t, zapcore.InfoLevel, ll.Level("gossip.comm"))
+
+	_, err = ll.PatchSpec(flogging.SpecPatch{Set: map[string]string{"gossip": "error"}}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "gossip=error:info", ll.Spec())
+	assert.Equal(t, zapcore.ErrorLevel, ll.Level("gossip.comm"))
+
+	_, err = ll.PatchSpec(flogging.SpecPatch{Set: map[string]string{"!gossip": "error"}}, "")
+	assert.EqualError(t, err, "invalid logging specification '!gossip=error': only negated loggers in segment '!gossip=error'")
+}
+
+func TestLoggerLevelsPatchSpecVersion(t *testing.T) {
+	ll := &flogging.LoggerLevels{}
+	err := ll.ActivateSpec("a=info:debug")
+	require.NoError(t, err)
+
+	version := ll.SpecVersion()
+	assert.Regexp(t, `^[0-9a-f]{16}$`, version)
+
+	err = ll.ActivateSpec("debug:a=INFO")
+	require.NoError(t, err)
+	assert.Equal(t, version, ll.SpecVersion(), "equivalent specs have the same version")
+
+	err = ll.OverrideSpec("b=error", time.Hour)
+	require.NoError(t, err)
+	defer ll.ClearOverrides()
+	assert.Equal(t, version, ll.SpecVersion(), "overrides do not change the version")
+
+	updated, err := ll.PatchSpec(flogging.SpecPatch{Set: map[string]string{"a": "warn"}}, version)
+	require.NoError(t, err)
+	assert.NotEqual(t, version, updated.Version)
+	assert.Equal(t, "a=warn:b=error:debug", updated.Spec)
+	assert.Equal(t, "a=warn:b=error:debug", ll.Spec())
+
+	_, err = ll.PatchSpec(flogging.SpecPatch{Set: map[string]string{"a": "fatal"}}, version)
+	assert.Equal(t, flogging.ErrSpecVersionMismatch, err)
+	assert.Equal(t, "a=warn:b=error:debug", ll.Spec())
+	assert.Equal(t, updated.Version, ll.SpecVersion())
+}
+
+func TestLoggerLevelsReplaceSpec(t *testing.T) {
+	ll := &flogging.LoggerLevels{}
+	err := ll.ActivateSpec("a=info:debug")
+	require.NoError(t, err)
+	version := ll.SpecVersion()
+
+	result, err := ll.ReplaceSpec("a=warn:info", version)
+	require.NoError(t, err)
+	assert.Equal(t, flogging.VersionedSpec{Spec: "a=warn:info", Version: ll.SpecVersion()}, result)
+	assert.NotEqual(t, version, result.Version)
+
+	_, err = ll.ReplaceSpec("a=error:info", version)
+	assert.Equal(t, flogging.ErrSpecVersionMismatch, err)
+	assert.Equal(t, "a=warn:info", ll.Spec())
+
+	result, err = ll.ReplaceSpec("a=error:info", "")
+	require.NoError(t, err)
+	assert.Equal(t, "a=error:info", result.Spec)
+
+	_, err = ll.ReplaceSpec("bogus", "")
+	assert.EqualError(t, err, "invalid logging specification 'bogus': bad segment 'bogus'")
+}
+
+func TestLoggerLevelsPatchSpecErrors(t *testing.T) {
+	var tests = []struct {
+		patch flogging.SpecPatch
+		err   string
+	}{
+		{patch: flogging.SpecPatch{Default: "bogus"}, err: "invalid spec patch: bad default level 'bogus'"},
+		{patch: flogging.SpecPatch{Set: map[string]string{"a": "bogus"}}, err: "invalid logging specification 'a=bogus': bad segment 'a=bogus'"},
+		{patch: flogging.SpecPatch{Set: map[string]string{"a*": "info"}}, err: "invalid logging specification 'a*=info': bad logger name 'a*'"},
+		{patch: flogging.SpecPatch{Set: map[string]string{"": "info"}}, err: "invalid logging specification '=info': no logger specified in segment '=info'"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.err, func(t *testing.T) {
+			ll := &flogging.LoggerLevels{}
+			err := ll.ActivateSpec("a=warn:fatal")
+			require.NoError(t, err)
+
+			_, err = ll.PatchSpec(tc.patch, "")
+			assert.EqualError(t, err, tc.err)
+			assert.Equal(t, "a=warn:fatal", ll.Spec())
+		})
+	}
+}
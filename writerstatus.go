@@ -0,0 +1,49 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package flogging
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/redresseur/flogging/output"
+)
+
+// WriterStatus describes the writer log records are written to.
+type WriterStatus struct {
+	// Type is the type of the writer.
+	Type string `json:"type"`
+	// Name is the name of the file when the writer is an *os.File.
+	Name string `json:"name,omitempty"`
+	// Color is true when color escapes are emitted.
+	Color bool `json:"color"`
+	// Rotation is the status of the current file when the writer was created
+	// by output.NewWriter.
+	Rotation *output.Status `json:"rotation,omitempty"`
+}
+
+// WriterStatus returns the status of the writer provided to SetWriter or the
+// Writer field of Config.
+func (s *Logging) WriterStatus() WriterStatus {
+	s.mutex.RLock()
+	w, color := s.output, s.color
+	s.mutex.RUnlock()
+
+	status := WriterStatus{Color: color}
+	if w == nil {
+		return status
+	}
+
+	status.Type = fmt.Sprintf("%T", w)
+	if f, ok := w.(*os.File); ok {
+		status.Name = f.Name()
+	}
+	if rotation, ok := output.GetStatus(w); ok {
+		status.Rotation = &rotation
+	}
+	return status
+}
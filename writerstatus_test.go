@@ -0,0 +1,62 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package flogging_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/redresseur/flogging"
+	"github.com/redresseur/flogging/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggingWriterStatus(t *testing.T) {
+	logging, err := flogging.New(flogging.Config{Writer: &bytes.Buffer{}})
+	require.NoError(t, err)
+	assert.Equal(t, flogging.WriterStatus{Type: "*bytes.Buffer"}, logging.WriterStatus())
+
+	logging.SetWriter(os.Stderr)
+	status := logging.WriterStatus()
+	assert.Equal(t, "*os.File", status.Type)
+	assert.Equal(t, os.Stderr.Name(), status.Name)
+	assert.Nil(t, status.Rotation)
+}
+
+func TestLoggingWriterStatusRotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "writerstatus")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	w, err := output.NewWriter(context.Background(), &output.WriterConfig{
+		Dir:          dir,
+		Prefix:       "peer",
+		Model:        output.SizeModel,
+		MaxSize:      1024,
+		MaxFileCount: 3,
+	})
+	require.NoError(t, err)
+	defer output.Close(w)
+
+	logging, err := flogging.New(flogging.Config{Writer: w})
+	require.NoError(t, err)
+
+	status := logging.WriterStatus()
+	require.NotNil(t, status.Rotation)
+	assert.Equal(t, dir, status.Rotation.Dir)
+	assert.Equal(t, output.SizeModel, status.Rotation.Model)
+	assert.Equal(t, int64(1024), status.Rotation.MaxSize)
+	assert.Equal(t, 3, status.Rotation.MaxFileCount)
+	assert.Equal(t, 0, status.Rotation.Index)
+	assert.Equal(t, dir, filepath.Dir(status.Rotation.Path))
+	assert.Regexp(t, `^peer\d{4}-\d{2}-\d{2}_0000\.log$`, filepath.Base(status.Rotation.Path))
+}
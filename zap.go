@@ -30,6 +30,9 @@ func NewZapLogger(core zapcore.Core, options ...zap.Option) *zap.Logger {
 }
 
 // NewGRPCLogger creates a grpc.Logger that delegates to a zap.Logger.
+//
+// Deprecated: the grpc.Logger interface is deprecated by gRPC and all entries
+// are logged at DEBUG. Use NewGRPCLoggerV2 or Logging.GRPCLogger.
 func NewGRPCLogger(l *zap.Logger) *zapgrpc.Logger {
 	l = l.WithOptions(
 		zap.AddCaller(),
@@ -85,12 +88,27 @@ func (f *FabricLogger) Criticalf(template string, args ...interface{}) { f.s.Err
 func (f *FabricLogger) Notice(args ...interface{})                     { f.s.Infof(formatArgs(args)) }
 func (f *FabricLogger) Noticef(template string, args ...interface{})   { f.s.Infof(template, args...) }
 
-func (f *FabricLogger) Named(name string) *FabricLogger { return &FabricLogger{s: f.s.Named(name)} }
-func (f *FabricLogger) Sync() error                     { return f.s.Sync() }
-func (f *FabricLogger) Zap() *zap.Logger                { return f.s.Desugar() }
+func (f *FabricLogger) Sync() error      { return f.s.Sync() }
+func (f *FabricLogger) Zap() *zap.Logger { return f.s.Desugar() }
+
+func (f *FabricLogger) Named(name string) *FabricLogger {
+	l := f.s.Desugar().Named(name)
+	if lc, ok := l.Core().(*loggerCore); ok {
+		l = l.WithOptions(zap.WrapCore(func(zapcore.Core) zapcore.Core { return lc.named(name) }))
+	}
+	return &FabricLogger{s: l.Sugar()}
+}
 
+// IsEnabledFor returns true when entries at the level are written by the
+// logger. For the loggers of a Logging instance, the level of the logger in
+// the active spec is consulted; levels that are only captured by a ring
+// buffer or a flight recorder are not reported as enabled.
 func (f *FabricLogger) IsEnabledFor(level zapcore.Level) bool {
-	return f.s.Desugar().Core().Enabled(level)
+	core := f.s.Desugar().Core()
+	if lc, ok := core.(*loggerCore); ok {
+		return lc.writeEnabled(level)
+	}
+	return core.Enabled(level)
 }
 
 func (f *FabricLogger) With(args ...interface{}) *FabricLogger {